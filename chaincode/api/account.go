@@ -6,8 +6,10 @@ import (
 	"github.com/hyperledger/fabric-contract-api-go/contractapi"
 	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
 	"github.com/usnistgov/blossom/chaincode/collections"
+	"github.com/usnistgov/blossom/chaincode/ngac/common"
 	events "github.com/usnistgov/blossom/chaincode/ngac/epp"
 	decider "github.com/usnistgov/blossom/chaincode/ngac/pdp"
+	"sort"
 	"strings"
 	"time"
 
@@ -36,6 +38,28 @@ func (b *BlossomSmartContract) RequestAccount(ctx contractapi.TransactionContext
 		return fmt.Errorf("ngac check failed: %w", err)
 	}
 
+	transientInput, err := getAccountTransientInput(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting transient input: %w", err)
+	}
+
+	// the requesting user is the account's system owner, otherwise they could register someone else and lock
+	// themselves out of the account
+	username, err := common.GetUsername(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting user from ctx: %w", err)
+	}
+
+	if transientInput.SystemOwner == "" {
+		if username == transientInput.SystemAdmin || username == transientInput.AcquisitionSpecialist {
+			return fmt.Errorf("account system owner, system admin and acquisition specialist must be different users")
+		}
+
+		transientInput.SystemOwner = username
+	} else if transientInput.SystemOwner != username {
+		return fmt.Errorf("system owner %s must be the requesting user %s", transientInput.SystemOwner, username)
+	}
+
 	accountName, err := accountName(ctx)
 	if err != nil {
 		return fmt.Errorf("error retrieving MSPID from ctx: %w", err)
@@ -55,15 +79,23 @@ func (b *BlossomSmartContract) RequestAccount(ctx contractapi.TransactionContext
 
 	// account public goes on public ledger
 	acctPub := model.AccountPublic{
-		Name:   accountName,
-		MSPID:  mspid,
-		Status: model.PendingApproval,
+		Name:       accountName,
+		MSPID:      mspid,
+		Status:     model.PendingApproval,
+		Department: transientInput.Department,
+		SubAgency:  transientInput.SubAgency,
 	}
 
 	// account private goes on private data collection for the msp
+	// the users are added to the NGAC graph when the account is approved
 	acctPvt := model.AccountPrivate{
-		ATO:    "",
-		Assets: make(map[string]map[string]string),
+		Assets: make(map[string]map[string]model.LicenseLease),
+		Users: map[string]string{
+			transientInput.SystemOwner:           model.SystemOwnerRole,
+			transientInput.SystemAdmin:           model.SystemAdminRole,
+			transientInput.AcquisitionSpecialist: model.AcquisitionSpecialistRole,
+		},
+		PointsOfContact: transientInput.PointsOfContact,
 	}
 
 	// add account public to world state
@@ -84,7 +116,7 @@ func (b *BlossomSmartContract) RequestAccount(ctx contractapi.TransactionContext
 
 	collection := collections.Account(accountName)
 
-	if err = ctx.GetStub().PutPrivateData(collection, model.AccountKey(accountName), pvtBytes); err != nil {
+	if err = putPrivateData(ctx, collection, model.AccountKey(accountName), "RequestAccount", pvtBytes); err != nil {
 		return fmt.Errorf("error putting private data: %w", err)
 	}
 
@@ -100,14 +132,19 @@ func (b *BlossomSmartContract) ApproveAccount(ctx contractapi.TransactionContext
 	// get account private details from PDC to add users to NGAC graph
 	if bytes, err = ctx.GetStub().GetPrivateData(collections.Account(account), model.AccountKey(account)); err != nil {
 		return fmt.Errorf("error getting private data: %w", err)
+	} else if bytes == nil {
+		return fmt.Errorf("account %q has not been requested", account)
+	}
+
+	acctPvt := model.NewAccountPrivate()
+	if err = json.Unmarshal(bytes, acctPvt); err != nil {
+		return fmt.Errorf("error unmarshaling account %q: %w", account, err)
 	}
 
 	if err = decider.CanApproveAccount(ctx); err != nil {
 		return fmt.Errorf("error approving account in NGAC: %w", err)
 	}
 
-	// update account status
-	status := model.PendingATO
 	bytes, err = ctx.GetStub().GetState(model.AccountKey(account))
 	if err != nil {
 		return fmt.Errorf("error getting account %q from world state: %w", account, err)
@@ -118,20 +155,12 @@ func (b *BlossomSmartContract) ApproveAccount(ctx contractapi.TransactionContext
 		return fmt.Errorf("error unmarshaling account %q: %w", account, err)
 	}
 
-	// update status
-	acctPub.Status = status
-
-	// marshal back to json
-	if bytes, err = json.Marshal(acctPub); err != nil {
-		return fmt.Errorf("error marshaling account %q: %w", account, err)
-	}
-
-	// update world state
-	if err = ctx.GetStub().PutState(model.AccountKey(account), bytes); err != nil {
-		return fmt.Errorf("error updating status of account %q: %w", account, err)
+	// update account status
+	if err = putAccountStatus(ctx, acctPub, model.PendingATO, "account approved"); err != nil {
+		return err
 	}
 
-	return events.ProcessApproveAccount(ctx, account)
+	return events.ProcessApproveAccount(ctx, account, acctPvt.Users)
 }
 
 func (b *BlossomSmartContract) UploadATO(ctx contractapi.TransactionContextInterface) error {
@@ -158,6 +187,17 @@ func (b *BlossomSmartContract) UploadATO(ctx contractapi.TransactionContextInter
 		return fmt.Errorf("error uploading ATO for account %s: %w", accountName, err)
 	}
 
+	ato := transientInput.toATO()
+
+	timestamp, err := txTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+
+	if ato.IsExpired(timestamp) {
+		return fmt.Errorf("ato for account %s expired on %s", accountName, ato.ExpirationDate)
+	}
+
 	bytes, err := ctx.GetStub().GetPrivateData(collection, model.AccountKey(accountName))
 	if err != nil {
 		return fmt.Errorf("error getting account %q from world state: %w", accountName, err)
@@ -169,7 +209,7 @@ func (b *BlossomSmartContract) UploadATO(ctx contractapi.TransactionContextInter
 	}
 
 	// update ATO value
-	acctPvt.ATO = transientInput.ATO
+	acctPvt.ATO = ato
 
 	// marshal back to json
 	if bytes, err = json.Marshal(acctPvt); err != nil {
@@ -177,14 +217,14 @@ func (b *BlossomSmartContract) UploadATO(ctx contractapi.TransactionContextInter
 	}
 
 	// update pdc
-	if err = ctx.GetStub().PutPrivateData(collection, model.AccountKey(accountName), bytes); err != nil {
+	if err = putPrivateData(ctx, collection, model.AccountKey(accountName), "UploadATO", bytes); err != nil {
 		return fmt.Errorf("error updating ATO for account %q: %w", accountName, err)
 	}
 
 	return nil
 }
 
-func (b *BlossomSmartContract) UpdateAccountStatus(ctx contractapi.TransactionContextInterface, accountName, statusStr string) error {
+func (b *BlossomSmartContract) UpdateAccountStatus(ctx contractapi.TransactionContextInterface, accountName, statusStr, reason string) error {
 	status, err := model.GetStatusUpdate(statusStr)
 	if err != nil {
 		return err
@@ -211,25 +251,669 @@ func (b *BlossomSmartContract) UpdateAccountStatus(ctx contractapi.TransactionCo
 		return fmt.Errorf("error unmarshaling account %q: %w", accountName, err)
 	}
 
-	// update status
+	if err = putAccountStatus(ctx, acctPub, status, reason); err != nil {
+		return err
+	}
+
+	// an account that opts out returns all of its licenses
+	if status == model.UnauthorizedOptOut {
+		if err = reclaimLicenses(ctx, acctPub, reason); err != nil {
+			return fmt.Errorf("error reclaiming licenses from account %q: %w", accountName, err)
+		}
+	}
+
+	// process event
+	return events.UpdateAccountStatusEvent(ctx, accountName, collections.Catalog(), status)
+}
+
+// reclaimLicenses checks in every license the account has checked out, cancels the account's open checkout requests,
+// transfers and offers and pending checkin and lease renewal requests, and emits a single event summarizing what was
+// reclaimed.  Offers claimed by the account are opened again for other accounts to claim.  The reclaimed licenses are
+// offered to the waitlist of each asset.  Each asset and the account's private info are written once.
+func reclaimLicenses(ctx contractapi.TransactionContextInterface, acctPub *model.AccountPublic, reason string) error {
+	collection := collections.Account(acctPub.Name)
+
+	bytes, err := ctx.GetStub().GetPrivateData(collection, model.AccountKey(acctPub.Name))
+	if err != nil {
+		return fmt.Errorf("error getting private info: %w", err)
+	}
+
+	acctPvt := model.NewAccountPrivate()
+	if err = json.Unmarshal(bytes, acctPvt); err != nil {
+		return fmt.Errorf("error unmarshaling private info: %w", err)
+	}
+
+	now, err := txTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+
+	reclaimed := model.LicensesReclaimed{
+		Account:           acctPub.Name,
+		Reason:            reason,
+		Licenses:          make(map[string][]string),
+		CancelledRequests: make([]string, 0),
+	}
+
+	// open checkout requests are cancelled and kept with their history
+	checkouts, err := getCheckoutRequests(ctx, acctPub.Name)
+	if err != nil {
+		return fmt.Errorf("error getting pending checkout requests: %w", err)
+	}
+
+	keys := make([]string, 0)
+	for key, req := range checkouts {
+		if req.IsOpen() {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	// the waitlisted requests are removed from the waitlists of their assets below
+	waitlisted := make(map[string]string)
+	for _, key := range keys {
+		if checkouts[key].Status == CheckoutWaitlisted {
+			waitlisted[checkouts[key].Asset] = key
+		}
+
+		if err = putCheckoutRequest(ctx, acctPub.Name, key, checkouts[key], CheckoutCancelled, 0, reason); err != nil {
+			return fmt.Errorf("error cancelling request %s: %w", key, err)
+		}
+
+		reclaimed.CancelledRequests = append(reclaimed.CancelledRequests, key)
+	}
+
+	// open transfers from or to the account are cancelled
+	transfers, err := openTransfers(ctx, acctPub.Name)
+	if err != nil {
+		return err
+	}
+
+	for _, transfer := range transfers {
+		if err = putTransfer(ctx, transfer, model.TransferCancelled, reason); err != nil {
+			return err
+		}
+
+		reclaimed.CancelledRequests = append(reclaimed.CancelledRequests, model.TransferKey(transfer.ID))
+	}
+
+	// open offers posted by the account are withdrawn and offers claimed by the account are opened again
+	offers, err := openOffers(ctx, acctPub.Name)
+	if err != nil {
+		return err
+	}
+
+	for _, offer := range offers {
+		if offer.Account != acctPub.Name {
+			offer.ClaimedBy = ""
+			offer.LeaseTermDays = 0
+			if err = putOffer(ctx, offer, model.OfferOpen, acctPub.Name, reason); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err = putOffer(ctx, offer, model.OfferWithdrawn, "", reason); err != nil {
+			return err
+		}
+
+		reclaimed.CancelledRequests = append(reclaimed.CancelledRequests, model.OfferKey(offer.ID))
+	}
+
+	// sort the assets and licenses so every peer endorses the same write set
+	assetIDs := make([]string, 0)
+	for assetID := range acctPvt.Assets {
+		assetIDs = append(assetIDs, assetID)
+	}
+	sort.Strings(assetIDs)
+
+	for _, assetID := range assetIDs {
+		licenses := make([]string, 0)
+		for license := range acctPvt.Assets[assetID] {
+			licenses = append(licenses, license)
+		}
+		sort.Strings(licenses)
+
+		assetPub, assetPvt, err := getAsset(ctx, assetID)
+		if err != nil {
+			return fmt.Errorf("error getting asset %s: %w", assetID, err)
+		}
+
+		if err = checkin(assetPub, assetPvt, acctPub, acctPvt, licenses, now); err != nil {
+			return fmt.Errorf("error checking in %s: %w", assetID, err)
+		}
+
+		// offer the reclaimed licenses to the requests waiting for them
+		waitlist, err := getWaitlist(ctx, assetID)
+		if err != nil {
+			return err
+		}
+
+		waiting := len(waitlist.Entries)
+		if key, ok := waitlisted[assetID]; ok {
+			waitlist.Remove(key)
+			delete(waitlisted, assetID)
+		}
+
+		accounts := map[string]*model.AccountPrivate{acctPub.Name: acctPvt}
+		if _, err = fulfillWaitlist(ctx, "ReclaimLicenses", assetPub, assetPvt, waitlist, accounts, checkouts); err != nil {
+			return err
+		}
+
+		if len(waitlist.Entries) != waiting {
+			if err = putWaitlist(ctx, waitlist); err != nil {
+				return err
+			}
+		}
+
+		if err = putAsset(ctx, "ReclaimLicenses", assetPub, assetPvt); err != nil {
+			return fmt.Errorf("error updating asset %s: %w", assetID, err)
+		}
+
+		reclaimed.Licenses[assetID] = licenses
+	}
+
+	if len(assetIDs) > 0 {
+		if err = putAccountPrivate(ctx, acctPub.Name, "ReclaimLicenses", acctPvt); err != nil {
+			return err
+		}
+	}
+
+	// remove the remaining waitlisted requests from the waitlists of assets the account did not hold licenses for
+	waitlistedAssets := make([]string, 0)
+	for assetID := range waitlisted {
+		waitlistedAssets = append(waitlistedAssets, assetID)
+	}
+	sort.Strings(waitlistedAssets)
+
+	for _, assetID := range waitlistedAssets {
+		if err = removeFromWaitlist(ctx, assetID, waitlisted[assetID]); err != nil {
+			return err
+		}
+	}
+
+	if keys, err = accountPrivateKeys(ctx, acctPub.Name, checkinRequestKey(acctPub.Name, "")); err != nil {
+		return fmt.Errorf("error getting pending checkin requests: %w", err)
+	}
+
+	renewals, err := accountPrivateKeys(ctx, acctPub.Name, leaseRenewalRequestKey(acctPub.Name, ""))
+	if err != nil {
+		return fmt.Errorf("error getting pending lease renewal requests: %w", err)
+	}
+
+	keys = append(keys, renewals...)
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if err = ctx.GetStub().DelPrivateData(collection, key); err != nil {
+			return fmt.Errorf("error cancelling request %s: %w", key, err)
+		}
+
+		reclaimed.CancelledRequests = append(reclaimed.CancelledRequests, key)
+	}
+
+	if bytes, err = json.Marshal(reclaimed); err != nil {
+		return fmt.Errorf("error marshaling event: %w", err)
+	}
+
+	return ctx.GetStub().SetEvent(model.LicensesReclaimedEvent, bytes)
+}
+
+// putAccountStatus moves the account to the given status if the transition is allowed, records who made the change,
+// why, and in which transaction, and writes the updated account to the world state.
+func putAccountStatus(ctx contractapi.TransactionContextInterface, acctPub *model.AccountPublic, status model.Status, reason string) error {
+	if reason == "" {
+		return fmt.Errorf("a reason is required to update the status of account %q", acctPub.Name)
+	}
+
+	if err := model.ValidateStatusTransition(acctPub.Status, status); err != nil {
+		return fmt.Errorf("error updating status of account %q: %w", acctPub.Name, err)
+	}
+
+	update, err := newUpdate(ctx, string(status), reason)
+	if err != nil {
+		return err
+	}
+	update.Previous = string(acctPub.Status)
+
+	acctPub.StatusUpdate = &update
 	acctPub.Status = status
 
 	// marshal back to json
+	bytes, err := json.Marshal(acctPub)
+	if err != nil {
+		return fmt.Errorf("error marshaling account %q: %w", acctPub.Name, err)
+	}
+
+	// update world state
+	if err = ctx.GetStub().PutState(model.AccountKey(acctPub.Name), bytes); err != nil {
+		return fmt.Errorf("error updating status of account %q: %w", acctPub.Name, err)
+	}
+
+	return nil
+}
+
+func (b *BlossomSmartContract) SweepExpiredATOs(ctx contractapi.TransactionContextInterface) ([]string, error) {
+	// ngac check
+	if err := decider.CanSweepExpiredATOs(ctx); err != nil {
+		return nil, fmt.Errorf("error sweeping expired ATOs: %w", err)
+	}
+
+	timestamp, err := txTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.sweepAuthorizedAccounts(ctx, model.UnauthorizedATO, func(acctPvt *model.AccountPrivate) string {
+		// accounts without an ATO expiration on record are left alone
+		if acctPvt.ATO != nil && acctPvt.ATO.IsExpired(timestamp) {
+			return fmt.Sprintf("ATO expired on %s", acctPvt.ATO.ExpirationDate.Format(time.RFC3339))
+		}
+
+		return ""
+	})
+}
+
+// sweepAuthorizedAccounts moves every authorized account to the given status if the sweep function returns a reason
+// for the account's private info.  The status events of all swept accounts are processed with a single write of the
+// policy store.  The names of the swept accounts are returned.
+func (b *BlossomSmartContract) sweepAuthorizedAccounts(ctx contractapi.TransactionContextInterface, status model.Status,
+	sweep func(acctPvt *model.AccountPrivate) string) ([]string, error) {
+	accounts, err := b.GetAccounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting accounts: %w", err)
+	}
+
+	swept := make([]string, 0)
+	statuses := make(map[string]model.Status)
+	for _, acctPub := range accounts {
+		if acctPub.Status != model.Authorized {
+			continue
+		}
+
+		bytes, err := ctx.GetStub().GetPrivateData(collections.Account(acctPub.Name), model.AccountKey(acctPub.Name))
+		if err != nil {
+			return nil, fmt.Errorf("error getting private info for account %q: %w", acctPub.Name, err)
+		}
+
+		acctPvt := model.NewAccountPrivate()
+		if bytes != nil {
+			if err = json.Unmarshal(bytes, acctPvt); err != nil {
+				return nil, fmt.Errorf("error unmarshaling private info for account %q: %w", acctPub.Name, err)
+			}
+		}
+
+		reason := sweep(acctPvt)
+		if reason == "" {
+			continue
+		}
+
+		if err = putAccountStatus(ctx, acctPub, status, reason); err != nil {
+			return nil, err
+		}
+
+		swept = append(swept, acctPub.Name)
+		statuses[acctPub.Name] = status
+	}
+
+	if len(statuses) == 0 {
+		return swept, nil
+	}
+
+	if err = events.UpdateAccountStatusEvents(ctx, collections.Catalog(), statuses); err != nil {
+		return nil, err
+	}
+
+	return swept, nil
+}
+
+func (b *BlossomSmartContract) UpdateAccountAgency(ctx contractapi.TransactionContextInterface) error {
+	transientInput, err := getAccountAgencyTransientInput(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting transient input: %w", err)
+	}
+
+	accountName, err := accountName(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting account name from stub: %w", err)
+	}
+
+	if ok, err := accountExists(ctx, accountName); err != nil {
+		return fmt.Errorf("error checking if account %q exists: %w", accountName, err)
+	} else if !ok {
+		return fmt.Errorf("an account with the name %q does not exist", accountName)
+	}
+
+	// ngac check
+	if err = decider.CanUpdateAccountAgency(ctx, accountName); err != nil {
+		return fmt.Errorf("error updating agency of account %s: %w", accountName, err)
+	}
+
+	bytes, err := ctx.GetStub().GetState(model.AccountKey(accountName))
+	if err != nil {
+		return fmt.Errorf("error getting account %q from world state: %w", accountName, err)
+	}
+
+	acctPub := model.NewAccountPublic()
+	if err = json.Unmarshal(bytes, acctPub); err != nil {
+		return fmt.Errorf("error unmarshaling account %q: %w", accountName, err)
+	}
+
+	acctPub.Department = transientInput.Department
+	acctPub.SubAgency = transientInput.SubAgency
+
 	if bytes, err = json.Marshal(acctPub); err != nil {
 		return fmt.Errorf("error marshaling account %q: %w", accountName, err)
 	}
 
-	// update world state
 	if err = ctx.GetStub().PutState(model.AccountKey(accountName), bytes); err != nil {
-		return fmt.Errorf("error updating status of account %q: %w", accountName, err)
+		return fmt.Errorf("error updating agency of account %q: %w", accountName, err)
 	}
 
-	// process event
-	return events.UpdateAccountStatusEvent(ctx, accountName, collections.Catalog(), status)
+	// points of contact identify individuals so they are kept in the account's private data collection
+	acctPvt, err := getAccountPrivate(ctx, accountName)
+	if err != nil {
+		return err
+	}
+
+	acctPvt.PointsOfContact = transientInput.PointsOfContact
+
+	return putAccountPrivate(ctx, accountName, "UpdateAccountAgency", acctPvt)
+}
+
+func (b *BlossomSmartContract) DecommissionAccount(ctx contractapi.TransactionContextInterface, accountName, reason string) error {
+	if ok, err := accountExists(ctx, accountName); err != nil {
+		return fmt.Errorf("error checking if account %q exists: %w", accountName, err)
+	} else if !ok {
+		return fmt.Errorf("an account with the name %q does not exist", accountName)
+	}
+
+	// ngac check
+	if err := decider.CanDecommissionAccount(ctx, accountName); err != nil {
+		return fmt.Errorf("error decommissioning account %s: %w", accountName, err)
+	}
+
+	bytes, err := ctx.GetStub().GetState(model.AccountKey(accountName))
+	if err != nil {
+		return fmt.Errorf("error getting account %q from world state: %w", accountName, err)
+	}
+
+	acctPub := model.NewAccountPublic()
+	if err = json.Unmarshal(bytes, acctPub); err != nil {
+		return fmt.Errorf("error unmarshaling account %q: %w", accountName, err)
+	}
+
+	if err = model.ValidateStatusTransition(acctPub.Status, model.Decommissioned); err != nil {
+		return fmt.Errorf("error decommissioning account %q: %w", accountName, err)
+	}
+
+	collection := collections.Account(accountName)
+
+	if bytes, err = ctx.GetStub().GetPrivateData(collection, model.AccountKey(accountName)); err != nil {
+		return fmt.Errorf("error getting account %q from private data: %w", accountName, err)
+	}
+
+	acctPvt := model.NewAccountPrivate()
+	if err = json.Unmarshal(bytes, acctPvt); err != nil {
+		return fmt.Errorf("error unmarshaling account %q: %w", accountName, err)
+	}
+
+	// all licenses must be returned before the account can be decommissioned
+	if len(acctPvt.Assets) > 0 {
+		return fmt.Errorf("account %q still has licenses checked out", accountName)
+	}
+
+	// all checkout, checkin and lease renewal requests and transfers must be resolved
+	checkouts, err := getCheckoutRequests(ctx, accountName)
+	if err != nil {
+		return fmt.Errorf("error getting checkout requests of account %q: %w", accountName, err)
+	}
+
+	for _, req := range checkouts {
+		if req.IsOpen() {
+			return fmt.Errorf("account %q has open checkout requests", accountName)
+		}
+	}
+
+	if keys, err := accountPrivateKeys(ctx, accountName, checkinRequestKey(accountName, "")); err != nil {
+		return fmt.Errorf("error getting checkin requests of account %q: %w", accountName, err)
+	} else if len(keys) > 0 {
+		return fmt.Errorf("account %q has open checkin requests", accountName)
+	}
+
+	if transfers, err := openTransfers(ctx, accountName); err != nil {
+		return err
+	} else if len(transfers) > 0 {
+		return fmt.Errorf("account %q has open transfers", accountName)
+	}
+
+	if offers, err := openOffers(ctx, accountName); err != nil {
+		return err
+	} else if len(offers) > 0 {
+		return fmt.Errorf("account %q has open offers", accountName)
+	}
+
+	// a purchased acquisition still holds the licenses to onboard
+	if acquisitions, err := openAcquisitions(ctx, accountName); err != nil {
+		return err
+	} else if len(acquisitions) > 0 {
+		return fmt.Errorf("account %q has open acquisitions", accountName)
+	}
+
+	if keys, err := accountPrivateKeys(ctx, accountName, leaseRenewalRequestKey(accountName, "")); err != nil {
+		return fmt.Errorf("error getting lease renewal requests of account %q: %w", accountName, err)
+	} else if len(keys) > 0 {
+		return fmt.Errorf("account %q has open lease renewal requests", accountName)
+	}
+
+	swids, err := accountPrivateKeys(ctx, accountName, model.SwIDPrefix)
+	if err != nil {
+		return fmt.Errorf("error getting swids of account %q: %w", accountName, err)
+	}
+
+	forecasts, err := accountPrivateKeys(ctx, accountName, model.ForecastKey(accountName, ""))
+	if err != nil {
+		return fmt.Errorf("error getting forecasts of account %q: %w", accountName, err)
+	}
+
+	acquisitions, err := accountPrivateKeys(ctx, accountName, model.AcquisitionKey(accountName, ""))
+	if err != nil {
+		return fmt.Errorf("error getting acquisitions of account %q: %w", accountName, err)
+	}
+
+	// delete the account's swids, forecasts and acquisitions
+	keys := append(append(swids, forecasts...), acquisitions...)
+	for _, key := range keys {
+		if err = ctx.GetStub().DelPrivateData(collection, key); err != nil {
+			return fmt.Errorf("error deleting %s: %w", key, err)
+		}
+	}
+
+	// delete account private info
+	if err = delPrivateData(ctx, collection, model.AccountKey(accountName), "DecommissionAccount"); err != nil {
+		return fmt.Errorf("error deleting private info of account %q: %w", accountName, err)
+	}
+
+	// the public info stays on the ledger as a tombstone
+	if err = putAccountStatus(ctx, acctPub, model.Decommissioned, reason); err != nil {
+		return err
+	}
+
+	return events.ProcessDecommissionAccount(ctx, accountName)
+}
+
+func (b *BlossomSmartContract) AddAccountUser(ctx contractapi.TransactionContextInterface) error {
+	transientInput, err := getAccountUserTransientInput(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting transient input: %w", err)
+	}
+
+	if !model.IsValidRole(transientInput.Role) {
+		return fmt.Errorf("unrecognized role: %s", transientInput.Role)
+	}
+
+	accountName, err := accountName(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting account name from stub: %w", err)
+	}
+
+	acctPvt, err := getAccountUsers(ctx, accountName)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := acctPvt.Users[transientInput.Username]; ok {
+		return fmt.Errorf("user %s is already registered with account %q", transientInput.Username, accountName)
+	}
+
+	acctPvt.Users[transientInput.Username] = transientInput.Role
+
+	return putAccountPrivate(ctx, accountName, "AddAccountUser", acctPvt)
+}
+
+func (b *BlossomSmartContract) RemoveAccountUser(ctx contractapi.TransactionContextInterface, username string) error {
+	accountName, err := accountName(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting account name from stub: %w", err)
+	}
+
+	acctPvt, err := getAccountUsers(ctx, accountName)
+	if err != nil {
+		return err
+	}
+
+	role, ok := acctPvt.Users[username]
+	if !ok {
+		return fmt.Errorf("user %s is not registered with account %q", username, accountName)
+	}
+
+	delete(acctPvt.Users, username)
+
+	// the account would have no one left to manage its users
+	if role == model.SystemOwnerRole && !hasRole(acctPvt.Users, model.SystemOwnerRole) {
+		return fmt.Errorf("user %s is the last system owner of account %q", username, accountName)
+	}
+
+	return putAccountPrivate(ctx, accountName, "RemoveAccountUser", acctPvt)
+}
+
+func (b *BlossomSmartContract) ProcessAccountUsers(ctx contractapi.TransactionContextInterface, accountName string) error {
+	if ok, err := accountExists(ctx, accountName); err != nil {
+		return fmt.Errorf("error checking if account %q exists: %w", accountName, err)
+	} else if !ok {
+		return fmt.Errorf("an account with the name %q does not exist", accountName)
+	}
+
+	// ngac check
+	if err := decider.CanProcessAccountUsers(ctx); err != nil {
+		return fmt.Errorf("ngac check failed: %w", err)
+	}
+
+	bytes, err := ctx.GetStub().GetPrivateData(collections.Account(accountName), model.AccountKey(accountName))
+	if err != nil {
+		return fmt.Errorf("error getting account %q from private data: %w", accountName, err)
+	} else if bytes == nil {
+		return fmt.Errorf("an account with the name %q does not exist", accountName)
+	}
+
+	acctPvt := model.NewAccountPrivate()
+	if err = json.Unmarshal(bytes, acctPvt); err != nil {
+		return fmt.Errorf("error unmarshaling account %q: %w", accountName, err)
+	}
+
+	// the account would have no one left to manage its users, i.e. the roster of an account approved before users were
+	// registered that does not list its system owner
+	if !hasRole(acctPvt.Users, model.SystemOwnerRole) {
+		return fmt.Errorf("account %q does not have a system owner registered", accountName)
+	}
+
+	return events.ProcessSetAccountUsers(ctx, accountName, acctPvt.Users)
+}
+
+// getAccountUsers checks that the requesting user can manage the users of the account and returns the account's
+// private info.
+func getAccountUsers(ctx contractapi.TransactionContextInterface, accountName string) (*model.AccountPrivate, error) {
+	if ok, err := accountExists(ctx, accountName); err != nil {
+		return nil, fmt.Errorf("error checking if account %q exists: %w", accountName, err)
+	} else if !ok {
+		return nil, fmt.Errorf("an account with the name %q does not exist", accountName)
+	}
+
+	// ngac check
+	if err := decider.CanManageAccountUsers(ctx, accountName); err != nil {
+		return nil, fmt.Errorf("error managing users of account %s: %w", accountName, err)
+	}
+
+	return getAccountPrivate(ctx, accountName)
+}
+
+// hasRole reports whether any of the users has the role.
+func hasRole(users map[string]string, role string) bool {
+	for _, r := range users {
+		if r == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getAccountPrivate returns the private info of the account from the account's private data collection.
+func getAccountPrivate(ctx contractapi.TransactionContextInterface, accountName string) (*model.AccountPrivate, error) {
+	bytes, err := ctx.GetStub().GetPrivateData(collections.Account(accountName), model.AccountKey(accountName))
+	if err != nil {
+		return nil, fmt.Errorf("error getting private info of account %q: %w", accountName, err)
+	}
+
+	acctPvt := model.NewAccountPrivate()
+	if err = json.Unmarshal(bytes, acctPvt); err != nil {
+		return nil, fmt.Errorf("error unmarshaling private info of account %q: %w", accountName, err)
+	}
+
+	return acctPvt, nil
+}
+
+func putAccountPrivate(ctx contractapi.TransactionContextInterface, accountName, operation string, acctPvt *model.AccountPrivate) error {
+	bytes, err := json.Marshal(acctPvt)
+	if err != nil {
+		return fmt.Errorf("error marshaling account %q: %w", accountName, err)
+	}
+
+	if err = putPrivateData(ctx, collections.Account(accountName), model.AccountKey(accountName), operation, bytes); err != nil {
+		return fmt.Errorf("error updating private info of account %q: %w", accountName, err)
+	}
+
+	return nil
+}
+
+// accountPrivateKeys returns the keys in the account's private data collection that begin with the given prefix.
+func accountPrivateKeys(ctx contractapi.TransactionContextInterface, accountName, prefix string) ([]string, error) {
+	iter, err := ctx.GetStub().GetPrivateDataByRange(collections.Account(accountName), "", "")
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	keys := make([]string, 0)
+	for iter.HasNext() {
+		var next *queryresult.KV
+		if next, err = iter.Next(); err != nil {
+			return nil, fmt.Errorf("error getting next KV: %w", err)
+		}
+
+		if !strings.HasPrefix(next.Key, prefix) {
+			continue
+		}
+
+		keys = append(keys, next.Key)
+	}
+
+	return keys, nil
 }
 
 func (b *BlossomSmartContract) GetAccounts(ctx contractapi.TransactionContextInterface) ([]*model.AccountPublic, error) {
-	resultsIterator, err := ctx.GetStub().GetStateByRange("", "")
+	resultsIterator, err := ctx.GetStub().GetStateByRange(model.AccountKeyRange())
 	if err != nil {
 		return nil, err
 	}
@@ -257,6 +941,119 @@ func (b *BlossomSmartContract) GetAccounts(ctx contractapi.TransactionContextInt
 	return accounts, nil
 }
 
+func (b *BlossomSmartContract) GetAccountsPage(ctx contractapi.TransactionContextInterface, pageSize int32, bookmark, statusFilter string) (*model.AccountsPage, error) {
+	if pageSize <= 0 {
+		return nil, fmt.Errorf("page size must be greater than 0")
+	}
+
+	var (
+		status model.Status
+		err    error
+	)
+
+	if statusFilter != "" {
+		if status, err = model.ParseStatus(statusFilter); err != nil {
+			return nil, err
+		}
+	}
+
+	page := &model.AccountsPage{
+		Accounts: make([]*model.AccountPublic, 0),
+		Bookmark: bookmark,
+	}
+
+	start, end := model.AccountKeyRange()
+
+	// when filtering, a page of the ledger may hold fewer matching accounts than requested so keep reading pages until
+	// the page is full or there are no more accounts. Only the number of accounts still needed is read each time so
+	// the bookmark never skips a matching account.
+	for {
+		accounts, next, err := getAccountsRange(ctx, start, end, pageSize-int32(len(page.Accounts)), page.Bookmark)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, acctPub := range accounts {
+			if status != "" && acctPub.Status != status {
+				continue
+			}
+
+			page.Accounts = append(page.Accounts, acctPub)
+		}
+
+		page.Bookmark = next
+
+		if next == "" || int32(len(page.Accounts)) >= pageSize {
+			break
+		}
+	}
+
+	// counting reads every account so it is only done for the first page, later pages omit the count
+	if bookmark == "" {
+		count, err := countAccounts(ctx, start, end, status)
+		if err != nil {
+			return nil, err
+		}
+
+		page.Count = &count
+	}
+
+	return page, nil
+}
+
+// countAccounts returns the number of accounts in the key range with the status, or all accounts if the status is empty.
+func countAccounts(ctx contractapi.TransactionContextInterface, start, end string, status model.Status) (int, error) {
+	resultsIterator, err := ctx.GetStub().GetStateByRange(start, end)
+	if err != nil {
+		return 0, fmt.Errorf("error getting accounts: %w", err)
+	}
+	defer resultsIterator.Close()
+
+	count := 0
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return 0, err
+		}
+
+		acctPub := model.NewAccountPublic()
+		if err = json.Unmarshal(queryResponse.Value, acctPub); err != nil {
+			return 0, fmt.Errorf("error unmarshaling account %s: %w", queryResponse.Key, err)
+		}
+
+		if status == "" || acctPub.Status == status {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func getAccountsRange(ctx contractapi.TransactionContextInterface, start, end string, pageSize int32, bookmark string) ([]*model.AccountPublic, string, error) {
+	resultsIterator, metadata, err := ctx.GetStub().GetStateByRangeWithPagination(start, end, pageSize, bookmark)
+	if err != nil {
+		return nil, "", fmt.Errorf("error getting accounts: %w", err)
+	}
+	defer resultsIterator.Close()
+
+	accounts := make([]*model.AccountPublic, 0)
+	for resultsIterator.HasNext() {
+		var queryResponse *queryresult.KV
+		if queryResponse, err = resultsIterator.Next(); err != nil {
+			return nil, "", err
+		}
+
+		acctPub := model.NewAccountPublic()
+		if err = json.Unmarshal(queryResponse.Value, acctPub); err != nil {
+			return nil, "", fmt.Errorf("error unmarshaling account %s: %w", queryResponse.Key, err)
+		}
+
+		accounts = append(accounts, acctPub)
+	}
+
+	return accounts, metadata.Bookmark, nil
+}
+
 func (b *BlossomSmartContract) GetAccount(ctx contractapi.TransactionContextInterface, accountName string) (*model.Account, error) {
 	var (
 		acctPub = model.NewAccountPublic()
@@ -283,18 +1080,24 @@ func (b *BlossomSmartContract) GetAccount(ctx contractapi.TransactionContextInte
 		// ignore error if a user does not have access to the private data collection of the account
 		// they can still have access to the public info
 		fmt.Printf("error occurred reading pvtdata: %v\n", err)
-	} else {
+	} else if bytes != nil {
 		if err = json.Unmarshal(bytes, acctPvt); err != nil {
 			return nil, fmt.Errorf("error deserializing account private info: %w", err)
 		}
 	}
 
 	return &model.Account{
-		Name:   acctPub.Name,
-		MSPID:  acctPub.MSPID,
-		Status: acctPub.Status,
-		ATO:    acctPvt.ATO,
-		Assets: acctPvt.Assets,
+		Name:            acctPub.Name,
+		MSPID:           acctPub.MSPID,
+		Status:          acctPub.Status,
+		StatusUpdate:    acctPub.StatusUpdate,
+		Department:      acctPub.Department,
+		SubAgency:       acctPub.SubAgency,
+		PointsOfContact: acctPvt.PointsOfContact,
+		ATO:             acctPvt.ATO,
+		ROBAcceptance:   acctPvt.ROBAcceptance,
+		Assets:          acctPvt.Assets,
+		Users:           acctPvt.Users,
 	}, nil
 }
 
@@ -307,7 +1110,7 @@ func (b *BlossomSmartContract) GetHistory(ctx contractapi.TransactionContextInte
 	}
 	defer iter.Close()
 
-	// Getting history for private data is not yet supported, so info will be limited to public account info
+	// Fabric does not keep the history of private data, see GetAccountPrivateHistory for the account's private info
 
 	for iter.HasNext() {
 		result, err := iter.Next()
@@ -330,3 +1133,18 @@ func (b *BlossomSmartContract) GetHistory(ctx contractapi.TransactionContextInte
 
 	return history, nil
 }
+
+func (b *BlossomSmartContract) GetAccountPrivateHistory(ctx contractapi.TransactionContextInterface, account string) ([]model.PrivateHistoryEntry, error) {
+	if ok, err := accountExists(ctx, account); err != nil {
+		return nil, fmt.Errorf("error checking if account %q exists: %w", account, err)
+	} else if !ok {
+		return nil, fmt.Errorf("an account with the name %q does not exist", account)
+	}
+
+	history, err := getPrivateHistory(ctx, collections.Account(account), model.AccountKey(account))
+	if err != nil {
+		return nil, fmt.Errorf("error getting private history of account %q: %w", account, err)
+	}
+
+	return history, nil
+}
@@ -1,11 +1,18 @@
 package api
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"github.com/stretchr/testify/require"
+	"github.com/usnistgov/blossom/chaincode/collections"
 	"github.com/usnistgov/blossom/chaincode/mocks"
 	"github.com/usnistgov/blossom/chaincode/model"
+	"github.com/usnistgov/blossom/chaincode/ngac/common"
+	"github.com/usnistgov/blossom/chaincode/ngac/pap"
+	decider "github.com/usnistgov/blossom/chaincode/ngac/pdp"
 	"testing"
+	"time"
 )
 
 func TestRequestAccount(t *testing.T) {
@@ -39,7 +46,22 @@ func TestRequestAccount(t *testing.T) {
 		require.NoError(t, err)
 
 		bcc := BlossomSmartContract{}
-		err = ctx.SetTransient("account", accountTransientInput{"a1_system_owner", "a1_system_admin", "a1_acq_spec"})
+		input := accountTransientInput{
+			SystemOwner:           "org2user1",
+			SystemAdmin:           "org2user2",
+			AcquisitionSpecialist: "org2user3",
+		}
+
+		// the department is required
+		err = ctx.SetTransient("account", input)
+		require.NoError(t, err)
+		err = bcc.RequestAccount(ctx)
+		require.Error(t, err)
+
+		input.Department = "Department of Commerce"
+		input.SubAgency = "NIST"
+		input.PointsOfContact = []model.PointOfContact{{Name: "a1 poc", Email: "poc@a1.gov"}}
+		err = ctx.SetTransient("account", input)
 		require.NoError(t, err)
 		err = bcc.RequestAccount(ctx)
 		require.NoError(t, err)
@@ -62,6 +84,108 @@ func TestRequestAccount(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("test user with two roles", func(t *testing.T) {
+		ctx := newTestStub(t)
+
+		err := ctx.SetClientIdentity(mocks.Org2SystemOwner)
+		require.NoError(t, err)
+
+		bcc := BlossomSmartContract{}
+		err = ctx.SetTransient("account", accountTransientInput{
+			SystemOwner:           "org2user1",
+			SystemAdmin:           "org2user1",
+			AcquisitionSpecialist: "org2user3",
+			accountAgencyTransientInput: accountAgencyTransientInput{
+				Department: "Department of Commerce",
+			},
+		})
+		require.NoError(t, err)
+		err = bcc.RequestAccount(ctx)
+		require.Error(t, err)
+	})
+
+	t.Run("test system owner is the requesting user", func(t *testing.T) {
+		ctx := newTestStub(t)
+
+		err := ctx.SetClientIdentity(mocks.Org2SystemOwner)
+		require.NoError(t, err)
+
+		bcc := BlossomSmartContract{}
+		input := accountTransientInput{
+			SystemOwner:           "someone_else",
+			SystemAdmin:           "org2user2",
+			AcquisitionSpecialist: "org2user3",
+			accountAgencyTransientInput: accountAgencyTransientInput{
+				Department: "Department of Commerce",
+			},
+		}
+		err = ctx.SetTransient("account", input)
+		require.NoError(t, err)
+		err = bcc.RequestAccount(ctx)
+		require.Error(t, err)
+
+		// the system owner defaults to the requesting user
+		input.SystemOwner = ""
+		err = ctx.SetTransient("account", input)
+		require.NoError(t, err)
+		err = bcc.RequestAccount(ctx)
+		require.NoError(t, err)
+
+		err = ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		acct, err := bcc.GetAccount(ctx, Org2MSP)
+		require.NoError(t, err)
+		require.Equal(t, model.SystemOwnerRole, acct.Users["org2user1"])
+		require.NotContains(t, acct.Users, "")
+	})
+}
+
+func TestUpdateAccountAgency(t *testing.T) {
+	ctx := newTestStub(t)
+	bcc := BlossomSmartContract{}
+
+	requestTestAccount(t, ctx, Org2MSP)
+	requestTestAccount(t, ctx, Org3MSP)
+
+	input := accountAgencyTransientInput{
+		Department:      "Department of Energy",
+		PointsOfContact: []model.PointOfContact{{Name: "org3 poc", Email: "poc@org3.gov", Phone: "555-0100"}},
+	}
+
+	t.Run("test updates own account", func(t *testing.T) {
+		// the account is the MSPID of the requesting user, not an argument
+		require.NoError(t, ctx.SetClientIdentity(mocks.Org3SystemOwner))
+		require.NoError(t, ctx.SetTransient("agency", input))
+		err := bcc.UpdateAccountAgency(ctx)
+		require.NoError(t, err)
+
+		require.NoError(t, ctx.SetClientIdentity(mocks.Super))
+		acct, err := bcc.GetAccount(ctx, Org3MSP)
+		require.NoError(t, err)
+		require.Equal(t, "Department of Energy", acct.Department)
+		require.Equal(t, input.PointsOfContact, acct.PointsOfContact)
+
+		acct, err = bcc.GetAccount(ctx, Org2MSP)
+		require.NoError(t, err)
+		require.Equal(t, "Department of Commerce", acct.Department)
+	})
+
+	t.Run("test points of contact are private", func(t *testing.T) {
+		bytes, err := ctx.GetStub().GetState(model.AccountKey(Org3MSP))
+		require.NoError(t, err)
+		require.NotContains(t, string(bytes), "poc@org3.gov")
+
+		bytes, err = ctx.GetStub().GetPrivateData(Org3Collection, model.AccountKey(Org3MSP))
+		require.NoError(t, err)
+		require.Contains(t, string(bytes), "poc@org3.gov")
+	})
+
+	t.Run("test unauthorized user", func(t *testing.T) {
+		require.NoError(t, ctx.SetClientIdentity(mocks.Org2SystemAdmin))
+		require.NoError(t, ctx.SetTransient("agency", input))
+		err := bcc.UpdateAccountAgency(ctx)
+		require.Error(t, err)
+	})
 }
 
 func TestUploadATO(t *testing.T) {
@@ -73,25 +197,153 @@ func TestUploadATO(t *testing.T) {
 	require.NoError(t, err)
 
 	bcc := BlossomSmartContract{}
-	err = ctx.SetTransient("ato", uploadATOTransientInput{"my ato"})
+	input := testATOInput(time.Now().AddDate(2, 0, 0).UTC().Truncate(time.Second))
+	err = ctx.SetTransient("ato", input)
 	require.NoError(t, err)
 	err = bcc.UploadATO(ctx)
 	require.NoError(t, err)
 
 	account, err := bcc.GetAccount(ctx, Org2MSP)
 	require.NoError(t, err)
-	require.Equal(t, "my ato", account.ATO)
+	require.Equal(t, input.toATO(), account.ATO)
+
+	t.Run("test invalid ato", func(t *testing.T) {
+		invalid := input
+		invalid.ImpactLevel = "SEVERE"
+		err = ctx.SetTransient("ato", invalid)
+		require.NoError(t, err)
+		err = bcc.UploadATO(ctx)
+		require.Error(t, err)
+
+		invalid = input
+		invalid.Digest = "abc"
+		err = ctx.SetTransient("ato", invalid)
+		require.NoError(t, err)
+		err = bcc.UploadATO(ctx)
+		require.Error(t, err)
+	})
+
+	t.Run("test expired ato", func(t *testing.T) {
+		err = ctx.SetTransient("ato", testATOInput(time.Now().AddDate(0, 0, -1)))
+		require.NoError(t, err)
+		err = bcc.UploadATO(ctx)
+		require.Error(t, err)
+	})
 
 	err = ctx.SetClientIdentity(mocks.Org2AcqSpec)
 	require.NoError(t, err)
 
-	err = ctx.SetTransient("ato", uploadATOTransientInput{"my ato"})
+	err = ctx.SetTransient("ato", input)
 	require.NoError(t, err)
 	err = bcc.UploadATO(ctx)
 	require.Error(t, err)
 	require.Errorf(t, err, "error uploading ATO for account Org2MSP: user a1_acq_spec does not have permission upload_ato on A1MSP_object", err.Error())
 }
 
+func TestSweepExpiredATOs(t *testing.T) {
+	ctx := newTestStub(t)
+
+	requestTestAccount(t, ctx, Org2MSP)
+	requestTestAccount(t, ctx, Org3MSP)
+	onboardTestAsset(t, ctx, "123", "asset1", []string{"1"})
+
+	bcc := BlossomSmartContract{}
+
+	// give Org3 an ATO that lapses before Org2's
+	err := ctx.SetClientIdentity(mocks.Org3SystemOwner)
+	require.NoError(t, err)
+	err = ctx.SetTransient("ato", testATOInput(time.Now().AddDate(0, 1, 0)))
+	require.NoError(t, err)
+	err = bcc.UploadATO(ctx)
+	require.NoError(t, err)
+
+	t.Run("test only admin can sweep", func(t *testing.T) {
+		err = ctx.SetClientIdentity(mocks.Org2SystemOwner)
+		require.NoError(t, err)
+		_, err = bcc.SweepExpiredATOs(ctx)
+		require.Error(t, err)
+	})
+
+	err = ctx.SetClientIdentity(mocks.Super)
+	require.NoError(t, err)
+
+	t.Run("test no expired atos", func(t *testing.T) {
+		swept, err := bcc.SweepExpiredATOs(ctx)
+		require.NoError(t, err)
+		require.Empty(t, swept)
+	})
+
+	// give Org2 an ATO stored before ATOs were structured, which has no expiration date
+	bytes, err := ctx.GetStub().GetPrivateData(Org2Collection, model.AccountKey(Org2MSP))
+	require.NoError(t, err)
+	acctPvt := make(map[string]interface{})
+	require.NoError(t, json.Unmarshal(bytes, &acctPvt))
+	acctPvt["ato"] = "legacy ato attestation"
+	bytes, err = json.Marshal(acctPvt)
+	require.NoError(t, err)
+	require.NoError(t, ctx.GetStub().PutPrivateData(Org2Collection, model.AccountKey(Org2MSP), bytes))
+
+	t.Run("test expired ato", func(t *testing.T) {
+		err = ctx.SetTxTimestamp(time.Now().AddDate(0, 2, 0))
+		require.NoError(t, err)
+
+		swept, err := bcc.SweepExpiredATOs(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []string{Org3MSP}, swept)
+
+		acct, err := bcc.GetAccount(ctx, Org3MSP)
+		require.NoError(t, err)
+		require.Equal(t, model.UnauthorizedATO, acct.Status)
+		require.Equal(t, string(model.Authorized), acct.StatusUpdate.Previous)
+
+		acct, err = bcc.GetAccount(ctx, Org2MSP)
+		require.NoError(t, err)
+		require.Equal(t, model.Authorized, acct.Status)
+
+		// the set_account_inactive obligation removes write access from the account
+		err = ctx.SetClientIdentity(mocks.Org3SystemOwner)
+		require.NoError(t, err)
+		err = ctx.SetTransient("checkout", testCheckoutInput("123", 1))
+		require.NoError(t, err)
+		err = bcc.RequestCheckout(ctx)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "ngac check failed")
+	})
+}
+
+func TestGetAccountPrivateHistory(t *testing.T) {
+	ctx := newTestStub(t)
+
+	requestTestAccount(t, ctx, Org2MSP)
+
+	bcc := BlossomSmartContract{}
+
+	err := ctx.SetClientIdentity(mocks.Org2SystemOwner)
+	require.NoError(t, err)
+
+	history, err := bcc.GetAccountPrivateHistory(ctx, Org2MSP)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Equal(t, "RequestAccount", history[0].Operation)
+	require.Equal(t, "org2user1:Org2MSP", history[0].Actor)
+	require.Equal(t, "UploadATO", history[1].Operation)
+	require.True(t, history[0].Timestamp.Before(history[1].Timestamp))
+
+	// the latest entry is the hash of the current private info
+	bytes, err := ctx.GetStub().GetPrivateData(Org2Collection, model.AccountKey(Org2MSP))
+	require.NoError(t, err)
+	hash := sha256.Sum256(bytes)
+	require.Equal(t, hex.EncodeToString(hash[:]), history[1].Hash)
+
+	t.Run("test other account cannot read history", func(t *testing.T) {
+		err = ctx.SetClientIdentity(mocks.Org3SystemOwner)
+		require.NoError(t, err)
+
+		_, err = bcc.GetAccountPrivateHistory(ctx, Org2MSP)
+		require.Error(t, err)
+	})
+}
+
 func TestUpdateAccountStatus(t *testing.T) {
 	ctx := newTestStub(t)
 
@@ -101,14 +353,112 @@ func TestUpdateAccountStatus(t *testing.T) {
 	require.NoError(t, err)
 
 	bcc := BlossomSmartContract{}
-	err = bcc.UpdateAccountStatus(ctx, Org2MSP, "AUTHORIZED")
+	err = bcc.UpdateAccountStatus(ctx, Org2MSP, "UNAUTHORIZED_OPTOUT", "opting out")
 	require.Error(t, err)
 
 	err = ctx.SetClientIdentity(mocks.Super)
 	require.NoError(t, err)
 
-	err = bcc.UpdateAccountStatus(ctx, Org2MSP, "AUTHORIZED")
+	t.Run("test reason is required", func(t *testing.T) {
+		err = bcc.UpdateAccountStatus(ctx, Org2MSP, "UNAUTHORIZED_SECURITY_RISK", "")
+		require.Error(t, err)
+	})
+
+	t.Run("test update is recorded", func(t *testing.T) {
+		ctx.SetTxID("123")
+		err = bcc.UpdateAccountStatus(ctx, Org2MSP, "UNAUTHORIZED_SECURITY_RISK", "security incident")
+		require.NoError(t, err)
+
+		acct, err := bcc.GetAccount(ctx, Org2MSP)
+		require.NoError(t, err)
+		require.Equal(t, model.UnauthorizedSecurityRisk, acct.Status)
+		require.Equal(t, string(model.Authorized), acct.StatusUpdate.Previous)
+		require.Equal(t, string(model.UnauthorizedSecurityRisk), acct.StatusUpdate.Status)
+		require.Equal(t, "security incident", acct.StatusUpdate.Reason)
+		require.Equal(t, "adminuser:Org1MSP", acct.StatusUpdate.Actor)
+		require.Equal(t, "123", acct.StatusUpdate.TxID)
+		require.False(t, acct.StatusUpdate.Timestamp.IsZero())
+	})
+
+	t.Run("test illegal transition", func(t *testing.T) {
+		err = bcc.UpdateAccountStatus(ctx, Org2MSP, "AUTHORIZED", "incident resolved")
+		require.Error(t, err)
+
+		err = bcc.UpdateAccountStatus(ctx, Org2MSP, "PENDING_ATO", "incident resolved")
+		require.NoError(t, err)
+
+		err = bcc.UpdateAccountStatus(ctx, Org2MSP, "AUTHORIZED", "ato reviewed")
+		require.NoError(t, err)
+	})
+}
+
+func TestOptOutReclaimsLicenses(t *testing.T) {
+	ctx := newTestStub(t)
+	bcc := BlossomSmartContract{}
+
+	onboardTestAsset(t, ctx, "123", "myasset1", []string{"1", "2", "3"})
+	onboardTestAsset(t, ctx, "321", "myasset2", []string{"1", "2"})
+	requestTestAccount(t, ctx, Org2MSP)
+
+	checkoutTestAsset(t, ctx, Org2MSP, "123", 2)
+	checkoutTestAsset(t, ctx, Org2MSP, "321", 1)
+
+	// leave a checkout and a checkin request pending
+	err := ctx.SetClientIdentity(mocks.Org2SystemAdmin)
+	require.NoError(t, err)
+	err = ctx.SetTransient("checkout", testCheckoutInput("321", 1))
+	require.NoError(t, err)
+	err = bcc.RequestCheckout(ctx)
+	require.NoError(t, err)
+	err = ctx.SetTransient("checkin", initiateCheckinTransientInput{AssetID: "123", Licenses: []string{"1"}})
+	require.NoError(t, err)
+	err = bcc.InitiateCheckin(ctx)
+	require.NoError(t, err)
+
+	err = ctx.SetClientIdentity(mocks.Super)
+	require.NoError(t, err)
+	err = bcc.UpdateAccountStatus(ctx, Org2MSP, "UNAUTHORIZED_OPTOUT", "opting out")
+	require.NoError(t, err)
+
+	acct, err := bcc.GetAccount(ctx, Org2MSP)
+	require.NoError(t, err)
+	require.Equal(t, model.UnauthorizedOptOut, acct.Status)
+	require.Empty(t, acct.Assets)
+
+	asset, err := bcc.GetAsset(ctx, "123")
+	require.NoError(t, err)
+	require.Equal(t, 3, asset.Available)
+	require.Len(t, asset.AvailableLicenses, 3)
+	require.Empty(t, asset.CheckedOut)
+
+	asset, err = bcc.GetAsset(ctx, "321")
+	require.NoError(t, err)
+	require.Equal(t, 2, asset.Available)
+	require.Empty(t, asset.CheckedOut)
+
+	// the pending checkout request is cancelled and kept with its history
+	checkouts, err := bcc.GetCheckoutRequests(ctx, Org2MSP)
+	require.NoError(t, err)
+	for _, req := range checkouts {
+		require.False(t, req.IsPending())
+	}
+	require.Equal(t, CheckoutCancelled, checkouts[len(checkouts)-1].Status)
+	require.Equal(t, "opting out", checkouts[len(checkouts)-1].History[1].Reason)
+
+	checkins, err := bcc.GetInitiatedCheckins(ctx, Org2MSP)
 	require.NoError(t, err)
+	require.Empty(t, checkins)
+
+	payload, ok := ctx.GetEvent(model.LicensesReclaimedEvent)
+	require.True(t, ok)
+
+	event := model.LicensesReclaimed{}
+	err = json.Unmarshal(payload, &event)
+	require.NoError(t, err)
+	require.Equal(t, Org2MSP, event.Account)
+	require.Equal(t, "opting out", event.Reason)
+	require.Equal(t, map[string][]string{"123": {"1", "2"}, "321": {"1"}}, event.Licenses)
+	require.Len(t, event.CancelledRequests, 2)
 }
 
 func TestAccounts(t *testing.T) {
@@ -127,6 +477,83 @@ func TestAccounts(t *testing.T) {
 	require.Equal(t, 2, len(accounts))
 }
 
+func TestGetAccountsPage(t *testing.T) {
+	ctx := newTestStub(t)
+
+	requestTestAccount(t, ctx, Org2MSP)
+	requestTestAccount(t, ctx, Org3MSP)
+
+	// add pending accounts directly to the world state
+	for _, name := range []string{"A1MSP", "A2MSP", "A3MSP"} {
+		bytes, err := json.Marshal(model.AccountPublic{Name: name, MSPID: name, Status: model.PendingApproval})
+		require.NoError(t, err)
+		err = ctx.GetStub().PutState(model.AccountKey(name), bytes)
+		require.NoError(t, err)
+	}
+
+	// keys outside the account range are not returned
+	err := ctx.GetStub().PutState("other:key", []byte("{}"))
+	require.NoError(t, err)
+
+	bcc := BlossomSmartContract{}
+
+	t.Run("test pages", func(t *testing.T) {
+		page, err := bcc.GetAccountsPage(ctx, 2, "", "")
+		require.NoError(t, err)
+		require.Len(t, page.Accounts, 2)
+		require.NotNil(t, page.Count)
+		require.Equal(t, 5, *page.Count)
+		require.Equal(t, "A1MSP", page.Accounts[0].Name)
+		require.Equal(t, "A2MSP", page.Accounts[1].Name)
+		require.NotEmpty(t, page.Bookmark)
+
+		page, err = bcc.GetAccountsPage(ctx, 2, page.Bookmark, "")
+		require.NoError(t, err)
+		require.Len(t, page.Accounts, 2)
+		require.Nil(t, page.Count)
+		require.Equal(t, "A3MSP", page.Accounts[0].Name)
+		require.Equal(t, Org2MSP, page.Accounts[1].Name)
+
+		page, err = bcc.GetAccountsPage(ctx, 2, page.Bookmark, "")
+		require.NoError(t, err)
+		require.Len(t, page.Accounts, 1)
+		require.Nil(t, page.Count)
+		require.Equal(t, Org3MSP, page.Accounts[0].Name)
+		require.Empty(t, page.Bookmark)
+	})
+
+	t.Run("test status filter", func(t *testing.T) {
+		page, err := bcc.GetAccountsPage(ctx, 1, "", "AUTHORIZED")
+		require.NoError(t, err)
+		require.Len(t, page.Accounts, 1)
+		require.NotNil(t, page.Count)
+		require.Equal(t, 2, *page.Count)
+		require.Equal(t, Org2MSP, page.Accounts[0].Name)
+
+		page, err = bcc.GetAccountsPage(ctx, 1, page.Bookmark, "AUTHORIZED")
+		require.NoError(t, err)
+		require.Len(t, page.Accounts, 1)
+		require.Nil(t, page.Count)
+		require.Equal(t, Org3MSP, page.Accounts[0].Name)
+		require.Empty(t, page.Bookmark)
+
+		page, err = bcc.GetAccountsPage(ctx, 10, "", "PENDING_APPROVAL")
+		require.NoError(t, err)
+		require.Len(t, page.Accounts, 3)
+		require.NotNil(t, page.Count)
+		require.Equal(t, 3, *page.Count)
+		require.Empty(t, page.Bookmark)
+
+		_, err = bcc.GetAccountsPage(ctx, 10, "", "UNKNOWN")
+		require.Error(t, err)
+	})
+
+	t.Run("test invalid page size", func(t *testing.T) {
+		_, err := bcc.GetAccountsPage(ctx, 0, "", "")
+		require.Error(t, err)
+	})
+}
+
 func TestAccount(t *testing.T) {
 	ctx := newTestStub(t)
 
@@ -142,7 +569,7 @@ func TestAccount(t *testing.T) {
 	require.Equal(t, Org2MSP, acct.Name)
 	require.Equal(t, Org2MSP, acct.MSPID)
 	require.Equal(t, model.Authorized, acct.Status)
-	require.Equal(t, "", acct.ATO)
+	require.Nil(t, acct.ATO)
 	require.Empty(t, acct.Assets)
 
 	acct, err = bcc.GetAccount(ctx, Org3MSP)
@@ -152,3 +579,289 @@ func TestAccount(t *testing.T) {
 	require.Equal(t, model.Authorized, acct.Status)
 	require.Empty(t, acct.Assets)
 }
+
+func TestDecommissionAccount(t *testing.T) {
+	ctx := newTestStub(t)
+	bcc := BlossomSmartContract{}
+
+	onboardTestAsset(t, ctx, "123", "myasset", []string{"1", "2"})
+	requestTestAccount(t, ctx, Org2MSP)
+
+	// checkout a license and report a swid for it
+	require.NoError(t, ctx.SetClientIdentity(mocks.Org2SystemAdmin))
+	require.NoError(t, ctx.SetTransient("checkout", testCheckoutInput("123", 1)))
+	require.NoError(t, bcc.RequestCheckout(ctx))
+
+	require.NoError(t, ctx.SetClientIdentity(mocks.Super))
+	require.NoError(t, ctx.SetTransient("checkout", approveCheckoutTransientInput{Account: Org2MSP, AssetID: "123"}))
+	require.NoError(t, bcc.ApproveCheckout(ctx))
+
+	require.NoError(t, ctx.SetClientIdentity(mocks.Org2SystemAdmin))
+	require.NoError(t, ctx.SetTransient("swid", reportSwIDTransientInput{
+		PrimaryTag: "primary_tag_1",
+		Asset:      "123",
+		License:    "1",
+		Xml:        "swid_xml",
+	}))
+	require.NoError(t, bcc.ReportSwID(ctx))
+
+	require.NoError(t, ctx.SetTransient("checkin", initiateCheckinTransientInput{
+		AssetID:  "123",
+		Licenses: []string{"1"},
+	}))
+	require.NoError(t, bcc.InitiateCheckin(ctx))
+
+	require.NoError(t, ctx.SetClientIdentity(mocks.Org2AcqSpec))
+	require.NoError(t, ctx.SetTransient("acquisition", requestAcquisitionTransientInput{Product: "Editor", Quantity: 1, Justification: "new team"}))
+	require.NoError(t, bcc.RequestAcquisition(ctx))
+
+	t.Run("test authorized account cannot be decommissioned", func(t *testing.T) {
+		require.NoError(t, ctx.SetClientIdentity(mocks.Super))
+		err := bcc.DecommissionAccount(ctx, Org2MSP, "leaving blossom")
+		require.Error(t, err)
+	})
+
+	require.NoError(t, ctx.SetClientIdentity(mocks.Super))
+	require.NoError(t, bcc.UpdateAccountStatus(ctx, Org2MSP, "UNAUTHORIZED_ATO", "ato lapsed"))
+
+	t.Run("test account with licenses cannot be decommissioned", func(t *testing.T) {
+		err := bcc.DecommissionAccount(ctx, Org2MSP, "leaving blossom")
+		require.Error(t, err)
+	})
+
+	t.Run("test unauthorized user", func(t *testing.T) {
+		require.NoError(t, ctx.SetClientIdentity(mocks.Org2SystemOwner))
+		err := bcc.DecommissionAccount(ctx, Org2MSP, "leaving blossom")
+		require.Error(t, err)
+	})
+
+	// opting out reclaims the licenses and cancels the pending checkin
+	require.NoError(t, ctx.SetClientIdentity(mocks.Super))
+	require.NoError(t, bcc.UpdateAccountStatus(ctx, Org2MSP, "UNAUTHORIZED_OPTOUT", "leaving blossom"))
+
+	t.Run("test account with open acquisitions cannot be decommissioned", func(t *testing.T) {
+		err := bcc.DecommissionAccount(ctx, Org2MSP, "leaving blossom")
+		require.EqualError(t, err, `account "Org2MSP" has open acquisitions`)
+
+		acquisitions, err := bcc.GetAcquisitions(ctx, Org2MSP)
+		require.NoError(t, err)
+		require.Len(t, acquisitions, 1)
+
+		require.NoError(t, bcc.DenyAcquisition(ctx, Org2MSP, acquisitions[0].ID, "leaving blossom"))
+	})
+
+	err := bcc.DecommissionAccount(ctx, Org2MSP, "leaving blossom")
+	require.NoError(t, err)
+
+	acct, err := bcc.GetAccount(ctx, Org2MSP)
+	require.NoError(t, err)
+	require.Equal(t, model.Decommissioned, acct.Status)
+	require.Equal(t, "leaving blossom", acct.StatusUpdate.Reason)
+
+	bytes, err := ctx.GetStub().GetPrivateData(Org2Collection, model.SwIDKey("primary_tag_1"))
+	require.NoError(t, err)
+	require.Nil(t, bytes)
+
+	bytes, err = ctx.GetStub().GetPrivateData(Org2Collection, model.AccountKey(Org2MSP))
+	require.NoError(t, err)
+	require.Nil(t, bytes)
+
+	t.Run("test status cannot be updated", func(t *testing.T) {
+		err = bcc.UpdateAccountStatus(ctx, Org2MSP, "PENDING_ATO", "coming back")
+		require.Error(t, err)
+	})
+
+	t.Run("test name cannot be reused", func(t *testing.T) {
+		require.NoError(t, ctx.SetClientIdentity(mocks.Org2SystemOwner))
+		err = bcc.RequestAccount(ctx)
+		require.Error(t, err)
+	})
+}
+
+func TestDecommissionPendingAccount(t *testing.T) {
+	ctx := newTestStub(t)
+	bcc := BlossomSmartContract{}
+
+	require.NoError(t, ctx.SetClientIdentity(mocks.Org2SystemOwner))
+	require.NoError(t, ctx.SetTransient("account", accountTransientInput{
+		SystemOwner:           "org2user1",
+		SystemAdmin:           "org2user2",
+		AcquisitionSpecialist: "org2user3",
+		accountAgencyTransientInput: accountAgencyTransientInput{
+			Department: "Department of Commerce",
+		},
+	}))
+	require.NoError(t, bcc.RequestAccount(ctx))
+
+	// the account was never approved so it has no nodes in the graph
+	require.NoError(t, ctx.SetClientIdentity(mocks.Super))
+	err := bcc.DecommissionAccount(ctx, Org2MSP, "request abandoned")
+	require.NoError(t, err)
+
+	acct, err := bcc.GetAccount(ctx, Org2MSP)
+	require.NoError(t, err)
+	require.Equal(t, model.Decommissioned, acct.Status)
+
+	bytes, err := ctx.GetStub().GetPrivateData(Org2Collection, model.AccountKey(Org2MSP))
+	require.NoError(t, err)
+	require.Nil(t, bytes)
+
+	// other accounts can still be approved
+	requestTestAccount(t, ctx, Org3MSP)
+}
+
+func TestAccountUsers(t *testing.T) {
+	ctx := newTestStub(t)
+	bcc := BlossomSmartContract{}
+
+	onboardTestAsset(t, ctx, "123", "myasset", []string{"1", "2"})
+	requestTestAccount(t, ctx, Org2MSP)
+
+	require.NoError(t, ctx.SetClientIdentity(mocks.Super))
+	acct, err := bcc.GetAccount(ctx, Org2MSP)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"org2user1": model.SystemOwnerRole,
+		"org2user2": model.SystemAdminRole,
+		"org2user3": model.AcquisitionSpecialistRole,
+	}, acct.Users)
+
+	t.Run("test unauthorized user", func(t *testing.T) {
+		// the admin is not a member of the account
+		require.NoError(t, ctx.SetClientIdentity(mocks.Super))
+		err = bcc.RemoveAccountUser(ctx, "org2user2")
+		require.Error(t, err)
+
+		require.NoError(t, ctx.SetClientIdentity(mocks.Org2SystemAdmin))
+		err = bcc.RemoveAccountUser(ctx, "org2user3")
+		require.Error(t, err)
+	})
+
+	t.Run("test other account", func(t *testing.T) {
+		// the account is the MSPID of the requesting user so org3 cannot remove org2's users
+		requestTestAccount(t, ctx, Org3MSP)
+		require.NoError(t, ctx.SetClientIdentity(mocks.Org3SystemOwner))
+		err = bcc.RemoveAccountUser(ctx, "org2user2")
+		require.Error(t, err)
+
+		require.NoError(t, ctx.SetClientIdentity(mocks.Super))
+		acct, err := bcc.GetAccount(ctx, Org2MSP)
+		require.NoError(t, err)
+		require.Contains(t, acct.Users, "org2user2")
+	})
+
+	processUsers := func() {
+		require.NoError(t, ctx.SetClientIdentity(mocks.Super))
+		err := bcc.ProcessAccountUsers(ctx, Org2MSP)
+		require.NoError(t, err)
+	}
+
+	t.Run("test only admin can process users", func(t *testing.T) {
+		require.NoError(t, ctx.SetClientIdentity(mocks.Org2SystemOwner))
+		err = bcc.ProcessAccountUsers(ctx, Org2MSP)
+		require.Error(t, err)
+	})
+
+	t.Run("test removed user loses access", func(t *testing.T) {
+		require.NoError(t, ctx.SetClientIdentity(mocks.Org2SystemOwner))
+		err = bcc.RemoveAccountUser(ctx, "org2user2")
+		require.NoError(t, err)
+
+		// the roster takes effect when the admin registers it in the graph
+		require.NoError(t, ctx.SetClientIdentity(mocks.Org2SystemAdmin))
+		err = decider.CanRequestCheckout(ctx, Org2MSP)
+		require.NoError(t, err)
+
+		processUsers()
+
+		require.NoError(t, ctx.SetClientIdentity(mocks.Org2SystemAdmin))
+		err = decider.CanRequestCheckout(ctx, Org2MSP)
+		require.EqualError(t, err, "user org2user2 is not registered with account Org2MSP")
+	})
+
+	t.Run("test added user gains access", func(t *testing.T) {
+		require.NoError(t, ctx.SetClientIdentity(mocks.Org2SystemOwner))
+		require.NoError(t, ctx.SetTransient("user", accountUserTransientInput{"org2user2", model.SystemAdminRole}))
+		err = bcc.AddAccountUser(ctx)
+		require.NoError(t, err)
+
+		err = bcc.AddAccountUser(ctx)
+		require.Error(t, err)
+
+		processUsers()
+
+		require.NoError(t, ctx.SetClientIdentity(mocks.Org2SystemAdmin))
+		require.NoError(t, ctx.SetTransient("checkout", testCheckoutInput("123", 1)))
+		err = bcc.RequestCheckout(ctx)
+		require.NoError(t, err)
+	})
+
+	t.Run("test invalid role", func(t *testing.T) {
+		require.NoError(t, ctx.SetClientIdentity(mocks.Org2SystemOwner))
+		require.NoError(t, ctx.SetTransient("user", accountUserTransientInput{"org2user4", "Auditor"}))
+		err = bcc.AddAccountUser(ctx)
+		require.Error(t, err)
+	})
+
+	t.Run("test last system owner", func(t *testing.T) {
+		require.NoError(t, ctx.SetClientIdentity(mocks.Org2SystemOwner))
+		err = bcc.RemoveAccountUser(ctx, "org2user1")
+		require.Error(t, err)
+	})
+}
+
+func TestUnregisteredAccountUsers(t *testing.T) {
+	ctx := newTestStub(t)
+	bcc := BlossomSmartContract{}
+
+	requestTestAccount(t, ctx, Org2MSP)
+
+	// accounts approved before users were registered in the graph have no users assigned to the account
+	require.NoError(t, ctx.SetClientIdentity(mocks.Super))
+	store, err := common.GetPvtCollPolicyStore(ctx, collections.Catalog())
+	require.NoError(t, err)
+	err = pap.SetAccountUsers(store, Org2MSP, nil)
+	require.NoError(t, err)
+	err = common.PutPvtCollPolicyStore(ctx, store)
+	require.NoError(t, err)
+
+	t.Run("test certificate role is used", func(t *testing.T) {
+		require.NoError(t, ctx.SetClientIdentity(mocks.Org2SystemAdmin))
+		err = decider.CanRequestCheckout(ctx, Org2MSP)
+		require.NoError(t, err)
+
+		require.NoError(t, ctx.SetClientIdentity(mocks.Org2SystemOwner))
+		err = bcc.RemoveAccountUser(ctx, "org2user3")
+		require.NoError(t, err)
+	})
+
+	t.Run("test roster without system owner is not registered", func(t *testing.T) {
+		require.NoError(t, ctx.SetClientIdentity(mocks.Super))
+		acctPvt, err := getAccountPrivate(ctx, Org2MSP)
+		require.NoError(t, err)
+		delete(acctPvt.Users, "org2user1")
+		err = putAccountPrivate(ctx, Org2MSP, "test", acctPvt)
+		require.NoError(t, err)
+
+		err = bcc.ProcessAccountUsers(ctx, Org2MSP)
+		require.Error(t, err)
+
+		acctPvt.Users["org2user1"] = model.SystemOwnerRole
+		err = putAccountPrivate(ctx, Org2MSP, "test", acctPvt)
+		require.NoError(t, err)
+	})
+
+	t.Run("test registered roster replaces certificate role", func(t *testing.T) {
+		require.NoError(t, ctx.SetClientIdentity(mocks.Super))
+		err = bcc.ProcessAccountUsers(ctx, Org2MSP)
+		require.NoError(t, err)
+
+		require.NoError(t, ctx.SetClientIdentity(mocks.Org2SystemAdmin))
+		err = decider.CanRequestCheckout(ctx, Org2MSP)
+		require.NoError(t, err)
+
+		require.NoError(t, ctx.SetClientIdentity(mocks.Org2AcqSpec))
+		err = decider.CanRequestAcquisition(ctx, Org2MSP)
+		require.Error(t, err)
+	})
+}
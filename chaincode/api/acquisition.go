@@ -0,0 +1,315 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"github.com/usnistgov/blossom/chaincode/collections"
+	"github.com/usnistgov/blossom/chaincode/model"
+	"github.com/usnistgov/blossom/chaincode/ngac/common"
+	decider "github.com/usnistgov/blossom/chaincode/ngac/pdp"
+)
+
+func NewAcquisitionContract() AcquisitionInterface {
+	return &BlossomSmartContract{}
+}
+
+func (b *BlossomSmartContract) RequestAcquisition(ctx contractapi.TransactionContextInterface) error {
+	transientInput, err := getRequestAcquisitionTransientInput(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting transient input: %w", err)
+	}
+
+	account, err := accountName(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting MSPID from stub: %w", err)
+	}
+
+	// ngac check
+	if err = decider.CanRequestAcquisition(ctx, account); err != nil {
+		return fmt.Errorf("ngac check failed: %w", err)
+	}
+
+	acquisition := &model.Acquisition{
+		ID:            ctx.GetStub().GetTxID(),
+		Account:       account,
+		Product:       transientInput.Product,
+		Quantity:      transientInput.Quantity,
+		Justification: transientInput.Justification,
+		Quotes:        make([]model.Quote, 0),
+	}
+
+	return putAcquisition(ctx, acquisition, model.AcquisitionRequested, "")
+}
+
+func (b *BlossomSmartContract) AttachQuote(ctx contractapi.TransactionContextInterface, acquisitionID string) error {
+	transientInput, err := getAttachQuoteTransientInput(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting transient input: %w", err)
+	}
+
+	account, err := accountName(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting MSPID from stub: %w", err)
+	}
+
+	// ngac check
+	if err = decider.CanAttachQuote(ctx, account); err != nil {
+		return fmt.Errorf("ngac check failed: %w", err)
+	}
+
+	acquisition, err := getAcquisition(ctx, account, acquisitionID)
+	if err != nil {
+		return err
+	}
+
+	if acquisition.Status != model.AcquisitionRequested {
+		return fmt.Errorf("quotes cannot be attached to acquisition %s with status %s", acquisitionID, acquisition.Status)
+	}
+
+	if _, ok := acquisition.Quote(transientInput.Digest); ok {
+		return fmt.Errorf("a quote with digest %s is already attached to acquisition %s", transientInput.Digest, acquisitionID)
+	}
+
+	user, err := common.GetUser(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting user from ctx: %w", err)
+	}
+
+	now, err := txTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+
+	acquisition.Quotes = append(acquisition.Quotes, model.Quote{
+		Vendor:      transientInput.Vendor,
+		Digest:      transientInput.Digest,
+		AmountCents: transientInput.AmountCents,
+		URI:         transientInput.URI,
+		AttachedBy:  user,
+		AttachedAt:  now,
+	})
+
+	// attaching a quote does not change the status so it is not recorded in the history
+	return storeAcquisition(ctx, acquisition)
+}
+
+func (b *BlossomSmartContract) ApproveAcquisition(ctx contractapi.TransactionContextInterface, account, acquisitionID string) error {
+	// ngac check
+	if err := decider.CanApproveAcquisition(ctx); err != nil {
+		return fmt.Errorf("ngac check failed: %w", err)
+	}
+
+	acquisition, err := getAcquisition(ctx, account, acquisitionID)
+	if err != nil {
+		return err
+	}
+
+	if acquisition.Status != model.AcquisitionRequested {
+		return fmt.Errorf("acquisition %s cannot be approved with status %s", acquisitionID, acquisition.Status)
+	}
+
+	if len(acquisition.Quotes) == 0 {
+		return fmt.Errorf("acquisition %s does not have any quotes", acquisitionID)
+	}
+
+	return putAcquisition(ctx, acquisition, model.AcquisitionApproved, "")
+}
+
+func (b *BlossomSmartContract) DenyAcquisition(ctx contractapi.TransactionContextInterface, account, acquisitionID, reason string) error {
+	if reason == "" {
+		return fmt.Errorf("a reason is required to deny an acquisition")
+	}
+
+	// ngac check
+	if err := decider.CanApproveAcquisition(ctx); err != nil {
+		return fmt.Errorf("ngac check failed: %w", err)
+	}
+
+	acquisition, err := getAcquisition(ctx, account, acquisitionID)
+	if err != nil {
+		return err
+	}
+
+	if acquisition.Status != model.AcquisitionRequested {
+		return fmt.Errorf("acquisition %s cannot be denied with status %s", acquisitionID, acquisition.Status)
+	}
+
+	return putAcquisition(ctx, acquisition, model.AcquisitionDenied, reason)
+}
+
+func (b *BlossomSmartContract) RecordPurchase(ctx contractapi.TransactionContextInterface, acquisitionID string) error {
+	transientInput, err := getRecordPurchaseTransientInput(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting transient input: %w", err)
+	}
+
+	account, err := accountName(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting MSPID from stub: %w", err)
+	}
+
+	// ngac check
+	if err = decider.CanRecordPurchase(ctx, account); err != nil {
+		return fmt.Errorf("ngac check failed: %w", err)
+	}
+
+	acquisition, err := getAcquisition(ctx, account, acquisitionID)
+	if err != nil {
+		return err
+	}
+
+	if acquisition.Status != model.AcquisitionApproved {
+		return fmt.Errorf("acquisition %s cannot be purchased with status %s", acquisitionID, acquisition.Status)
+	}
+
+	if _, ok := acquisition.Quote(transientInput.QuoteDigest); !ok {
+		return fmt.Errorf("quote %s is not attached to acquisition %s", transientInput.QuoteDigest, acquisitionID)
+	}
+
+	acquisition.Purchase = &model.Purchase{
+		OrderNumber: transientInput.OrderNumber,
+		QuoteDigest: transientInput.QuoteDigest,
+		Licenses:    transientInput.Licenses,
+		Cost:        transientInput.Cost,
+	}
+
+	return putAcquisition(ctx, acquisition, model.AcquisitionPurchased, "")
+}
+
+func (b *BlossomSmartContract) OnboardAcquisition(ctx contractapi.TransactionContextInterface, account, acquisitionID, assetID, name, onboardDate, expiration string) error {
+	// ngac check
+	if err := decider.CanOnboardAcquisition(ctx); err != nil {
+		return fmt.Errorf("ngac check failed: %w", err)
+	}
+
+	acquisition, err := getAcquisition(ctx, account, acquisitionID)
+	if err != nil {
+		return err
+	}
+
+	if acquisition.Status != model.AcquisitionPurchased {
+		return fmt.Errorf("acquisition %s cannot be onboarded with status %s", acquisitionID, acquisition.Status)
+	}
+
+	assetInput := onboardAssetTransientInput{
+		Licenses: acquisition.Purchase.Licenses,
+		Cost:     acquisition.Purchase.Cost,
+	}
+	if err = b.onboardAsset(ctx, assetID, name, onboardDate, expiration, assetInput); err != nil {
+		return fmt.Errorf("error onboarding acquisition %s: %w", acquisitionID, err)
+	}
+
+	acquisition.AssetID = assetID
+
+	return putAcquisition(ctx, acquisition, model.AcquisitionOnboarded, "")
+}
+
+func (b *BlossomSmartContract) GetAcquisitions(ctx contractapi.TransactionContextInterface, account string) ([]*model.Acquisition, error) {
+	return getAcquisitions(ctx, account)
+}
+
+// getAcquisitions returns the acquisitions of the account ordered by when they were requested.
+func getAcquisitions(ctx contractapi.TransactionContextInterface, account string) ([]*model.Acquisition, error) {
+	keys, err := accountPrivateKeys(ctx, account, model.AcquisitionKey(account, ""))
+	if err != nil {
+		return nil, fmt.Errorf("error getting acquisitions of account %q: %w", account, err)
+	}
+
+	acquisitions := make([]*model.Acquisition, 0)
+	for _, key := range keys {
+		bytes, err := ctx.GetStub().GetPrivateData(collections.Account(account), key)
+		if err != nil {
+			return nil, fmt.Errorf("error getting acquisition %s: %w", key, err)
+		}
+
+		acquisition := &model.Acquisition{}
+		if err = json.Unmarshal(bytes, acquisition); err != nil {
+			return nil, fmt.Errorf("error unmarshaling acquisition: %w", err)
+		}
+
+		acquisitions = append(acquisitions, acquisition)
+	}
+
+	// the first update of an acquisition is its request
+	sort.Slice(acquisitions, func(i, j int) bool {
+		a, b := acquisitions[i].History[0].Timestamp, acquisitions[j].History[0].Timestamp
+		if a.Equal(b) {
+			return acquisitions[i].ID < acquisitions[j].ID
+		}
+
+		return a.Before(b)
+	})
+
+	return acquisitions, nil
+}
+
+// openAcquisitions returns the acquisitions of the account that have not been denied or onboarded.
+func openAcquisitions(ctx contractapi.TransactionContextInterface, account string) ([]*model.Acquisition, error) {
+	acquisitions, err := getAcquisitions(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+
+	open := make([]*model.Acquisition, 0)
+	for _, acquisition := range acquisitions {
+		if acquisition.IsOpen() {
+			open = append(open, acquisition)
+		}
+	}
+
+	return open, nil
+}
+
+func (b *BlossomSmartContract) GetAcquisition(ctx contractapi.TransactionContextInterface, account, acquisitionID string) (*model.Acquisition, error) {
+	return getAcquisition(ctx, account, acquisitionID)
+}
+
+func getAcquisition(ctx contractapi.TransactionContextInterface, account, acquisitionID string) (*model.Acquisition, error) {
+	bytes, err := ctx.GetStub().GetPrivateData(collections.Account(account), model.AcquisitionKey(account, acquisitionID))
+	if err != nil {
+		return nil, fmt.Errorf("error getting acquisition %s: %w", acquisitionID, err)
+	} else if bytes == nil {
+		return nil, fmt.Errorf("acquisition %s does not exist for account %s", acquisitionID, account)
+	}
+
+	acquisition := &model.Acquisition{}
+	if err = json.Unmarshal(bytes, acquisition); err != nil {
+		return nil, fmt.Errorf("error unmarshaling acquisition %s: %w", acquisitionID, err)
+	}
+
+	return acquisition, nil
+}
+
+// putAcquisition sets the status of the acquisition, records the update in its history and stores it in the private
+// data collection of the requesting account.
+func putAcquisition(ctx contractapi.TransactionContextInterface, acquisition *model.Acquisition, status model.AcquisitionStatus, reason string) error {
+	update, err := newUpdate(ctx, string(status), reason)
+	if err != nil {
+		return err
+	}
+
+	acquisition.Status = status
+	key := model.AcquisitionKey(acquisition.Account, acquisition.ID)
+	if err = putWithHistory(ctx, collections.Account(acquisition.Account), key, acquisition, &acquisition.History, update); err != nil {
+		return fmt.Errorf("error updating acquisition %s: %w", acquisition.ID, err)
+	}
+
+	return nil
+}
+
+func storeAcquisition(ctx contractapi.TransactionContextInterface, acquisition *model.Acquisition) error {
+	bytes, err := json.Marshal(acquisition)
+	if err != nil {
+		return fmt.Errorf("error marshaling acquisition %s: %w", acquisition.ID, err)
+	}
+
+	key := model.AcquisitionKey(acquisition.Account, acquisition.ID)
+	if err = ctx.GetStub().PutPrivateData(collections.Account(acquisition.Account), key, bytes); err != nil {
+		return fmt.Errorf("error updating acquisition %s: %w", acquisition.ID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/usnistgov/blossom/chaincode/mocks"
+	"github.com/usnistgov/blossom/chaincode/model"
+)
+
+func TestAcquisitions(t *testing.T) {
+	ctx := newTestStub(t)
+	bcc := BlossomSmartContract{}
+
+	requestTestAccount(t, ctx, Org2MSP)
+
+	digest := sha256.Sum256([]byte("quote"))
+	quoteDigest := hex.EncodeToString(digest[:])
+
+	var acquisitionID string
+
+	t.Run("test request acquisition", func(t *testing.T) {
+		input := requestAcquisitionTransientInput{Product: "Editor", Quantity: 2, Justification: "new team"}
+
+		err := ctx.SetClientIdentity(mocks.Org2SystemAdmin)
+		require.NoError(t, err)
+		err = ctx.SetTransient("acquisition", input)
+		require.NoError(t, err)
+		err = bcc.RequestAcquisition(ctx)
+		require.Error(t, err)
+
+		err = ctx.SetClientIdentity(mocks.Org2AcqSpec)
+		require.NoError(t, err)
+		err = ctx.SetTransient("acquisition", input)
+		require.NoError(t, err)
+		err = bcc.RequestAcquisition(ctx)
+		require.NoError(t, err)
+
+		acquisitions, err := bcc.GetAcquisitions(ctx, Org2MSP)
+		require.NoError(t, err)
+		require.Len(t, acquisitions, 1)
+		require.Equal(t, model.AcquisitionRequested, acquisitions[0].Status)
+		acquisitionID = acquisitions[0].ID
+	})
+
+	t.Run("test approve acquisition", func(t *testing.T) {
+		// an acquisition cannot be approved without a quote
+		err := ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		err = bcc.ApproveAcquisition(ctx, Org2MSP, acquisitionID)
+		require.Error(t, err)
+
+		err = ctx.SetClientIdentity(mocks.Org2AcqSpec)
+		require.NoError(t, err)
+		err = ctx.SetTransient("quote", attachQuoteTransientInput{Vendor: "acme", Digest: "abc", AmountCents: 20000})
+		require.NoError(t, err)
+		err = bcc.AttachQuote(ctx, acquisitionID)
+		require.Error(t, err)
+
+		err = ctx.SetTransient("quote", attachQuoteTransientInput{Vendor: "acme", Digest: quoteDigest, AmountCents: 20000})
+		require.NoError(t, err)
+		err = bcc.AttachQuote(ctx, acquisitionID)
+		require.NoError(t, err)
+
+		err = bcc.ApproveAcquisition(ctx, Org2MSP, acquisitionID)
+		require.Error(t, err)
+
+		err = ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		err = bcc.ApproveAcquisition(ctx, Org2MSP, acquisitionID)
+		require.NoError(t, err)
+
+		acquisition, err := bcc.GetAcquisition(ctx, Org2MSP, acquisitionID)
+		require.NoError(t, err)
+		require.Equal(t, model.AcquisitionApproved, acquisition.Status)
+		require.Len(t, acquisition.Quotes, 1)
+	})
+
+	t.Run("test record purchase", func(t *testing.T) {
+		input := recordPurchaseTransientInput{
+			OrderNumber: "PO-1",
+			QuoteDigest: quoteDigest,
+			Licenses:    []model.License{{LicenseID: "e1"}, {LicenseID: "e2"}},
+			Cost:        &model.AssetCost{UnitCostCents: 10000},
+		}
+
+		err := ctx.SetClientIdentity(mocks.Org2AcqSpec)
+		require.NoError(t, err)
+		err = ctx.SetTransient("purchase", recordPurchaseTransientInput{
+			OrderNumber: "PO-1",
+			QuoteDigest: hex.EncodeToString(make([]byte, 32)),
+			Licenses:    input.Licenses,
+		})
+		require.NoError(t, err)
+		err = bcc.RecordPurchase(ctx, acquisitionID)
+		require.Error(t, err)
+
+		err = ctx.SetTransient("purchase", input)
+		require.NoError(t, err)
+		err = bcc.RecordPurchase(ctx, acquisitionID)
+		require.NoError(t, err)
+
+		acquisition, err := bcc.GetAcquisition(ctx, Org2MSP, acquisitionID)
+		require.NoError(t, err)
+		require.Equal(t, model.AcquisitionPurchased, acquisition.Status)
+	})
+
+	t.Run("test onboard acquisition", func(t *testing.T) {
+		err := ctx.SetClientIdentity(mocks.Org2AcqSpec)
+		require.NoError(t, err)
+		err = bcc.OnboardAcquisition(ctx, Org2MSP, acquisitionID, "456", "Editor", "2023-01-01T00:00:00Z", "2030-01-01T00:00:00Z")
+		require.Error(t, err)
+
+		err = ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		err = bcc.OnboardAcquisition(ctx, Org2MSP, acquisitionID, "456", "Editor", "2023-01-01T00:00:00Z", "2030-01-01T00:00:00Z")
+		require.NoError(t, err)
+
+		asset, err := bcc.GetAsset(ctx, "456")
+		require.NoError(t, err)
+		require.Equal(t, 2, asset.Available)
+		require.Equal(t, int64(10000), asset.Cost.UnitCostCents)
+
+		acquisition, err := bcc.GetAcquisition(ctx, Org2MSP, acquisitionID)
+		require.NoError(t, err)
+		require.Equal(t, model.AcquisitionOnboarded, acquisition.Status)
+		require.Equal(t, "456", acquisition.AssetID)
+		require.Len(t, acquisition.History, 4)
+
+		err = bcc.OnboardAcquisition(ctx, Org2MSP, acquisitionID, "789", "Editor", "2023-01-01T00:00:00Z", "2030-01-01T00:00:00Z")
+		require.Error(t, err)
+	})
+
+	t.Run("test deny acquisition", func(t *testing.T) {
+		err := ctx.SetClientIdentity(mocks.Org2AcqSpec)
+		require.NoError(t, err)
+		err = ctx.SetTransient("acquisition", requestAcquisitionTransientInput{Product: "Viewer", Quantity: 1, Justification: "reports"})
+		require.NoError(t, err)
+		err = bcc.RequestAcquisition(ctx)
+		require.NoError(t, err)
+		acquisitions, err := bcc.GetAcquisitions(ctx, Org2MSP)
+		require.NoError(t, err)
+		require.Len(t, acquisitions, 2)
+		id := acquisitions[1].ID
+
+		err = ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		err = bcc.DenyAcquisition(ctx, Org2MSP, id, "")
+		require.Error(t, err)
+		err = bcc.DenyAcquisition(ctx, Org2MSP, id, "use the existing viewer")
+		require.NoError(t, err)
+
+		acquisition, err := bcc.GetAcquisition(ctx, Org2MSP, id)
+		require.NoError(t, err)
+		require.Equal(t, model.AcquisitionDenied, acquisition.Status)
+	})
+}
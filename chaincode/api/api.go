@@ -1,9 +1,11 @@
 package api
 
 import (
+	"fmt"
 	"github.com/hyperledger/fabric-contract-api-go/contractapi"
 	"github.com/usnistgov/blossom/chaincode/model"
 	"github.com/usnistgov/blossom/chaincode/ngac/pdp"
+	"time"
 )
 
 type (
@@ -17,19 +19,46 @@ type (
 	AccountInterface interface {
 		// RequestAccount allows accounts to request an account in the Blossom system. The name of the account is the
 		// MSPID of the requesting user's member. Only users with the system_owner attribute can call this function
-		// for their organization. Account names cannot be deleted because each account has their own private data collection
-		// and they cannot be deleted.
+		// for their organization. The transient input registers the usernames (certificate common names) of the
+		// account's system owner, system administrator and acquisition specialist, and the department (required),
+		// sub-agency and points of contact of the account. The system owner must be the requesting user and defaults
+		// to them if it is empty. Account names cannot be deleted because each account has their own private data collection
+		// and they cannot be deleted. A decommissioned account leaves a tombstone so the name cannot be requested again.
+		// TRANSIENT MAP: export ACCOUNT=$(echo -n "{\"system_owner\":\"\",\"system_admin\":\"\",\"acquisition_specialist\":\"\",\"department\":\"\",\"sub_agency\":\"\",\"points_of_contact\":[]}" | base64 | tr -d \\n)
 		RequestAccount(ctx contractapi.TransactionContextInterface) error
 
 		// ApproveAccount initializes the account's NGAC graph in the account's PDC, with the user invoking this function
-		// being the admin in the graph.  The status of the account will be Pending after execution.  The admin user can
-		// call UpdateAccountStatus to update the status of the account.
+		// being the admin in the graph.  The users registered in RequestAccount are added to the graph under the
+		// account's user attribute and the user attribute of their role. The status of the account will be Pending
+		// after execution.  The admin user can call UpdateAccountStatus to update the status of the account.
 		ApproveAccount(ctx contractapi.TransactionContextInterface, account string) error
 
-		// UploadATO updates the ATO field of the Account with the given name. This is just the ATO attestation not a full ATO.
-		// TRANSIENT MAP: export ATO=$(echo -n "{\"ato\":\"\"}" | base64 | tr -d \\n)
+		// UploadATO updates the ATO of the account of the requesting user. The ATO records the authorizing official, the
+		// name of the system, the issue and expiration dates (RFC 3339), the FIPS 199 impact level (LOW, MODERATE, HIGH),
+		// and the hex encoded SHA-256 digest of the ATO document. An ATO that has already expired is rejected.
+		// TRANSIENT MAP: export ATO=$(echo -n "{\"authorizing_official\":\"\",\"system_name\":\"\",\"issue_date\":\"\",\"expiration_date\":\"\",\"impact_level\":\"\",\"digest\":\"\"}" | base64 | tr -d \\n)
 		UploadATO(ctx contractapi.TransactionContextInterface) error
 
+		// RegisterEncryptionKey sets the RSA public key (PEM, at least 2048 bits) of the account of the requesting user.
+		// The keys of licenses checked out to the account afterwards are sealed to it with RSA-OAEP and AES-GCM, so only
+		// the holder of the private key can read them. If the key changes, the keys of licenses already held that were
+		// sealed to the previous key are cleared until SealLicenseKeys seals them again. Only the system owner can
+		// register the key. Sealing only protects the copy of a key written to the account's private data collection,
+		// the plaintext keys stay in the licenses collection where every peer of the admin member can read them.
+		// TRANSIENT MAP: export ENCRYPTION_KEY=$(echo -n "{\"public_key\":\"\"}" | base64 | tr -d \\n)
+		RegisterEncryptionKey(ctx contractapi.TransactionContextInterface) error
+
+		// SealLicenseKeys seals the keys of every license held by the account that does not have a sealed key to the
+		// account's encryption key. It is run after the account registers or changes its key, since the license keys
+		// are only readable by the admin. Only the admin can seal license keys.
+		SealLicenseKeys(ctx contractapi.TransactionContextInterface, account string) error
+
+		// SweepExpiredATOs updates the status of every authorized account whose ATO has expired as of the transaction
+		// timestamp to UNAUTHORIZED_ATO. Accounts without an ATO expiration date on record, such as ATOs uploaded before
+		// they were structured, are not updated. The names of the updated accounts are returned. Only the admin can
+		// perform the sweep.
+		SweepExpiredATOs(ctx contractapi.TransactionContextInterface) ([]string, error)
+
 		// UpdateAccountStatus updates the status of an account in Blossom. The status is one of:
 		//		"PENDING_APPROVAL",
 		//		"PENDING_ATO",
@@ -42,17 +71,72 @@ type (
 		// Updating the status to Authorized allows the account to read and write to blossom.
 		// Updating the status to Pending allows the account to read write only account related information such as ATOs.
 		// Updating the status to Inactive provides the same NGAC consequences as Pending
-		UpdateAccountStatus(ctx contractapi.TransactionContextInterface, account string, status string) error
+		// Only the transitions declared in the model are allowed (i.e. a denied account cannot be authorized without
+		// first waiting for an ATO). The reason is required and is recorded along with the user, transaction ID and
+		// transaction timestamp of the update.
+		// Updating the status to UNAUTHORIZED_OPTOUT checks in every license the account has checked out, cancels the
+		// account's open checkout requests, transfers and offers and pending checkin and lease renewal requests, and
+		// emits a LicensesReclaimed event summarizing both. Offers claimed by the account are opened again. The
+		// reclaimed licenses are offered to the waitlist of each asset.
+		UpdateAccountStatus(ctx contractapi.TransactionContextInterface, account string, status string, reason string) error
+
+		// UpdateAccountAgency updates the department, sub-agency and points of contact of the requesting account. The
+		// account is the MSPID of the requesting user. The department is required. Each point of contact requires a name
+		// and email. The department and sub-agency are public, the points of contact are stored in the account's private
+		// data collection.
+		// TRANSIENT MAP: export AGENCY=$(echo -n "{\"department\":\"\",\"sub_agency\":\"\",\"points_of_contact\":[{\"name\":\"\",\"email\":\"\",\"phone\":\"\",\"title\":\"\"}]}" | base64 | tr -d \\n)
+		UpdateAccountAgency(ctx contractapi.TransactionContextInterface) error
+
+		// DecommissionAccount removes an account from Blossom. The account must not hold any licenses or have any open
+		// checkout, checkin or lease renewal requests, transfers, offers or acquisitions. The account's SwIDs and private
+		// info are deleted from its private data collection, the account object and user attribute are removed from the
+		// NGAC graph, and the public account info is left on the ledger with the status Decommissioned as a tombstone so
+		// the account name cannot be reused.
+		// The account must be in a status that allows decommissioning (i.e. opted out).
+		DecommissionAccount(ctx contractapi.TransactionContextInterface, account string, reason string) error
+
+		// AddAccountUser adds a user to the roster of the requesting account. The account is the MSPID of the requesting
+		// user, who must be a system owner of the account. The roster is kept in the account's private data collection
+		// and takes effect when the admin registers it in the NGAC graph with ProcessAccountUsers, because the graph is
+		// only writable by the admin member. Only users registered with an account in the graph can access it,
+		// regardless of the blossom.role attribute in their certificate.
+		// TRANSIENT MAP: export USER=$(echo -n "{\"username\":\"\",\"role\":\"\"}" | base64 | tr -d \\n)
+		AddAccountUser(ctx contractapi.TransactionContextInterface) error
+
+		// RemoveAccountUser removes a user from the roster of the requesting account. The user's access is revoked when
+		// the admin registers the roster with ProcessAccountUsers. The last system owner of an account cannot be removed.
+		RemoveAccountUser(ctx contractapi.TransactionContextInterface, username string) error
+
+		// ProcessAccountUsers registers the users on the account's roster in the NGAC graph under the account's user
+		// attribute and the user attribute of their role, and removes the users that are no longer on the roster. The
+		// roster must have a system owner. Until an account has users registered in the graph, i.e. accounts approved
+		// before users were registered, its users are assigned the role in the blossom.role attribute of their
+		// certificate. Only the admin can process the users of an account.
+		ProcessAccountUsers(ctx contractapi.TransactionContextInterface, account string) error
 
 		// GetAccounts returns the public info of all accounts that are registered with Blossom.
 		GetAccounts(ctx contractapi.TransactionContextInterface) ([]*model.AccountPublic, error)
 
+		// GetAccountsPage returns a page of at most pageSize accounts starting at the bookmark, which is empty for the first
+		// page. The returned bookmark is passed to the next call to get the next page and is empty after the last page.
+		// If statusFilter is not empty only accounts with that status are returned. The filter is one of the statuses
+		// accepted by UpdateAccountStatus or "DECOMMISSIONED". The first page also has the count of accounts that match
+		// the filter across all pages. Counting scans every account on the ledger, not just the page, so a first page
+		// costs as much as GetAccounts. Later pages omit the count.
+		GetAccountsPage(ctx contractapi.TransactionContextInterface, pageSize int32, bookmark string, statusFilter string) (*model.AccountsPage, error)
+
 		// GetAccount returns the account information of the account with the provided name.  Any fields of any account the user
 		// does not have access to will not be returned.
 		GetAccount(ctx contractapi.TransactionContextInterface, account string) (*model.Account, error)
 
-		// GetHistory returns the transaction history of the account.
+		// GetHistory returns the transaction history of the account's public info.
 		GetHistory(ctx contractapi.TransactionContextInterface, account string) ([]model.HistorySnapshot, error)
+
+		// GetAccountPrivateHistory returns the history of the account's private info, such as ATO uploads and license
+		// checkouts, ordered from oldest to newest. Each entry holds the transaction ID, timestamp, user, the transaction
+		// that made the change and the SHA-256 hash of the new private info. Only members of the account's private data
+		// collection can read the history.
+		GetAccountPrivateHistory(ctx contractapi.TransactionContextInterface, account string) ([]model.PrivateHistoryEntry, error)
 	}
 
 	// AssetInterface provides the functions to interact with Assets in fabric.
@@ -60,41 +144,151 @@ type (
 		// OnboardAsset adds a new software asset to Blossom.  This will create a new asset object on the ledger and in the
 		// NGAC graph. Assets are identified by the ID field. The user performing the request will need to
 		// have permission to add an asset to the ledger. The asset will be an object attribute in NGAC and the
-		// asset licenses will be objects that are assigned to the asset.
-		// TRANSIENT MAP: export ATO=$(echo -n "{\"licenses\":\"\"}" | base64 | tr -d \\n)
+		// asset licenses will be objects that are assigned to the asset. The onboarding date and expiration are RFC 3339
+		// dates and the expiration must be after the onboarding date. Each license expiration is an RFC 3339 date between
+		// the onboarding date and the asset expiration; a license without an expiration expires with the asset. The
+		// optional cost is the yearly cost of one license in cents, the funding source and the contract line item (CLIN)
+		// used to charge accounts for the licenses they lease. Licenses can record the contract batch they were purchased
+		// in. The optional allocation strategy (POOL_ORDER, SOONEST_EXPIRING, LATEST_EXPIRING, SAME_BATCH or EXPLICIT)
+		// decides which licenses are selected when licenses are checked out and defaults to POOL_ORDER. A license can
+		// have a secret key, which is kept in the licenses collection and sealed to the encryption key of the account it
+		// is checked out to.
+		// TRANSIENT MAP: export ASSET=$(echo -n "{\"licenses\":[{\"license_id\":\"\",\"expiration\":\"\",\"batch\":\"\",\"key\":\"\"}], \"cost\":{\"unit_cost_cents\":, \"funding_source\":\"\", \"contract_line_item\":\"\"}, \"allocation_strategy\":\"\"}" | base64 | tr -d \\n)
 		OnboardAsset(ctx contractapi.TransactionContextInterface, id string, name string, onboardDate string, expiration string) error
 
 		// OffboardAsset removes an existing asset in Blossom.  This will remove the license from the ledger
 		// and from NGAC. An error will be returned if there are any accounts that have checked out the asset
-		// and the licenses are not returned
+		// and the licenses are not returned, or if any requests are still waitlisted for the asset
 		OffboardAsset(ctx contractapi.TransactionContextInterface, id string) error
 
+		// ForceOffboardAsset offboards an asset that still has licenses checked out, such as when a vendor contract is
+		// terminated early. Every checked out license is revoked and removed from the holding accounts, open checkout
+		// requests, transfers and offers for the asset are cancelled, pending checkin and lease renewal requests for the
+		// asset are deleted, and the SwIDs associated with the asset are flagged with the reason. The asset is then
+		// offboarded as in OffboardAsset. A reason is required.
+		ForceOffboardAsset(ctx contractapi.TransactionContextInterface, id string, reason string) error
+
+		// AddLicenses adds licenses to an onboarded asset. The licenses are available to be checked out immediately and
+		// are first offered to the asset's waitlist. License IDs must not already exist for the asset. License expirations are validated as in OnboardAsset.
+		// TRANSIENT MAP: export LICENSES=$(echo -n "{\"licenses\":[{\"license_id\":\"\",\"expiration\":\"\",\"batch\":\"\",\"key\":\"\"}]}" | base64 | tr -d \\n)
+		AddLicenses(ctx contractapi.TransactionContextInterface, id string) error
+
+		// RetireLicenses removes licenses from an onboarded asset. Only available licenses can be retired, an error is
+		// returned if any of the licenses are checked out.
+		// TRANSIENT MAP: export LICENSES=$(echo -n "{\"licenses\":[\"\"]}" | base64 | tr -d \\n)
+		RetireLicenses(ctx contractapi.TransactionContextInterface, id string) error
+
+		// UpdateAssetExpiration updates the date the asset expires from Blossom. The expiration is an RFC 3339 date that
+		// must be after the onboarding date and cannot be before the expiration of any of the asset's licenses.
+		UpdateAssetExpiration(ctx contractapi.TransactionContextInterface, id string, expiration string) error
+
+		// UpdateAssetCost sets the cost of the asset. Leases that are still held are charged at the new cost from the
+		// start of the lease, leases that have already ended keep the charges recorded when they ended.
+		// TRANSIENT MAP: export COST=$(echo -n "{\"unit_cost_cents\":, \"funding_source\":\"\", \"contract_line_item\":\"\"}" | base64 | tr -d \\n)
+		UpdateAssetCost(ctx contractapi.TransactionContextInterface, id string) error
+
+		// UpdateAllocationStrategy sets the strategy used to select the licenses of the asset when they are checked out.
+		// Licenses already checked out are not affected.
+		UpdateAllocationStrategy(ctx contractapi.TransactionContextInterface, id string, strategy string) error
+
+		// MigrateDates rewrites the assets and the licenses held by accounts that were stored before dates were validated so
+		// every date is an RFC 3339 date. Legacy dates in the YYYY-MM-DD and MM/DD/YYYY formats are converted. An
+		// onboarding date that cannot be parsed is set to the transaction timestamp, an asset expiration that cannot be
+		// parsed is set to the latest license expiration, and a license expiration that cannot be parsed or outlives its
+		// asset is set to the asset expiration. Records that cannot be migrated are left unchanged and reported as skipped.
+		// The migration can be run more than once. Only the admin can perform the migration.
+		MigrateDates(ctx contractapi.TransactionContextInterface) (*model.DateMigration, error)
+
+		// SweepExpiredLicenses removes every license that has expired as of the transaction timestamp. Expired licenses
+		// that are available are removed from the asset, and expired licenses that are checked out are revoked from the
+		// account holding them. A single LicensesExpired event reports the licenses removed from each asset and revoked
+		// from each account, and the same summary is returned. Only the admin can perform the sweep.
+		SweepExpiredLicenses(ctx contractapi.TransactionContextInterface) (*model.LicensesExpired, error)
+
 		// GetAssets returns all software assets in Blossom. This information includes which accounts have licenses for each
-		// asset.
+		// asset. The status of each asset is "pending" before its onboarding date, "expired" at or after its expiration,
+		// and "active" otherwise, as of the transaction timestamp.
 		GetAssets(ctx contractapi.TransactionContextInterface) ([]*model.AssetPublic, error)
 
-		// GetAsset returns the info for the asset with the given asset ID.
+		// GetAsset returns the info for the asset with the given asset ID, including its status as in GetAssets.
 		GetAsset(ctx contractapi.TransactionContextInterface, id string) (*model.Asset, error)
 
+		// GetExpiringAssets returns the assets that have not expired but expire within the given number of days of the
+		// transaction timestamp, soonest first, so contract renewals can be started on time. Only the admin can view
+		// expiring assets.
+		GetExpiringAssets(ctx contractapi.TransactionContextInterface, days int) ([]*model.AssetPublic, error)
+
+		// GetWaitlist returns the waitlist of the asset with the given asset ID. Entries are in the order they will be
+		// offered licenses: highest priority first, then first come first served. Entries for requests cancelled by the
+		// account are dropped the next time licenses are offered.
+		GetWaitlist(ctx contractapi.TransactionContextInterface, id string) (*model.Waitlist, error)
+
+		// GetLicenseRollup aggregates the number of licenses of each asset checked out by accounts up the agency
+		// hierarchy. A roll-up is returned for each department with the roll-ups of its sub-agencies and accounts
+		// as children. Accounts that have not recorded a department are rolled up under "Unassigned".
+		GetLicenseRollup(ctx contractapi.TransactionContextInterface) ([]*model.LicenseRollup, error)
+
+		// GetChargebacks returns the chargeback ledger of the account for each federal fiscal quarter (e.g. FY2027Q1 is
+		// October through December 2026) it leased licenses in. Leases are charged the yearly unit cost of the asset
+		// prorated over the time they were held, up to the lease end. A lease is charged when it ends by a checkin,
+		// transfer or expiration, and leases that are still held are charged up to the transaction timestamp and marked as
+		// accrued. Assets without a cost are not charged. Only the admin can view chargebacks.
+		GetChargebacks(ctx contractapi.TransactionContextInterface, account string) ([]*model.ChargebackLedger, error)
+
+		// GetCostAllocation returns the total charged to each account and for each asset in the fiscal period, computed as
+		// in GetChargebacks. If the period is empty every period is included. Only the admin can view the cost
+		// allocation.
+		GetCostAllocation(ctx contractapi.TransactionContextInterface, period string) (*model.CostAllocation, error)
+
+		// GetAssetHistory returns the history of the asset's private info (licenses and which accounts have them checked
+		// out) ordered from oldest to newest. The history is kept after the asset is offboarded.
+		GetAssetHistory(ctx contractapi.TransactionContextInterface, id string) ([]model.PrivateHistoryEntry, error)
+
 		// RequestCheckout requests software licenses for an account.  The requesting user must have permission to request
 		// (i.e. System Administrator). The amount parameter is the amount of software licenses the account is requesting.
 		// This number is subtracted from the total available for the asset. Returns the set of licenses that are now assigned to
-		// the account.
-		// TRANSIENT MAP: export CHECKOUT=$(echo -n "{\"asset_id\":\"\", \"amount\":}" | base64 | tr -d \\n)
+		// the account. An account can have one pending request per asset. The business justification, lease term in days
+		// (1 to 365), target FISMA system and RFC 3339 needed-by date are required for approvers to review the request.
+		// TRANSIENT MAP: export CHECKOUT=$(echo -n "{\"asset_id\":\"\", \"amount\":, \"justification\":\"\", \"lease_term_days\":, \"fisma_system\":\"\", \"needed_by\":\"\"}" | base64 | tr -d \\n)
 		RequestCheckout(ctx contractapi.TransactionContextInterface) error
 
-		// GetCheckoutRequests returns an array of checkout requests made by the account.
+		// GetCheckoutRequests returns the checkout requests made by the account, including resolved requests, in the order
+		// they were made. Each request has its current status (PENDING, WAITLISTED, APPROVED, PARTIALLY_APPROVED,
+		// DENIED, CANCELLED) and the history of its status changes.
 		GetCheckoutRequests(ctx contractapi.TransactionContextInterface, account string) ([]CheckoutRequest, error)
 
-		// ApproveCheckout approves a checkout request made by an account.  The requested licenses for the asset will be
-		// added to the account's private data collection. A user on the account can then call Licenses to get the approved
-		// license keys.
-		// TRANSIENT MAP: export CHECKOUT=$(echo -n "{\"account\":\"\", \"asset_id\":\"\"}" | base64 | tr -d \\n)
+		// ApproveCheckout approves the pending checkout request made by an account for an asset.  The requested licenses
+		// for the asset will be added to the account's private data collection. A user on the account can then call
+		// Licenses to get the approved license keys. Licenses that have expired as of the transaction timestamp are
+		// skipped. Each license is leased for the term requested, ending no later than the license expires. An amount
+		// less than the amount requested can be given to partially approve the request. The request is kept with its
+		// status set to APPROVED or PARTIALLY_APPROVED. If there are not enough unexpired licenses available, or other
+		// requests are already waiting, the request is set to WAITLISTED and joins the asset's waitlist in the catalog,
+		// ordered by the optional priority (higher first) and then by when it was approved. Licenses returned by
+		// ProcessCheckin, reclaimed from an account or added by AddLicenses are offered to the head of the waitlist,
+		// which is checked out in full before any request behind it. The licenses are selected using the allocation
+		// strategy of the asset, which is recorded on the request. For assets with the EXPLICIT strategy the approver
+		// must choose the licenses, and the number chosen is the amount approved; licenses offered from the waitlist are
+		// selected in pool order.
+		// TRANSIENT MAP: export CHECKOUT=$(echo -n "{\"account\":\"\", \"asset_id\":\"\", \"amount\":, \"reason\":\"\", \"priority\":, \"licenses\":[]}" | base64 | tr -d \\n)
 		ApproveCheckout(ctx contractapi.TransactionContextInterface) error
 
-		// GetLicenses get the license keys for an asset that an account has access to in their private data collection.
-		// The account is extracted from the requesting identity.
-		GetLicenses(ctx contractapi.TransactionContextInterface, account, assetID string) (map[string]string, error)
+		// DenyCheckout denies the pending or waitlisted checkout request made by an account for an asset. A reason is
+		// required. The request is removed from the waitlist and kept with its status set to DENIED.
+		// TRANSIENT MAP: export CHECKOUT=$(echo -n "{\"account\":\"\", \"asset_id\":\"\", \"reason\":\"\"}" | base64 | tr -d \\n)
+		DenyCheckout(ctx contractapi.TransactionContextInterface) error
+
+		// CancelCheckout cancels the pending or waitlisted checkout request made by the account of the requesting user for
+		// an asset. The request is kept with its status set to CANCELLED.
+		// TRANSIENT MAP: export CHECKOUT=$(echo -n "{\"asset_id\":\"\", \"reason\":\"\"}" | base64 | tr -d \\n)
+		CancelCheckout(ctx contractapi.TransactionContextInterface) error
+
+		// GetLicenses get the license keys for an asset that an account has access to in their private data collection,
+		// along with the expiration of each license and the end of its lease. If the license has a key and the account
+		// registered an encryption key, the sealed key can only be opened with the account's private key, e.g. with the
+		// licensekey command. The plaintext key of the license is still kept in the licenses collection, which is
+		// readable by every peer of the admin member.
+		GetLicenses(ctx contractapi.TransactionContextInterface, account, assetID string) (map[string]model.LicenseLease, error)
 
 		// InitiateCheckin starts the process of returning licenses to Blossom. This is serves as a request to the blossom
 		// admin to process the return of the licenses. This is because only the blossom admin can write to the licenses
@@ -106,9 +300,176 @@ type (
 		GetInitiatedCheckins(ctx contractapi.TransactionContextInterface, account string) ([]CheckinRequest, error)
 
 		// ProcessCheckin processes an account's checkin request (from InitiateCheckin) and returns the licenses to the
-		// available pool in the licenses private data collection.
+		// available pool in the licenses private data collection. The returned licenses are then offered to the asset's
+		// waitlist.
 		// TRANSIENT MAP: export CHECKIN=$(echo -n "{\"asset_id\":\"\", \"account\":\"\"}" | base64 | tr -d \\n)
 		ProcessCheckin(ctx contractapi.TransactionContextInterface) error
+
+		// RequestLeaseRenewal requests the extension of the leases of licenses the account has checked out for an asset
+		// by the given term in days. If no licenses are given, all of the account's licenses for the asset are renewed.
+		// The request must be made before each lease ends and cannot extend a lease that already ends when the license
+		// expires. Like InitiateCheckin, this serves as a request to the blossom admin, as only the admin can write to
+		// the licenses private data collection. An account can have one pending renewal request per asset.
+		// TRANSIENT MAP: export RENEWAL=$(echo -n "{\"asset_id\":\"\", \"licenses\":[], \"term_days\":}" | base64 | tr -d \\n)
+		RequestLeaseRenewal(ctx contractapi.TransactionContextInterface) error
+
+		// GetLeaseRenewalRequests returns the pending lease renewal requests of the given account.
+		GetLeaseRenewalRequests(ctx contractapi.TransactionContextInterface, account string) ([]LeaseRenewalRequest, error)
+
+		// ProcessLeaseRenewal processes an account's lease renewal request (from RequestLeaseRenewal). Each lease is
+		// extended by the term from its current end, but not past the license expiration. Licenses checked in since the
+		// request was made are skipped.
+		// TRANSIENT MAP: export RENEWAL=$(echo -n "{\"account\":\"\", \"asset_id\":\"\"}" | base64 | tr -d \\n)
+		ProcessLeaseRenewal(ctx contractapi.TransactionContextInterface) error
+	}
+
+	// TransferInterface provides the functions to transfer checked out licenses directly between accounts without
+	// returning them to the available pool where any account could check them out.
+	TransferInterface interface {
+		// ProposeTransfer proposes to transfer licenses the account of the requesting user has checked out to another
+		// account. The licenses must not be part of a pending checkin request or another open transfer. The transfer,
+		// identified by the ID of the proposing transaction, is stored in the world state with the status PROPOSED so
+		// the recipient can see it. The license IDs are only stored in the donating account's private data collection.
+		// TRANSIENT MAP: export TRANSFER=$(echo -n "{\"asset_id\":\"\", \"licenses\":[], \"to\":\"\"}" | base64 | tr -d \\n)
+		ProposeTransfer(ctx contractapi.TransactionContextInterface) error
+
+		// AcceptTransfer accepts a proposed transfer to the account of the requesting user. The status of the transfer
+		// is set to ACCEPTED and it waits for the admin to approve it.
+		AcceptTransfer(ctx contractapi.TransactionContextInterface, transferID string) error
+
+		// ApproveTransfer approves an accepted transfer. The licenses, with their expiration and lease end, are moved
+		// from the donating account to the recipient in both accounts' private info and in the asset's checked out
+		// licenses in the same transaction. The donating account must still hold the licenses and the recipient must be
+		// authorized. Only the admin can approve a transfer.
+		ApproveTransfer(ctx contractapi.TransactionContextInterface, transferID string) error
+
+		// CancelTransfer cancels a transfer that has not been approved. The donating account can withdraw the proposal,
+		// the recipient can decline it and the admin can deny it. A reason is required. The transfer is kept with its
+		// status set to CANCELLED.
+		CancelTransfer(ctx contractapi.TransactionContextInterface, transferID string, reason string) error
+
+		// GetTransfers returns the transfers the account is donating or receiving licenses in, in the order they were
+		// proposed, with the history of their status changes.
+		GetTransfers(ctx contractapi.TransactionContextInterface, account string) ([]*model.Transfer, error)
+	}
+
+	// MarketplaceInterface provides the functions for accounts to offer checked out licenses they do not need to other
+	// accounts.
+	MarketplaceInterface interface {
+		// PostOffer offers licenses the account of the requesting user has checked out on the marketplace until the
+		// given date. The licenses must not be part of a pending checkin request, an open transfer or another open offer.
+		// The offer, identified by the ID of the posting transaction, is stored in the world state with the status OPEN
+		// and the number of licenses offered. The license IDs are only stored in the offering account's private data
+		// collection.
+		// TRANSIENT MAP: export OFFER=$(echo -n "{\"asset_id\":\"\", \"licenses\":[], \"available_until\":\"\"}" | base64 | tr -d \\n)
+		PostOffer(ctx contractapi.TransactionContextInterface) error
+
+		// GetOffers returns the offers that can be claimed, in the order they were posted. Only users of authorized
+		// accounts can browse the offers. The license IDs are not included.
+		GetOffers(ctx contractapi.TransactionContextInterface) ([]*model.Offer, error)
+
+		// GetOffer returns the offer with the given ID and the history of its status changes.
+		GetOffer(ctx contractapi.TransactionContextInterface, offerID string) (*model.Offer, error)
+
+		// ClaimOffer claims an open offer for the account of the requesting user with the lease term, in days, the
+		// licenses are needed for. The offer must still be available and an account cannot claim its own offer. The
+		// status of the offer is set to CLAIMED and it waits for the admin to process the claim.
+		ClaimOffer(ctx contractapi.TransactionContextInterface, offerID string, leaseTermDays int) error
+
+		// ProcessOfferClaim checks in the offered licenses from the offering account and checks them out to the account
+		// that claimed the offer with a new lease for the claimed term, in the same transaction. The number of available
+		// licenses does not change. The offering account must still hold the licenses and the claiming account must be
+		// authorized. Only the admin can process a claim.
+		ProcessOfferClaim(ctx contractapi.TransactionContextInterface, offerID string) error
+
+		// WithdrawOffer withdraws an offer that has not been completed. The offering account and the admin can withdraw
+		// an offer. A reason is required. The offer is kept with its status set to WITHDRAWN.
+		WithdrawOffer(ctx contractapi.TransactionContextInterface, offerID string, reason string) error
+	}
+
+	// ForecastInterface provides the functions for accounts to forecast their demand for licenses so the admin can plan
+	// procurements.
+	ForecastInterface interface {
+		// SubmitForecast records the number of licenses of a product the account of the requesting user expects to need
+		// in a federal fiscal year, with a justification. The fiscal year cannot be before the current fiscal year. The
+		// forecast, identified by the ID of the submitting transaction, is stored in the account's private data
+		// collection.
+		// TRANSIENT MAP: export FORECAST=$(echo -n "{\"product\":\"\", \"quantity\":1, \"fiscal_year\":2027, \"justification\":\"\"}" | base64 | tr -d \\n)
+		SubmitForecast(ctx contractapi.TransactionContextInterface) error
+
+		// WithdrawForecast deletes a forecast the account of the requesting user submitted.
+		WithdrawForecast(ctx contractapi.TransactionContextInterface, forecastID string) error
+
+		// GetForecasts returns the forecasts submitted by the account, in the order they were submitted. Only users of
+		// the account and the admin can read them.
+		GetForecasts(ctx contractapi.TransactionContextInterface, account string) ([]*model.DemandForecast, error)
+
+		// GetDemand sums the forecasts of every account that has not been decommissioned for each product and fiscal
+		// year, ordered by fiscal year and product. Product names are compared ignoring case and surrounding spaces. If
+		// the fiscal year is 0 every fiscal year is included. Only the admin can view the demand.
+		GetDemand(ctx contractapi.TransactionContextInterface, fiscalYear int) ([]*model.DemandAggregate, error)
+	}
+
+	// AcquisitionInterface provides the functions for acquisition specialists to procure new software and for the admin
+	// to approve the procurement and onboard the purchased licenses.
+	AcquisitionInterface interface {
+		// RequestAcquisition requests the procurement of licenses of a software product for the account of the
+		// requesting user. Only acquisition specialists can request an acquisition. The acquisition, identified by the
+		// ID of the requesting transaction, is stored in the account's private data collection with the status REQUESTED.
+		// TRANSIENT MAP: export ACQUISITION=$(echo -n "{\"product\":\"\", \"quantity\":1, \"justification\":\"\"}" | base64 | tr -d \\n)
+		RequestAcquisition(ctx contractapi.TransactionContextInterface) error
+
+		// AttachQuote attaches a vendor quote, identified by the hex encoded SHA-256 digest of the quote document, to a
+		// requested acquisition of the account of the requesting user. Only acquisition specialists can attach quotes.
+		// TRANSIENT MAP: export QUOTE=$(echo -n "{\"vendor\":\"\", \"digest\":\"\", \"amount_cents\":0, \"uri\":\"\"}" | base64 | tr -d \\n)
+		AttachQuote(ctx contractapi.TransactionContextInterface, acquisitionID string) error
+
+		// ApproveAcquisition approves a requested acquisition of the account. The acquisition must have at least one
+		// quote. Only the admin can approve an acquisition.
+		ApproveAcquisition(ctx contractapi.TransactionContextInterface, account string, acquisitionID string) error
+
+		// DenyAcquisition denies a requested acquisition of the account with a reason. Only the admin can deny an
+		// acquisition.
+		DenyAcquisition(ctx contractapi.TransactionContextInterface, account string, acquisitionID string, reason string) error
+
+		// RecordPurchase records the purchase of an approved acquisition of the account of the requesting user with the
+		// purchased license pool. The purchase must be made from one of the attached quotes. Only acquisition specialists
+		// can record a purchase.
+		// TRANSIENT MAP: export PURCHASE=$(echo -n "{\"order_number\":\"\", \"quote_digest\":\"\", \"licenses\":[{\"license_id\":\"\"}]}" | base64 | tr -d \\n)
+		RecordPurchase(ctx contractapi.TransactionContextInterface, acquisitionID string) error
+
+		// OnboardAcquisition onboards the license pool of a purchased acquisition of the account as a new asset, as
+		// OnboardAsset does, and sets the status of the acquisition to ONBOARDED. Only the admin can onboard an
+		// acquisition.
+		OnboardAcquisition(ctx contractapi.TransactionContextInterface, account string, acquisitionID string, assetID string, name string, onboardDate string, expiration string) error
+
+		// GetAcquisitions returns the acquisitions of the account, in the order they were requested, with the history of
+		// their status changes. Only users of the account and the admin can read them.
+		GetAcquisitions(ctx contractapi.TransactionContextInterface, account string) ([]*model.Acquisition, error)
+
+		// GetAcquisition returns the acquisition of the account with the given ID.
+		GetAcquisition(ctx contractapi.TransactionContextInterface, account string, acquisitionID string) (*model.Acquisition, error)
+	}
+
+	// ROBInterface provides the functions to manage the Rules of Behavior (ROB) that accounts must accept.
+	ROBInterface interface {
+		// PublishROB publishes a new version of the ROB identified by the hex encoded SHA-256 digest of the document.
+		// The new version becomes the version accounts must accept within the grace period. Published versions cannot
+		// be changed. Only the admin can publish an ROB.
+		PublishROB(ctx contractapi.TransactionContextInterface, version string, digest string, uri string, gracePeriodDays int) error
+
+		// GetROB returns the current version of the ROB.
+		GetROB(ctx contractapi.TransactionContextInterface) (*model.ROB, error)
+
+		// AcceptROB records the acceptance of the current version of the ROB by the account of the requesting user. The
+		// version and digest must match the current ROB. The acceptance is signed by the system owner as the submitter
+		// of the transaction and the user, transaction ID and timestamp are recorded in the account's private info.
+		AcceptROB(ctx contractapi.TransactionContextInterface, version string, digest string) error
+
+		// EnforceROB moves every authorized account that has not accepted the current version of the ROB to
+		// UNAUTHORIZED_ROB once the grace period has ended. The names of the updated accounts are returned. Only the
+		// admin can enforce the ROB.
+		EnforceROB(ctx contractapi.TransactionContextInterface) ([]string, error)
 	}
 
 	// SwIDInterface provides the functions to interact with SwID tags in fabric.
@@ -137,3 +498,13 @@ type (
 func (b *BlossomSmartContract) InitNGAC(ctx contractapi.TransactionContextInterface) error {
 	return pdp.InitCatalogNGAC(ctx)
 }
+
+// txTimestamp returns the timestamp of the transaction in UTC.
+func txTimestamp(ctx contractapi.TransactionContextInterface) (time.Time, error) {
+	ts, err := ctx.GetStub().GetTxTimestamp()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error getting transaction timestamp: %w", err)
+	}
+
+	return time.Unix(ts.Seconds, int64(ts.Nanos)).UTC(), nil
+}
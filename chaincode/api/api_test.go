@@ -9,6 +9,7 @@ import (
 	"github.com/usnistgov/blossom/chaincode/mocks"
 	"github.com/usnistgov/blossom/chaincode/model"
 	"testing"
+	"time"
 )
 
 //go:generate counterfeiter -o ../mocks/chaincodestub.go -fake-name ChaincodeStub . chaincodeStub
@@ -37,10 +38,10 @@ func TestInitNGAC(t *testing.T) {
 
 		require.NoError(t, mock.SetClientIdentity(mocks.Super))
 		err = mock.SetTransient("asset", onboardAssetTransientInput{Licenses: []model.License{
-			{"1", "exp1"}, {"2", "exp2"},
+			{LicenseID: "1", Expiration: testLicenseExpiration}, {LicenseID: "2", Expiration: testLicenseExpiration},
 		}})
 		require.NoError(t, err)
-		err = bcc.OnboardAsset(mock, "123", "asset1", "onboard-date", "expiration-date")
+		err = bcc.OnboardAsset(mock, "123", "asset1", testOnboardingDate.Format(time.RFC3339), testAssetExpiration.Format(time.RFC3339))
 		require.Error(t, err)
 	})
 
@@ -9,7 +9,9 @@ import (
 	events "github.com/usnistgov/blossom/chaincode/ngac/epp"
 	"github.com/usnistgov/blossom/chaincode/ngac/pdp"
 	decider "github.com/usnistgov/blossom/chaincode/ngac/pdp"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/usnistgov/blossom/chaincode/model"
 )
@@ -18,14 +20,75 @@ type (
 	CheckoutRequest struct {
 		Asset  string `json:"asset,omitempty"`
 		Amount int    `json:"amount,omitempty"`
+		// Justification is the business justification for the request
+		Justification string `json:"justification,omitempty"`
+		// LeaseTermDays is the number of days the licenses are requested for
+		LeaseTermDays int `json:"lease_term_days,omitempty"`
+		// FISMASystem is the FISMA system the licenses will be used on
+		FISMASystem string `json:"fisma_system,omitempty"`
+		// NeededBy is the date the licenses are needed by
+		NeededBy time.Time `json:"needed_by,omitempty"`
+		// ID is the ID of the transaction that made the request
+		ID string `json:"id,omitempty"`
+		// Status is the current status of the request.  Requests made before requests had a status are pending.
+		Status CheckoutRequestStatus `json:"status,omitempty"`
+		// Approved is the number of licenses checked out for the request, which can be less than the amount requested
+		Approved int `json:"approved,omitempty"`
+		// Allocation is the allocation strategy that selected the licenses checked out for the request
+		Allocation model.AllocationStrategy `json:"allocation,omitempty"`
+		// History records every change to the status of the request, oldest first
+		History []model.Update `json:"history,omitempty"`
+	}
+
+	// LeaseRenewalRequest is a request made by an account to extend the leases of licenses it has checked out.
+	LeaseRenewalRequest struct {
+		Asset    string   `json:"asset,omitempty"`
+		Licenses []string `json:"licenses,omitempty"`
+		// TermDays is the number of days to extend each lease by
+		TermDays int `json:"term_days,omitempty"`
+		// RequestedAt is the timestamp of the transaction that made the request
+		RequestedAt time.Time `json:"requested_at"`
 	}
 
+	// CheckoutRequestStatus is the status of a checkout request
+	CheckoutRequestStatus string
+
 	CheckinRequest struct {
 		Asset    string   `json:"asset,omitempty"`
 		Licenses []string `json:"licenses,omitempty"`
 	}
 )
 
+const (
+	CheckoutPending           CheckoutRequestStatus = "PENDING"
+	CheckoutApproved          CheckoutRequestStatus = "APPROVED"
+	CheckoutPartiallyApproved CheckoutRequestStatus = "PARTIALLY_APPROVED"
+	CheckoutDenied            CheckoutRequestStatus = "DENIED"
+	CheckoutCancelled         CheckoutRequestStatus = "CANCELLED"
+	// CheckoutWaitlisted is the status of a request that was approved when there were not enough licenses available
+	// and is waiting on the asset's waitlist
+	CheckoutWaitlisted CheckoutRequestStatus = "WAITLISTED"
+)
+
+// IsPending returns true if the request has not been approved, denied, or cancelled.
+func (r *CheckoutRequest) IsPending() bool {
+	return r.Status == "" || r.Status == CheckoutPending
+}
+
+// IsOpen returns true if the request is pending or waiting on the asset's waitlist.
+func (r *CheckoutRequest) IsOpen() bool {
+	return r.IsPending() || r.Status == CheckoutWaitlisted
+}
+
+// requestedAt returns the timestamp of the transaction that made the request.
+func (r *CheckoutRequest) requestedAt() time.Time {
+	if len(r.History) == 0 {
+		return time.Time{}
+	}
+
+	return r.History[0].Timestamp
+}
+
 func NewLicenseContract() AssetInterface {
 	return &BlossomSmartContract{}
 }
@@ -40,19 +103,34 @@ func (b *BlossomSmartContract) assetExists(ctx contractapi.TransactionContextInt
 }
 
 func (b *BlossomSmartContract) OnboardAsset(ctx contractapi.TransactionContextInterface, id string, name string, onboardDate string, expiration string) error {
+	assetInput, err := getOnboardAssetTransientInput(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting transient input: %w", err)
+	}
+
+	return b.onboardAsset(ctx, id, name, onboardDate, expiration, assetInput)
+}
+
+// onboardAsset adds an asset with the license pool to the catalog.
+func (b *BlossomSmartContract) onboardAsset(ctx contractapi.TransactionContextInterface, id, name, onboardDate, expiration string, assetInput onboardAssetTransientInput) error {
 	if ok, err := b.assetExists(ctx, id); err != nil {
 		return fmt.Errorf("error checking if asset already exists: %w", err)
 	} else if ok {
 		return fmt.Errorf("an asset with the ID %q already exists", id)
 	}
 
-	assetInput, err := getOnboardAssetTransientInput(ctx)
+	if len(assetInput.Licenses) == 0 {
+		return fmt.Errorf("licenses cannot be nil")
+	}
+
+	onboardingDate, err := model.ParseDate(onboardDate)
 	if err != nil {
-		return fmt.Errorf("error getting transient input: %w", err)
+		return fmt.Errorf("invalid onboarding date: %w", err)
 	}
 
-	if len(assetInput.Licenses) == 0 {
-		return fmt.Errorf("licenses cannot be nil")
+	expirationDate, err := model.ParseDate(expiration)
+	if err != nil {
+		return fmt.Errorf("invalid expiration: %w", err)
 	}
 
 	// ngac check
@@ -62,11 +140,29 @@ func (b *BlossomSmartContract) OnboardAsset(ctx contractapi.TransactionContextIn
 
 	// public info - id, name, available (=total), expiration
 	assetPub := &model.AssetPublic{
-		ID:             id,
-		Name:           name,
-		Available:      len(assetInput.Licenses),
-		OnboardingDate: onboardDate,
-		Expiration:     expiration,
+		ID:                 id,
+		Name:               name,
+		Available:          len(assetInput.Licenses),
+		OnboardingDate:     onboardingDate,
+		Expiration:         expirationDate,
+		Cost:               assetInput.Cost,
+		AllocationStrategy: assetInput.AllocationStrategy,
+	}
+
+	if err = assetPub.ValidateDates(); err != nil {
+		return err
+	}
+
+	licenses := make([]string, 0)
+	licenseMap := make(map[string]time.Time)
+	for _, license := range assetInput.Licenses {
+		exp, err := licenseExpiration(assetPub, license)
+		if err != nil {
+			return err
+		}
+
+		licenses = append(licenses, license.LicenseID)
+		licenseMap[license.LicenseID] = exp
 	}
 
 	bytes, err := json.Marshal(assetPub)
@@ -79,18 +175,16 @@ func (b *BlossomSmartContract) OnboardAsset(ctx contractapi.TransactionContextIn
 		return fmt.Errorf("error adding asset to catalog private data collection: %w", err)
 	}
 
-	licenses := make([]string, 0)
-	licenseMap := make(map[string]string)
-	for _, license := range assetInput.Licenses {
-		licenses = append(licenses, license.LicenseID)
-		licenseMap[license.LicenseID] = license.Expiration
-	}
-
 	assetPvt := model.AssetPrivate{
 		TotalAmount:       len(assetInput.Licenses),
 		Licenses:          licenseMap,
 		AvailableLicenses: licenses,
-		CheckedOut:        make(map[string]map[string]string),
+		CheckedOut:        make(map[string]map[string]model.LicenseLease),
+	}
+
+	for _, license := range assetInput.Licenses {
+		assetPvt.SetBatch(license)
+		assetPvt.SetKey(license)
 	}
 
 	if bytes, err = json.Marshal(assetPvt); err != nil {
@@ -98,212 +192,1386 @@ func (b *BlossomSmartContract) OnboardAsset(ctx contractapi.TransactionContextIn
 	}
 
 	// add license to licenses private data
-	if err = ctx.GetStub().PutPrivateData(collections.Licenses(), model.AssetKey(id), bytes); err != nil {
+	if err = putPrivateData(ctx, collections.Licenses(), model.AssetKey(id), "OnboardAsset", bytes); err != nil {
 		return fmt.Errorf("error adding asset to ledger: %w", err)
 	}
 
-	// ngac event
-	return events.ProcessOnboardAsset(ctx, collections.Catalog(), id)
+	// ngac event
+	return events.ProcessOnboardAsset(ctx, collections.Catalog(), id)
+}
+
+func (b *BlossomSmartContract) OffboardAsset(ctx contractapi.TransactionContextInterface, assetID string) error {
+	if ok, err := b.assetExists(ctx, assetID); err != nil {
+		return fmt.Errorf("error checking if asset exists: %w", err)
+	} else if !ok {
+		return nil
+	}
+
+	var (
+		asset *model.Asset
+		err   error
+	)
+
+	// ngac check
+	if err := pdp.CanOffboardAsset(ctx); err != nil {
+		return fmt.Errorf("ngac check failed: %w", err)
+	}
+
+	if asset, err = b.GetAsset(ctx, assetID); err != nil {
+		return fmt.Errorf("error getting asset info: %w", err)
+	}
+
+	// check that all licenses have been returned
+	if asset.Available != len(asset.Licenses) {
+		return fmt.Errorf("asset %s still has licenses checked out: %w", assetID, err)
+	}
+
+	// check that no requests are waiting for licenses. Entries of requests cancelled by their account are left on the
+	// waitlist since the account cannot write to the catalog, so only requests that are still waitlisted count
+	waitlist, err := getWaitlist(ctx, assetID)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range waitlist.Entries {
+		req, err := getCheckoutRequest(ctx, entry.Account, entry.RequestKey)
+		if err != nil {
+			return err
+		} else if req != nil && req.Status == CheckoutWaitlisted {
+			return fmt.Errorf("asset %s still has requests on its waitlist", assetID)
+		}
+	}
+
+	// the asset may still carry charges recorded before they were stored under their own keys
+	_, assetPvt, err := getAsset(ctx, assetID)
+	if err != nil {
+		return fmt.Errorf("error getting asset %s: %w", assetID, err)
+	}
+
+	return removeAsset(ctx, assetID, assetPvt, "OffboardAsset")
+}
+
+// ForceOffboardAsset revokes every license checked out of the asset, cancels the requests, transfers and offers for the
+// asset, flags the SwIDs associated with it, and then offboards it.  Each account's private info is written once.
+func (b *BlossomSmartContract) ForceOffboardAsset(ctx contractapi.TransactionContextInterface, assetID, reason string) error {
+	if reason == "" {
+		return fmt.Errorf("a reason is required to force the offboarding of an asset")
+	}
+
+	if ok, err := b.assetExists(ctx, assetID); err != nil {
+		return fmt.Errorf("error checking if asset exists: %w", err)
+	} else if !ok {
+		return fmt.Errorf("an asset with the ID %q does not exist", assetID)
+	}
+
+	// ngac check
+	if err := pdp.CanOffboardAsset(ctx); err != nil {
+		return fmt.Errorf("ngac check failed: %w", err)
+	}
+
+	assetPub, assetPvt, err := getAsset(ctx, assetID)
+	if err != nil {
+		return fmt.Errorf("error getting asset %s: %w", assetID, err)
+	}
+
+	now, err := txTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+
+	accounts, err := b.GetAccounts(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting accounts: %w", err)
+	}
+
+	for _, acctPub := range accounts {
+		// the private data of decommissioned accounts has already been removed
+		if acctPub.Status == model.Decommissioned {
+			continue
+		}
+
+		if err = forceOffboardAccount(ctx, assetPub, assetPvt, acctPub.Name, reason, now); err != nil {
+			return fmt.Errorf("error removing asset %s from account %s: %w", assetID, acctPub.Name, err)
+		}
+	}
+
+	// open transfers and offers of the asset are cancelled
+	transfers, err := assetTransfers(ctx, assetID)
+	if err != nil {
+		return err
+	}
+
+	for _, transfer := range transfers {
+		if err = putTransfer(ctx, transfer, model.TransferCancelled, reason); err != nil {
+			return err
+		}
+	}
+
+	offers, err := getOffers(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting offers: %w", err)
+	}
+
+	for _, offer := range offers {
+		if offer.Asset != assetID || !offer.IsOpen() {
+			continue
+		}
+
+		if err = putOffer(ctx, offer, model.OfferWithdrawn, "", reason); err != nil {
+			return err
+		}
+	}
+
+	return removeAsset(ctx, assetID, assetPvt, "ForceOffboardAsset")
+}
+
+// forceOffboardAccount revokes the licenses of the asset checked out by the account, cancels the account's open
+// checkout requests for the asset, deletes its pending checkin and lease renewal requests for the asset, and flags the
+// account's SwIDs associated with the asset.
+func forceOffboardAccount(ctx contractapi.TransactionContextInterface, assetPub *model.AssetPublic, assetPvt *model.AssetPrivate,
+	account, reason string, now time.Time) error {
+	collection := collections.Account(account)
+
+	if checkedOut, ok := assetPvt.CheckedOut[account]; ok {
+		acctPvt, err := getAccountPrivate(ctx, account)
+		if err != nil {
+			return err
+		}
+
+		// sort the licenses so every peer endorses the same write set
+		licenses := make([]string, 0)
+		for license := range checkedOut {
+			licenses = append(licenses, license)
+		}
+		sort.Strings(licenses)
+
+		revokeLicenses(assetPub, assetPvt, account, acctPvt, licenses, now)
+
+		if err = putAccountPrivate(ctx, account, "ForceOffboardAsset", acctPvt); err != nil {
+			return err
+		}
+	}
+
+	reqs, err := getCheckoutRequests(ctx, account)
+	if err != nil {
+		return fmt.Errorf("error getting checkout requests: %w", err)
+	}
+
+	keys := make([]string, 0)
+	for key, req := range reqs {
+		if req.Asset == assetPub.ID && req.IsOpen() {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if err = putCheckoutRequest(ctx, account, key, reqs[key], CheckoutCancelled, 0, reason); err != nil {
+			return fmt.Errorf("error cancelling request %s: %w", key, err)
+		}
+	}
+
+	for _, key := range []string{checkinRequestKey(account, assetPub.ID), leaseRenewalRequestKey(account, assetPub.ID)} {
+		if bytes, err := ctx.GetStub().GetPrivateData(collection, key); err != nil {
+			return fmt.Errorf("error getting request %s: %w", key, err)
+		} else if bytes == nil {
+			continue
+		}
+
+		if err = ctx.GetStub().DelPrivateData(collection, key); err != nil {
+			return fmt.Errorf("error deleting request %s: %w", key, err)
+		}
+	}
+
+	swids, err := getSwIDsAssociatedWithAsset(ctx, account, assetPub.ID)
+	if err != nil {
+		return fmt.Errorf("error getting swids: %w", err)
+	}
+
+	for _, swid := range swids {
+		swid.Flagged = true
+		swid.FlagReason = reason
+
+		bytes, err := json.Marshal(swid)
+		if err != nil {
+			return fmt.Errorf("error marshaling swid %s: %w", swid.PrimaryTag, err)
+		}
+
+		if err = ctx.GetStub().PutPrivateData(collection, model.SwIDKey(swid.PrimaryTag), bytes); err != nil {
+			return fmt.Errorf("error flagging swid %s: %w", swid.PrimaryTag, err)
+		}
+	}
+
+	return nil
+}
+
+// removeAsset writes the charges held in the asset private info under their own keys, deletes the asset and its
+// waitlist and runs the offboard_asset obligation.
+func removeAsset(ctx contractapi.TransactionContextInterface, assetID string, assetPvt *model.AssetPrivate, operation string) error {
+	if err := putCharges(ctx, assetPvt); err != nil {
+		return err
+	}
+
+	// remove asset from catalog
+	if err := ctx.GetStub().DelPrivateData(collections.Catalog(), model.AssetKey(assetID)); err != nil {
+		return fmt.Errorf("error offboarding asset from catalog pdc: %w", err)
+	}
+
+	// remove license licenses pdc
+	if err := delPrivateData(ctx, collections.Licenses(), model.AssetKey(assetID), operation); err != nil {
+		return fmt.Errorf("error offboarding asset from licenses pdc: %w", err)
+	}
+
+	// remove the waitlist from the catalog
+	if err := ctx.GetStub().DelPrivateData(collections.Catalog(), model.WaitlistKey(assetID)); err != nil {
+		return fmt.Errorf("error removing waitlist from catalog pdc: %w", err)
+	}
+
+	// ngac event
+	return events.ProcessOffboardAsset(ctx, collections.Catalog(), assetID)
+}
+
+func (b *BlossomSmartContract) AddLicenses(ctx contractapi.TransactionContextInterface, assetID string) error {
+	transientInput, err := getAddLicensesTransientInput(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting transient input: %w", err)
+	}
+
+	assetPub, assetPvt, err := b.getAssetToUpdate(ctx, assetID)
+	if err != nil {
+		return err
+	}
+
+	for _, license := range transientInput.Licenses {
+		if license.LicenseID == "" {
+			return fmt.Errorf("license ID cannot be empty")
+		} else if _, ok := assetPvt.Licenses[license.LicenseID]; ok {
+			return fmt.Errorf("license %s already exists for asset %s", license.LicenseID, assetID)
+		}
+
+		exp, err := licenseExpiration(assetPub, license)
+		if err != nil {
+			return err
+		}
+
+		assetPvt.Licenses[license.LicenseID] = exp
+		assetPvt.AvailableLicenses = append(assetPvt.AvailableLicenses, license.LicenseID)
+		assetPvt.SetBatch(license)
+		assetPvt.SetKey(license)
+	}
+
+	assetPvt.TotalAmount += len(transientInput.Licenses)
+	assetPub.Available += len(transientInput.Licenses)
+
+	// offer the new licenses to the requests waiting for them
+	if err = offerToWaitlist(ctx, "AddLicenses", assetPub, assetPvt, make(map[string]*model.AccountPrivate)); err != nil {
+		return err
+	}
+
+	return putAsset(ctx, "AddLicenses", assetPub, assetPvt)
+}
+
+func (b *BlossomSmartContract) RetireLicenses(ctx contractapi.TransactionContextInterface, assetID string) error {
+	transientInput, err := getRetireLicensesTransientInput(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting transient input: %w", err)
+	}
+
+	assetPub, assetPvt, err := b.getAssetToUpdate(ctx, assetID)
+	if err != nil {
+		return err
+	}
+
+	available := make(map[string]bool)
+	for _, license := range assetPvt.AvailableLicenses {
+		available[license] = true
+	}
+
+	for _, license := range transientInput.Licenses {
+		if _, ok := assetPvt.Licenses[license]; !ok {
+			return fmt.Errorf("license %s does not exist for asset %s", license, assetID)
+		} else if !available[license] {
+			// the license is checked out or was listed twice
+			return fmt.Errorf("license %s of asset %s is not available to retire", license, assetID)
+		}
+
+		delete(assetPvt.Licenses, license)
+		delete(assetPvt.Batches, license)
+		delete(assetPvt.Keys, license)
+		delete(available, license)
+	}
+
+	// keep the order of the remaining available licenses
+	availableLicenses := make([]string, 0)
+	for _, license := range assetPvt.AvailableLicenses {
+		if available[license] {
+			availableLicenses = append(availableLicenses, license)
+		}
+	}
+
+	assetPvt.AvailableLicenses = availableLicenses
+	assetPvt.TotalAmount -= len(transientInput.Licenses)
+	assetPub.Available -= len(transientInput.Licenses)
+
+	return putAsset(ctx, "RetireLicenses", assetPub, assetPvt)
+}
+
+func (b *BlossomSmartContract) UpdateAssetExpiration(ctx contractapi.TransactionContextInterface, assetID, expiration string) error {
+	expirationDate, err := model.ParseDate(expiration)
+	if err != nil {
+		return fmt.Errorf("invalid expiration: %w", err)
+	}
+
+	assetPub, assetPvt, err := b.getAssetToUpdate(ctx, assetID)
+	if err != nil {
+		return err
+	}
+
+	assetPub.Expiration = expirationDate
+	if err = assetPub.ValidateDates(); err != nil {
+		return err
+	}
+
+	// sort the licenses so the error returned is deterministic
+	licenses := make([]string, 0)
+	for license := range assetPvt.Licenses {
+		licenses = append(licenses, license)
+	}
+	sort.Strings(licenses)
+
+	for _, license := range licenses {
+		if err = assetPub.ValidateLicenseExpiration(license, assetPvt.Licenses[license]); err != nil {
+			return err
+		}
+	}
+
+	return putAsset(ctx, "UpdateAssetExpiration", assetPub, assetPvt)
+}
+
+func (b *BlossomSmartContract) UpdateAssetCost(ctx contractapi.TransactionContextInterface, assetID string) error {
+	transientInput, err := getUpdateAssetCostTransientInput(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting transient input: %w", err)
+	}
+
+	assetPub, assetPvt, err := b.getAssetToUpdate(ctx, assetID)
+	if err != nil {
+		return err
+	}
+
+	// leases that have already ended were charged at the previous cost
+	cost := transientInput.AssetCost
+	assetPub.Cost = &cost
+
+	return putAsset(ctx, "UpdateAssetCost", assetPub, assetPvt)
+}
+
+func (b *BlossomSmartContract) UpdateAllocationStrategy(ctx contractapi.TransactionContextInterface, assetID, strategy string) error {
+	allocation := model.AllocationStrategy(strategy)
+	if err := model.ValidateAllocationStrategy(allocation); err != nil {
+		return err
+	}
+
+	assetPub, assetPvt, err := b.getAssetToUpdate(ctx, assetID)
+	if err != nil {
+		return err
+	}
+
+	assetPub.AllocationStrategy = allocation
+
+	return putAsset(ctx, "UpdateAllocationStrategy", assetPub, assetPvt)
+}
+
+// licenseExpiration returns the expiration of a license being added to the asset.  A license without an expiration
+// expires with the asset.
+func licenseExpiration(assetPub *model.AssetPublic, license model.License) (time.Time, error) {
+	exp := license.Expiration.UTC()
+	if license.Expiration.IsZero() {
+		exp = assetPub.Expiration
+	}
+
+	if err := assetPub.ValidateLicenseExpiration(license.LicenseID, exp); err != nil {
+		return time.Time{}, err
+	}
+
+	return exp, nil
+}
+
+// getAssetToUpdate checks that the asset exists and the requesting user can update it, and returns the asset.
+func (b *BlossomSmartContract) getAssetToUpdate(ctx contractapi.TransactionContextInterface, assetID string) (*model.AssetPublic, *model.AssetPrivate, error) {
+	if ok, err := b.assetExists(ctx, assetID); err != nil {
+		return nil, nil, fmt.Errorf("error checking if asset exists: %w", err)
+	} else if !ok {
+		return nil, nil, fmt.Errorf("an asset with the ID %q does not exist", assetID)
+	}
+
+	// ngac check
+	if err := pdp.CanUpdateAsset(ctx); err != nil {
+		return nil, nil, fmt.Errorf("ngac check failed: %w", err)
+	}
+
+	assetPub, assetPvt, err := getAsset(ctx, assetID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting asset %s: %w", assetID, err)
+	}
+
+	return assetPub, assetPvt, nil
+}
+
+func (b *BlossomSmartContract) SweepExpiredLicenses(ctx contractapi.TransactionContextInterface) (*model.LicensesExpired, error) {
+	// ngac check
+	if err := decider.CanSweepExpiredLicenses(ctx); err != nil {
+		return nil, fmt.Errorf("error sweeping expired licenses: %w", err)
+	}
+
+	timestamp, err := txTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	assets, err := b.GetAssets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting assets: %w", err)
+	}
+
+	// sort the assets and accounts so every peer endorses the same write set
+	sort.Slice(assets, func(i, j int) bool {
+		return assets[i].ID < assets[j].ID
+	})
+
+	expired := &model.LicensesExpired{
+		Removed: make(map[string][]string),
+		Revoked: make([]model.LicensesReclaimed, 0),
+	}
+
+	// the private info of each affected account is loaded once and written once after all assets are swept
+	accounts := make(map[string]*model.AccountPrivate)
+	revoked := make(map[string]map[string][]string)
+
+	for _, asset := range assets {
+		assetPub, assetPvt, err := getAsset(ctx, asset.ID)
+		if err != nil {
+			return nil, fmt.Errorf("error getting asset %s: %w", asset.ID, err)
+		}
+
+		removed := removeExpiredLicenses(assetPub, assetPvt, timestamp)
+		if len(removed) > 0 {
+			expired.Removed[assetPub.ID] = removed
+		}
+
+		holders := make([]string, 0)
+		for account := range assetPvt.CheckedOut {
+			holders = append(holders, account)
+		}
+		sort.Strings(holders)
+
+		revokedFromAsset := false
+		for _, account := range holders {
+			licenses := make([]string, 0)
+			for license, lease := range assetPvt.CheckedOut[account] {
+				if model.IsLicenseExpired(lease.Expiration, timestamp) {
+					licenses = append(licenses, license)
+				}
+			}
+
+			if len(licenses) == 0 {
+				continue
+			}
+
+			sort.Strings(licenses)
+
+			acctPvt, ok := accounts[account]
+			if !ok {
+				if acctPvt, err = getAccountPrivate(ctx, account); err != nil {
+					return nil, err
+				}
+
+				accounts[account] = acctPvt
+				revoked[account] = make(map[string][]string)
+			}
+
+			revokeLicenses(assetPub, assetPvt, account, acctPvt, licenses, timestamp)
+			revoked[account][assetPub.ID] = licenses
+			revokedFromAsset = true
+		}
+
+		if len(removed) == 0 && !revokedFromAsset {
+			continue
+		}
+
+		if err = putAsset(ctx, "SweepExpiredLicenses", assetPub, assetPvt); err != nil {
+			return nil, fmt.Errorf("error updating asset %s: %w", assetPub.ID, err)
+		}
+	}
+
+	accountNames := make([]string, 0)
+	for account := range accounts {
+		accountNames = append(accountNames, account)
+	}
+	sort.Strings(accountNames)
+
+	for _, account := range accountNames {
+		if err = putAccountPrivate(ctx, account, "SweepExpiredLicenses", accounts[account]); err != nil {
+			return nil, err
+		}
+
+		expired.Revoked = append(expired.Revoked, model.LicensesReclaimed{
+			Account:           account,
+			Reason:            "licenses expired",
+			Licenses:          revoked[account],
+			CancelledRequests: make([]string, 0),
+		})
+	}
+
+	if len(expired.Removed) == 0 && len(expired.Revoked) == 0 {
+		return expired, nil
+	}
+
+	// fabric only keeps the last event set in a transaction, so every affected account is reported in one event
+	bytes, err := json.Marshal(expired)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling event: %w", err)
+	}
+
+	if err = ctx.GetStub().SetEvent(model.LicensesExpiredEvent, bytes); err != nil {
+		return nil, fmt.Errorf("error setting event: %w", err)
+	}
+
+	return expired, nil
+}
+
+// removeExpiredLicenses removes the available licenses that have expired as of the given time from the asset and
+// returns them in the order they were available.
+func removeExpiredLicenses(assetPub *model.AssetPublic, assetPvt *model.AssetPrivate, now time.Time) []string {
+	removed := make([]string, 0)
+	available := make([]string, 0)
+	for _, license := range assetPvt.AvailableLicenses {
+		if model.IsLicenseExpired(assetPvt.Licenses[license], now) {
+			removed = append(removed, license)
+			delete(assetPvt.Licenses, license)
+			delete(assetPvt.Batches, license)
+			delete(assetPvt.Keys, license)
+		} else {
+			available = append(available, license)
+		}
+	}
+
+	assetPvt.AvailableLicenses = available
+	assetPvt.TotalAmount -= len(removed)
+	assetPub.Available -= len(removed)
+
+	return removed
+}
+
+// revokeLicenses removes licenses checked out by the account from both the account and the asset and charges the
+// account for their leases until the given time.  The licenses are not returned to the available licenses.
+func revokeLicenses(assetPub *model.AssetPublic, assetPvt *model.AssetPrivate, account string, acctPvt *model.AccountPrivate,
+	licenses []string, now time.Time) {
+	checkedOut := assetPvt.CheckedOut[account]
+	held := acctPvt.Assets[assetPub.ID]
+	for _, license := range licenses {
+		assetPvt.Charge(assetPub, account, license, checkedOut[license], now)
+		delete(checkedOut, license)
+		delete(held, license)
+		delete(assetPvt.Licenses, license)
+		delete(assetPvt.Batches, license)
+		delete(assetPvt.Keys, license)
+	}
+
+	if len(checkedOut) == 0 {
+		delete(assetPvt.CheckedOut, account)
+	}
+
+	if len(held) == 0 {
+		delete(acctPvt.Assets, assetPub.ID)
+	}
+
+	assetPvt.TotalAmount -= len(licenses)
+}
+
+func (b *BlossomSmartContract) GetAssets(ctx contractapi.TransactionContextInterface) ([]*model.AssetPublic, error) {
+	// ngac check
+	if err := pdp.CanViewAssets(ctx); err != nil {
+		return nil, fmt.Errorf("ngac check failed: %w", err)
+	}
+
+	now, err := txTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	assets, err := getCatalogAssets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, asset := range assets {
+		asset.Status = asset.StatusAt(now)
+	}
+
+	return assets, nil
+}
+
+func (b *BlossomSmartContract) GetExpiringAssets(ctx contractapi.TransactionContextInterface, days int) ([]*model.AssetPublic, error) {
+	if days < 0 {
+		return nil, fmt.Errorf("days cannot be negative")
+	}
+
+	// ngac check
+	if err := pdp.CanViewExpiringAssets(ctx); err != nil {
+		return nil, fmt.Errorf("ngac check failed: %w", err)
+	}
+
+	now, err := txTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	assets, err := getCatalogAssets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	until := now.AddDate(0, 0, days)
+	expiring := make([]*model.AssetPublic, 0)
+	for _, asset := range assets {
+		asset.Status = asset.StatusAt(now)
+		if asset.Status != model.AssetExpired && !asset.Expiration.After(until) {
+			expiring = append(expiring, asset)
+		}
+	}
+
+	// the assets expiring soonest are first
+	sort.SliceStable(expiring, func(i, j int) bool {
+		return expiring[i].Expiration.Before(expiring[j].Expiration)
+	})
+
+	return expiring, nil
+}
+
+// getCatalogAssets returns the public info of every asset in the catalog in key order.
+func getCatalogAssets(ctx contractapi.TransactionContextInterface) ([]*model.AssetPublic, error) {
+	resultsIterator, err := ctx.GetStub().GetPrivateDataByRange(collections.Catalog(), "", "")
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	assets := make([]*model.AssetPublic, 0)
+	for resultsIterator.HasNext() {
+		var queryResponse *queryresult.KV
+		if queryResponse, err = resultsIterator.Next(); err != nil {
+			return nil, err
+		}
+
+		// assets on the ledger begin with the asset prefix -- ignore other results
+		if !strings.HasPrefix(queryResponse.Key, model.AssetPrefix) {
+			continue
+		}
+
+		asset := model.NewAssetPublic()
+		if err = json.Unmarshal(queryResponse.Value, asset); err != nil {
+			return nil, err
+		}
+
+		assets = append(assets, asset)
+	}
+
+	return assets, nil
+}
+
+func (b *BlossomSmartContract) GetAsset(ctx contractapi.TransactionContextInterface, id string) (*model.Asset, error) {
+	if ok, err := b.assetExists(ctx, id); err != nil {
+		return nil, fmt.Errorf("error checking if asset exists: %w", err)
+	} else if !ok {
+		return nil, fmt.Errorf("an asset with the ID %q does not exist", id)
+	}
+
+	var (
+		assetPub = model.NewAssetPublic()
+		assetPvt = model.NewAssetPrivate()
+		bytes    []byte
+		err      error
+	)
+
+	if bytes, err = ctx.GetStub().GetPrivateData(collections.Catalog(), model.AssetKey(id)); err != nil {
+		return nil, fmt.Errorf("error getting asset from private data: %w", err)
+	}
+
+	// ngac check
+	if err = pdp.CanViewAssetPublic(ctx); err != nil {
+		return nil, fmt.Errorf("ngac check on asset public failed: %w", err)
+	}
+
+	if err = json.Unmarshal(bytes, assetPub); err != nil {
+		return nil, fmt.Errorf("error unmarshaling asset public info: %w", err)
+	}
+
+	if bytes, err = ctx.GetStub().GetPrivateData(collections.Licenses(), model.AssetKey(id)); err != nil {
+		mspid, _ := ctx.GetClientIdentity().GetMSPID()
+		fmt.Printf("error occurred reading pvtdata for user in org %s: %v\n", mspid, err)
+	} else {
+		if err = json.Unmarshal(bytes, assetPvt); err != nil {
+			return nil, fmt.Errorf("error deserializing account private info: %w", err)
+		}
+	}
+
+	now, err := txTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Asset{
+		ID:                 assetPub.ID,
+		Name:               assetPub.Name,
+		Available:          assetPub.Available,
+		OnboardingDate:     assetPub.OnboardingDate,
+		Expiration:         assetPub.Expiration,
+		Cost:               assetPub.Cost,
+		AllocationStrategy: assetPub.AllocationStrategy,
+		Status:             assetPub.StatusAt(now),
+		TotalAmount:        assetPvt.TotalAmount,
+		Licenses:           assetPvt.Licenses,
+		AvailableLicenses:  assetPvt.AvailableLicenses,
+		CheckedOut:         assetPvt.CheckedOut,
+	}, nil
+}
+
+func (b *BlossomSmartContract) GetLicenseRollup(ctx contractapi.TransactionContextInterface) ([]*model.LicenseRollup, error) {
+	// ngac check
+	if err := pdp.CanViewAssetPrivate(ctx); err != nil {
+		return nil, fmt.Errorf("ngac check on asset private failed: %w", err)
+	}
+
+	accounts, err := b.GetAccounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting accounts: %w", err)
+	}
+
+	accountsByName := make(map[string]*model.AccountPublic)
+	for _, acctPub := range accounts {
+		accountsByName[acctPub.Name] = acctPub
+	}
+
+	iter, err := ctx.GetStub().GetPrivateDataByRange(collections.Licenses(), "", "")
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	rollups := newRollupTree()
+	for iter.HasNext() {
+		var next *queryresult.KV
+		if next, err = iter.Next(); err != nil {
+			return nil, fmt.Errorf("error getting next KV: %w", err)
+		}
+
+		if !strings.HasPrefix(next.Key, model.AssetPrefix) {
+			continue
+		}
+
+		assetID := strings.TrimPrefix(next.Key, model.AssetPrefix)
+
+		assetPvt := model.NewAssetPrivate()
+		if err = json.Unmarshal(next.Value, assetPvt); err != nil {
+			return nil, fmt.Errorf("error unmarshaling asset %s: %w", assetID, err)
+		}
+
+		for account, licenses := range assetPvt.CheckedOut {
+			acctPub, ok := accountsByName[account]
+			if !ok {
+				acctPub = &model.AccountPublic{Name: account}
+			}
+
+			rollups.add(acctPub, assetID, len(licenses))
+		}
+	}
+
+	return rollups.departmentRollups(), nil
+}
+
+// rollupTree builds the license roll-ups of each level of the agency hierarchy.
+type rollupTree struct {
+	departments map[string]*model.LicenseRollup
+	subAgencies map[string]map[string]*model.LicenseRollup
+	accounts    map[string]*model.LicenseRollup
+}
+
+func newRollupTree() *rollupTree {
+	return &rollupTree{
+		departments: make(map[string]*model.LicenseRollup),
+		subAgencies: make(map[string]map[string]*model.LicenseRollup),
+		accounts:    make(map[string]*model.LicenseRollup),
+	}
+}
+
+// add adds the licenses of an asset checked out by the account to the account's roll-up and every roll-up above it.
+func (t *rollupTree) add(acctPub *model.AccountPublic, assetID string, amount int) {
+	department := acctPub.Department
+	if department == "" {
+		department = model.UnassignedDepartment
+	}
+
+	dept, ok := t.departments[department]
+	if !ok {
+		dept = model.NewLicenseRollup(department, model.DepartmentLevel)
+		t.departments[department] = dept
+		t.subAgencies[department] = make(map[string]*model.LicenseRollup)
+	}
+
+	// accounts that do not belong to a sub-agency roll up directly to the department
+	parent := dept
+	if acctPub.SubAgency != "" {
+		sub, ok := t.subAgencies[department][acctPub.SubAgency]
+		if !ok {
+			sub = model.NewLicenseRollup(acctPub.SubAgency, model.SubAgencyLevel)
+			t.subAgencies[department][acctPub.SubAgency] = sub
+			dept.Children = append(dept.Children, sub)
+		}
+
+		sub.Add(assetID, amount)
+		parent = sub
+	}
+
+	acct, ok := t.accounts[acctPub.Name]
+	if !ok {
+		acct = model.NewLicenseRollup(acctPub.Name, model.AccountLevel)
+		t.accounts[acctPub.Name] = acct
+		parent.Children = append(parent.Children, acct)
+	}
+
+	acct.Add(assetID, amount)
+	dept.Add(assetID, amount)
+}
+
+// departmentRollups returns the department roll-ups with each level sorted by name.
+func (t *rollupTree) departmentRollups() []*model.LicenseRollup {
+	departments := make([]*model.LicenseRollup, 0)
+	for _, dept := range t.departments {
+		departments = append(departments, dept)
+	}
+
+	sortRollups(departments)
+
+	return departments
+}
+
+func sortRollups(rollups []*model.LicenseRollup) {
+	sort.Slice(rollups, func(i, j int) bool {
+		return rollups[i].Name < rollups[j].Name
+	})
+
+	for _, rollup := range rollups {
+		sortRollups(rollup.Children)
+	}
+}
+
+func (b *BlossomSmartContract) GetAssetHistory(ctx contractapi.TransactionContextInterface, id string) ([]model.PrivateHistoryEntry, error) {
+	// ngac check
+	if err := pdp.CanViewAssetPrivate(ctx); err != nil {
+		return nil, fmt.Errorf("ngac check on asset private failed: %w", err)
+	}
+
+	history, err := getPrivateHistory(ctx, collections.Licenses(), model.AssetKey(id))
+	if err != nil {
+		return nil, fmt.Errorf("error getting history of asset %q: %w", id, err)
+	}
+
+	return history, nil
+}
+
+func (b *BlossomSmartContract) RequestCheckout(ctx contractapi.TransactionContextInterface) error {
+	transientInput, err := getRequestCheckoutTransientInput(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting transient input: %w", err)
+	}
+
+	var (
+		account string
+		bytes   []byte
+	)
+
+	// check requested asset exists
+	if bytes, err = ctx.GetStub().GetPrivateData(collections.Catalog(), model.AssetKey(transientInput.AssetID)); err != nil {
+		return err
+	} else if bytes == nil {
+		return fmt.Errorf("asset with id %s does not exist", transientInput.AssetID)
+	}
+
+	assetPub := model.NewAssetPublic()
+	if err = json.Unmarshal(bytes, assetPub); err != nil {
+		return fmt.Errorf("error unmarshaling asset %s: %w", transientInput.AssetID, err)
+	}
+
+	now, err := txTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+
+	// licenses cannot be requested before the asset is onboarded or after it expires
+	if err = assetPub.CheckActive(now); err != nil {
+		return err
+	}
+
+	if account, err = accountName(ctx); err != nil {
+		return fmt.Errorf("error getting MSPID from stub: %w", err)
+	}
+
+	// ngac check
+	if err = decider.CanRequestCheckout(ctx, account); err != nil {
+		return fmt.Errorf("ngac check failed: %w", err)
+	}
+
+	// check if request has already been made and not resolved
+	if key, _, err := openCheckoutRequest(ctx, account, transientInput.AssetID); err != nil {
+		return fmt.Errorf("error checking if request has been made but not resolved: %w", err)
+	} else if key != "" {
+		return fmt.Errorf("request for asset %s alreadys exists for account %s and has not been resolved yet", transientInput.AssetID, account)
+	}
+
+	txID := ctx.GetStub().GetTxID()
+	req := &CheckoutRequest{
+		Asset:         transientInput.AssetID,
+		Amount:        transientInput.Amount,
+		Justification: transientInput.Justification,
+		LeaseTermDays: transientInput.LeaseTermDays,
+		FISMASystem:   transientInput.FISMASystem,
+		NeededBy:      transientInput.NeededBy,
+		ID:            txID,
+		History:       make([]model.Update, 0),
+	}
+
+	return putCheckoutRequest(ctx, account, checkoutRequestKey(account, transientInput.AssetID, txID), req, CheckoutPending, 0, "")
+}
+
+// checkoutRequestKey returns the key of a checkout request.  Every request is stored under its own key so resolved
+// requests are kept with their history.
+func checkoutRequestKey(account, assetID, txID string) string {
+	return fmt.Sprintf("%s%s:%s", checkoutRequestPrefix(account), assetID, txID)
+}
+
+// checkoutRequestPrefix returns the prefix of the keys of the checkout requests made by the account.
+func checkoutRequestPrefix(account string) string {
+	return fmt.Sprintf("checkout=%s:", account)
+}
+
+// getCheckoutRequests returns the checkout requests made by the account mapped by key.
+func getCheckoutRequests(ctx contractapi.TransactionContextInterface, account string) (map[string]*CheckoutRequest, error) {
+	iter, err := ctx.GetStub().GetPrivateDataByRange(collections.Account(account), "", "")
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	prefix := checkoutRequestPrefix(account)
+	reqs := make(map[string]*CheckoutRequest)
+	for iter.HasNext() {
+		var next *queryresult.KV
+		if next, err = iter.Next(); err != nil {
+			return nil, fmt.Errorf("error getting next KV: %w", err)
+		}
+
+		if !strings.HasPrefix(next.Key, prefix) {
+			continue
+		}
+
+		req := &CheckoutRequest{}
+		if err = json.Unmarshal(next.Value, req); err != nil {
+			return nil, fmt.Errorf("error unmarshaling request: %w", err)
+		}
+
+		reqs[next.Key] = req
+	}
+
+	return reqs, nil
+}
+
+// openCheckoutRequest returns the key of the account's open request for the asset and the request.  The key is empty if
+// there is no open request.
+func openCheckoutRequest(ctx contractapi.TransactionContextInterface, account, assetID string) (string, *CheckoutRequest, error) {
+	reqs, err := getCheckoutRequests(ctx, account)
+	if err != nil {
+		return "", nil, err
+	}
+
+	for key, req := range reqs {
+		if req.Asset == assetID && req.IsOpen() {
+			return key, req, nil
+		}
+	}
+
+	return "", nil, nil
+}
+
+// putCheckoutRequest updates the status of the request, records the update in the request's history, and writes the
+// request to the account's private data collection.
+func putCheckoutRequest(ctx contractapi.TransactionContextInterface, account, key string, req *CheckoutRequest,
+	status CheckoutRequestStatus, approved int, reason string) error {
+	update, err := newUpdate(ctx, string(status), reason)
+	if err != nil {
+		return err
+	}
+	update.Approved = approved
+
+	req.Status = status
+	req.Approved = approved
+	return putWithHistory(ctx, collections.Account(account), key, req, &req.History, update)
+}
+
+func (b *BlossomSmartContract) GetCheckoutRequests(ctx contractapi.TransactionContextInterface, account string) ([]CheckoutRequest, error) {
+	reqs, err := getCheckoutRequests(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]CheckoutRequest, 0)
+	for _, req := range reqs {
+		result = append(result, *req)
+	}
+
+	// order the requests by when they were made, requests made before requests had a history are first
+	sort.Slice(result, func(i, j int) bool {
+		ti, tj := result[i].requestedAt(), result[j].requestedAt()
+		if ti.Equal(tj) {
+			return result[i].ID < result[j].ID
+		}
+
+		return ti.Before(tj)
+	})
+
+	return result, nil
+}
+
+func (b *BlossomSmartContract) ApproveCheckout(ctx contractapi.TransactionContextInterface) error {
+	transientInput, err := getApproveCheckoutTransientInput(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting transient input: %w", err)
+	}
+
+	// ngac check
+	if err = decider.CanApproveCheckout(ctx, transientInput.Account); err != nil {
+		return fmt.Errorf("ngac check failed: %w", err)
+	}
+
+	key, req, err := getOpenCheckoutRequest(ctx, transientInput.Account, transientInput.AssetID)
+	if err != nil {
+		return err
+	} else if !req.IsPending() {
+		return fmt.Errorf("request for asset %s by account %s is already on the waitlist", transientInput.AssetID, transientInput.Account)
+	}
+
+	// approve the full amount requested unless a smaller amount is given
+	amount := req.Amount
+	if transientInput.Amount > req.Amount {
+		return fmt.Errorf("approved amount %d cannot be greater than the requested amount %d", transientInput.Amount, req.Amount)
+	} else if transientInput.Amount > 0 {
+		amount = transientInput.Amount
+	}
+
+	// the number of licenses chosen by the approver is the amount approved
+	if chosen := len(transientInput.Licenses); chosen > 0 {
+		if chosen > req.Amount {
+			return fmt.Errorf("number of licenses chosen %d cannot be greater than the requested amount %d", chosen, req.Amount)
+		} else if transientInput.Amount > 0 && transientInput.Amount != chosen {
+			return fmt.Errorf("approved amount %d does not match the number of licenses chosen %d", transientInput.Amount, chosen)
+		}
+
+		amount = chosen
+	}
+
+	acctPub, acctPvt, assetPub, assetPvt, err := getAcctAndAsset(ctx, transientInput.Account, transientInput.AssetID)
+	if err != nil {
+		return fmt.Errorf("error getting account and asset to process checkout: %w", err)
+	}
+
+	timestamp, err := txTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = assetPub.CheckActive(timestamp); err != nil {
+		return err
+	}
+
+	waitlist, err := getWaitlist(ctx, transientInput.AssetID)
+	if err != nil {
+		return err
+	}
+
+	explicit := assetPub.Allocation() == model.AllocationExplicit
+	if len(transientInput.Licenses) > 0 && !explicit {
+		return fmt.Errorf("licenses can only be chosen for assets with the %s allocation strategy", model.AllocationExplicit)
+	}
+
+	// if the licenses are not available or other requests are already waiting for them, the request joins the waitlist
+	if len(waitlist.Entries) > 0 || unexpiredAvailable(assetPvt, timestamp) < amount {
+		if len(transientInput.Licenses) > 0 {
+			return fmt.Errorf("licenses cannot be chosen for a request that joins the waitlist of asset %s", transientInput.AssetID)
+		}
+
+		return waitlistCheckout(ctx, transientInput, key, req, amount, acctPvt, assetPub, assetPvt, waitlist, timestamp)
+	}
+
+	if explicit {
+		if len(transientInput.Licenses) == 0 {
+			return fmt.Errorf("licenses must be chosen for assets with the %s allocation strategy", model.AllocationExplicit)
+		}
+
+		req.Allocation = model.AllocationExplicit
+		err = assignLicenses(assetPub, assetPvt, acctPub.Name, acctPvt, transientInput.Licenses, timestamp, req.LeaseTermDays)
+	} else {
+		req.Allocation, err = checkout(assetPub, assetPvt, acctPub.Name, acctPvt, amount, timestamp, req.LeaseTermDays)
+	}
+	if err != nil {
+		return fmt.Errorf("error checking out %s for account %s: %w", transientInput.AssetID, transientInput.Account, err)
+	}
+
+	if err = putCheckoutRequest(ctx, transientInput.Account, key, req, approvalStatus(req, amount), amount, transientInput.Reason); err != nil {
+		return fmt.Errorf("error updating request: %w", err)
+	}
+
+	return putAcctAndAsset(ctx, "ApproveCheckout", acctPub, acctPvt, assetPub, assetPvt)
+}
+
+// approvalStatus returns the status of a request approved for the given amount.
+func approvalStatus(req *CheckoutRequest, amount int) CheckoutRequestStatus {
+	if amount < req.Amount {
+		return CheckoutPartiallyApproved
+	}
+
+	return CheckoutApproved
+}
+
+func (b *BlossomSmartContract) DenyCheckout(ctx contractapi.TransactionContextInterface) error {
+	transientInput, err := getDenyCheckoutTransientInput(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting transient input: %w", err)
+	}
+
+	// ngac check
+	if err = decider.CanDenyCheckout(ctx, transientInput.Account); err != nil {
+		return fmt.Errorf("ngac check failed: %w", err)
+	}
+
+	key, req, err := getOpenCheckoutRequest(ctx, transientInput.Account, transientInput.AssetID)
+	if err != nil {
+		return err
+	}
+
+	if req.Status == CheckoutWaitlisted {
+		if err = removeFromWaitlist(ctx, transientInput.AssetID, key); err != nil {
+			return err
+		}
+	}
+
+	return putCheckoutRequest(ctx, transientInput.Account, key, req, CheckoutDenied, 0, transientInput.Reason)
 }
 
-func (b *BlossomSmartContract) OffboardAsset(ctx contractapi.TransactionContextInterface, assetID string) error {
-	if ok, err := b.assetExists(ctx, assetID); err != nil {
-		return fmt.Errorf("error checking if asset exists: %w", err)
-	} else if !ok {
-		return nil
+func (b *BlossomSmartContract) CancelCheckout(ctx contractapi.TransactionContextInterface) error {
+	transientInput, err := getCancelCheckoutTransientInput(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting transient input: %w", err)
 	}
 
-	var (
-		asset *model.Asset
-		err   error
-	)
+	account, err := accountName(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting MSPID from stub: %w", err)
+	}
 
 	// ngac check
-	if err := pdp.CanOffboardAsset(ctx); err != nil {
+	if err = decider.CanCancelCheckout(ctx, account); err != nil {
 		return fmt.Errorf("ngac check failed: %w", err)
 	}
 
-	if asset, err = b.GetAsset(ctx, assetID); err != nil {
-		return fmt.Errorf("error getting asset info: %w", err)
-	}
-
-	// check that all licenses have been returned
-	if asset.Available != len(asset.Licenses) {
-		return fmt.Errorf("asset %s still has licenses checked out: %w", assetID, err)
+	key, req, err := getOpenCheckoutRequest(ctx, account, transientInput.AssetID)
+	if err != nil {
+		return err
 	}
 
-	// remove asset from catalog
-	if err = ctx.GetStub().DelPrivateData(collections.Catalog(), model.AssetKey(assetID)); err != nil {
-		return fmt.Errorf("error offboarding asset from catalog pdc: %w", err)
-	}
+	return putCheckoutRequest(ctx, account, key, req, CheckoutCancelled, 0, transientInput.Reason)
+}
 
-	// remove license licenses pdc
-	if err = ctx.GetStub().DelPrivateData(collections.Licenses(), model.AssetKey(assetID)); err != nil {
-		return fmt.Errorf("error offboarding asset from licenses pdc: %w", err)
+// getOpenCheckoutRequest returns the key of the account's open request for the asset and the request, or an error if
+// there is no open request.
+func getOpenCheckoutRequest(ctx contractapi.TransactionContextInterface, account, assetID string) (string, *CheckoutRequest, error) {
+	key, req, err := openCheckoutRequest(ctx, account, assetID)
+	if err != nil {
+		return "", nil, fmt.Errorf("error checking if request exists: %w", err)
+	} else if key == "" {
+		return "", nil, fmt.Errorf("an open request for asset %s does not exist for account %s", assetID, account)
 	}
 
-	// ngac event
-	return events.ProcessOffboardAsset(ctx, collections.Catalog(), assetID)
+	return key, req, nil
 }
 
-func (b *BlossomSmartContract) GetAssets(ctx contractapi.TransactionContextInterface) ([]*model.AssetPublic, error) {
-	// ngac check
-	if err := pdp.CanViewAssets(ctx); err != nil {
-		return nil, fmt.Errorf("ngac check failed: %w", err)
+// checkout leases the given amount of available licenses to the account for the lease term starting at the given time.
+// The licenses are selected using the allocation strategy of the asset, which is returned.  Licenses that have expired
+// as of the given time are skipped and left in the pool to be removed by SweepExpiredLicenses.  A lease without a term
+// ends when the license expires.
+func checkout(assetPub *model.AssetPublic, assetPvt *model.AssetPrivate, account string, acctPvt *model.AccountPrivate,
+	amount int, now time.Time, leaseTermDays int) (model.AllocationStrategy, error) {
+	// check that the amount requested is less than the amount available
+	if amount > assetPub.Available {
+		return "", fmt.Errorf("requested amount %v cannot be greater than the available amount %v",
+			amount, assetPub.Available)
 	}
 
-	resultsIterator, err := ctx.GetStub().GetPrivateDataByRange(collections.Catalog(), "", "")
-	if err != nil {
-		return nil, err
+	// licenses that are not chosen by the approver are selected in pool order
+	strategy := assetPub.Allocation()
+	if strategy == model.AllocationExplicit {
+		strategy = model.AllocationPoolOrder
 	}
-	defer resultsIterator.Close()
 
-	assets := make([]*model.AssetPublic, 0)
-	for resultsIterator.HasNext() {
-		var queryResponse *queryresult.KV
-		if queryResponse, err = resultsIterator.Next(); err != nil {
-			return nil, err
-		}
+	licenses := assetPvt.SelectLicenses(strategy, amount, now)
+	if len(licenses) < amount {
+		return "", fmt.Errorf("requested amount %v cannot be greater than the number of unexpired licenses %v",
+			amount, len(licenses))
+	}
 
-		// assets on the ledger begin with the asset prefix -- ignore other results
-		if !strings.HasPrefix(queryResponse.Key, model.AssetPrefix) {
-			continue
-		}
+	return strategy, assignLicenses(assetPub, assetPvt, account, acctPvt, licenses, now, leaseTermDays)
+}
 
-		asset := model.NewAssetPublic()
-		if err = json.Unmarshal(queryResponse.Value, asset); err != nil {
-			return nil, err
+// assignLicenses removes the given licenses from the available pool and leases them to the account for the lease term
+// starting at the given time.  Each license must be available and not expired.
+func assignLicenses(assetPub *model.AssetPublic, assetPvt *model.AssetPrivate, account string, acctPvt *model.AccountPrivate,
+	licenses []string, now time.Time, leaseTermDays int) error {
+	assigned := make(map[string]bool)
+	for _, license := range licenses {
+		if model.IsLicenseExpired(assetPvt.Licenses[license], now) {
+			return fmt.Errorf("license %s has expired", license)
 		}
 
-		assets = append(assets, asset)
+		assigned[license] = true
 	}
 
-	return assets, nil
-}
+	// update available licenses
+	remaining := make([]string, 0)
+	for _, license := range assetPvt.AvailableLicenses {
+		if assigned[license] {
+			delete(assigned, license)
+		} else {
+			remaining = append(remaining, license)
+		}
+	}
 
-func (b *BlossomSmartContract) GetAsset(ctx contractapi.TransactionContextInterface, id string) (*model.Asset, error) {
-	if ok, err := b.assetExists(ctx, id); err != nil {
-		return nil, fmt.Errorf("error checking if asset exists: %w", err)
-	} else if !ok {
-		return nil, fmt.Errorf("an asset with the ID %q does not exist", id)
+	for _, license := range licenses {
+		if _, ok := assigned[license]; ok {
+			return fmt.Errorf("license %s is not available", license)
+		}
 	}
 
-	var (
-		assetPub = model.NewAssetPublic()
-		assetPvt = model.NewAssetPrivate()
-		bytes    []byte
-		err      error
-	)
+	assetPvt.AvailableLicenses = remaining
 
-	if bytes, err = ctx.GetStub().GetPrivateData(collections.Catalog(), model.AssetKey(id)); err != nil {
-		return nil, fmt.Errorf("error getting asset from private data: %w", err)
+	// update available amount
+	assetPub.Available -= len(licenses)
+
+	// create the set of licenses that are checked out including expiration and lease end dates
+	retCheckedOutLicenses := make(map[string]model.LicenseLease)
+	for _, license := range licenses {
+		retCheckedOutLicenses[license] = model.NewLicenseLease(assetPvt.Licenses[license], now, leaseTermDays)
 	}
 
-	// ngac check
-	if err = pdp.CanViewAssetPublic(ctx); err != nil {
-		return nil, fmt.Errorf("ngac check on asset public failed: %w", err)
+	// update the account assets
+	// add to existing asset if they are checking out more of a software asset
+	allCheckedOutAssets, ok := acctPvt.Assets[assetPub.ID]
+	if !ok {
+		allCheckedOutAssets = make(map[string]model.LicenseLease)
 	}
 
-	if err = json.Unmarshal(bytes, assetPub); err != nil {
-		return nil, fmt.Errorf("error unmarshaling asset public info: %w", err)
+	for license, lease := range retCheckedOutLicenses {
+		allCheckedOutAssets[license] = lease
 	}
 
-	if bytes, err = ctx.GetStub().GetPrivateData(collections.Licenses(), model.AssetKey(id)); err != nil {
-		mspid, _ := ctx.GetClientIdentity().GetMSPID()
-		fmt.Printf("error occurred reading pvtdata for user in org %s: %v\n", mspid, err)
-	} else {
-		if err = json.Unmarshal(bytes, assetPvt); err != nil {
-			return nil, fmt.Errorf("error deserializing account private info: %w", err)
-		}
+	// update asset in the account
+	acctPvt.Assets[assetPub.ID] = allCheckedOutAssets
+
+	// update the asset's account tracker
+	accountCheckedOut := make(map[string]model.LicenseLease)
+	for license, lease := range allCheckedOutAssets {
+		accountCheckedOut[license] = lease.Unsealed()
 	}
+	assetPvt.CheckedOut[account] = accountCheckedOut
 
-	return &model.Asset{
-		ID:                assetPub.ID,
-		Name:              assetPub.Name,
-		Available:         assetPub.Available,
-		OnboardingDate:    assetPub.OnboardingDate,
-		Expiration:        assetPub.Expiration,
-		TotalAmount:       assetPvt.TotalAmount,
-		Licenses:          assetPvt.Licenses,
-		AvailableLicenses: assetPvt.AvailableLicenses,
-		CheckedOut:        assetPvt.CheckedOut,
-	}, nil
+	return nil
 }
 
-func (b *BlossomSmartContract) RequestCheckout(ctx contractapi.TransactionContextInterface) error {
-	transientInput, err := getRequestCheckoutTransientInput(ctx)
+func (b *BlossomSmartContract) RequestLeaseRenewal(ctx contractapi.TransactionContextInterface) error {
+	transientInput, err := getRequestLeaseRenewalTransientInput(ctx)
 	if err != nil {
 		return fmt.Errorf("error getting transient input: %w", err)
 	}
 
-	var (
-		account string
-		bytes   []byte
-	)
-
-	// check requested asset exists
-	if bytes, err = ctx.GetStub().GetPrivateData(collections.Catalog(), model.AssetKey(transientInput.AssetID)); err != nil {
-		return err
-	} else if bytes == nil {
-		return fmt.Errorf("asset with id %s does not exist", transientInput.AssetID)
-	}
-
-	if account, err = accountName(ctx); err != nil {
+	account, err := accountName(ctx)
+	if err != nil {
 		return fmt.Errorf("error getting MSPID from stub: %w", err)
 	}
 
-	collection := collections.Account(account)
-
 	// ngac check
-	if err = decider.CanRequestCheckout(ctx, account); err != nil {
+	if err = decider.CanRequestLeaseRenewal(ctx, account); err != nil {
 		return fmt.Errorf("ngac check failed: %w", err)
 	}
 
-	key := checkoutRequestKey(account, transientInput.AssetID)
+	collection := collections.Account(account)
+	key := leaseRenewalRequestKey(account, transientInput.AssetID)
 
-	// check if request has already been made and not approved
-	if bytes, err = ctx.GetStub().GetPrivateData(collection, key); err != nil {
-		return fmt.Errorf("error reading private data to check if request has been made but not approved: %w", err)
+	// check if request has already been made and not processed
+	if bytes, err := ctx.GetStub().GetPrivateData(collection, key); err != nil {
+		return err
 	} else if bytes != nil {
-		return fmt.Errorf("request for asset %s alreadys exists for account %s and has not been approved yet", transientInput.AssetID, account)
+		return fmt.Errorf("request to renew leases of %s has already been made for account %s and has not been processed yet", transientInput.AssetID, account)
+	}
+
+	acctPvt, err := getAccountPrivate(ctx, account)
+	if err != nil {
+		return err
 	}
 
-	req := &CheckoutRequest{transientInput.AssetID, transientInput.Amount}
+	timestamp, err := txTimestamp(ctx)
+	if err != nil {
+		return err
+	}
 
-	if bytes, err = json.Marshal(req); err != nil {
+	req := LeaseRenewalRequest{
+		Asset:       transientInput.AssetID,
+		Licenses:    transientInput.Licenses,
+		TermDays:    transientInput.TermDays,
+		RequestedAt: timestamp,
+	}
+
+	// check the renewal against the account's leases so the request is rejected before it reaches the admin
+	if req.Licenses, err = renewableLicenses(acctPvt, account, req); err != nil {
+		return err
+	}
+
+	bytes, err := json.Marshal(req)
+	if err != nil {
 		return fmt.Errorf("error marshaling request: %w", err)
 	}
 
 	return ctx.GetStub().PutPrivateData(collection, key, bytes)
 }
 
-func checkoutRequestKey(account, assetID string) string {
-	return fmt.Sprintf("checkout=%s:%s", account, assetID)
+func leaseRenewalRequestKey(account, assetID string) string {
+	return fmt.Sprintf("renewal=%s:%s", account, assetID)
 }
 
-func (b *BlossomSmartContract) GetCheckoutRequests(ctx contractapi.TransactionContextInterface, account string) ([]CheckoutRequest, error) {
-	collection := collections.Account(account)
-
-	key := checkoutRequestKey(account, "")
-
-	iter, err := ctx.GetStub().GetPrivateDataByRange(collection, "", "")
+func (b *BlossomSmartContract) GetLeaseRenewalRequests(ctx contractapi.TransactionContextInterface, account string) ([]LeaseRenewalRequest, error) {
+	keys, err := accountPrivateKeys(ctx, account, leaseRenewalRequestKey(account, ""))
 	if err != nil {
 		return nil, err
 	}
 
-	reqs := make([]CheckoutRequest, 0)
-	for iter.HasNext() {
-		next := &queryresult.KV{}
-		if next, err = iter.Next(); err != nil {
-			return nil, fmt.Errorf("error getting next KV: %w", err)
-		}
+	sort.Strings(keys)
 
-		if !strings.HasPrefix(next.Key, key) {
-			continue
+	reqs := make([]LeaseRenewalRequest, 0)
+	for _, key := range keys {
+		bytes, err := ctx.GetStub().GetPrivateData(collections.Account(account), key)
+		if err != nil {
+			return nil, fmt.Errorf("error getting request %s: %w", key, err)
 		}
 
-		req := CheckoutRequest{}
-		if err = json.Unmarshal(next.Value, &req); err != nil {
+		req := LeaseRenewalRequest{}
+		if err = json.Unmarshal(bytes, &req); err != nil {
 			return nil, fmt.Errorf("error unmarshaling request: %w", err)
 		}
 
@@ -313,28 +1581,28 @@ func (b *BlossomSmartContract) GetCheckoutRequests(ctx contractapi.TransactionCo
 	return reqs, nil
 }
 
-func (b *BlossomSmartContract) ApproveCheckout(ctx contractapi.TransactionContextInterface) error {
-	transientInput, err := getApproveCheckoutTransientInput(ctx)
+func (b *BlossomSmartContract) ProcessLeaseRenewal(ctx contractapi.TransactionContextInterface) error {
+	transientInput, err := getProcessLeaseRenewalTransientInput(ctx)
 	if err != nil {
 		return fmt.Errorf("error getting transient input: %w", err)
 	}
 
 	var (
 		acctColl = collections.Account(transientInput.Account)
-		key      = checkoutRequestKey(transientInput.Account, transientInput.AssetID)
+		key      = leaseRenewalRequestKey(transientInput.Account, transientInput.AssetID)
 		bytes    []byte
 	)
 
 	// ngac check
-	if err = decider.CanApproveCheckout(ctx, transientInput.Account); err != nil {
+	if err = decider.CanProcessLeaseRenewal(ctx, transientInput.Account); err != nil {
 		return fmt.Errorf("ngac check failed: %w", err)
 	}
 
 	// check that request exists
 	if bytes, err = ctx.GetStub().GetPrivateData(acctColl, key); err != nil {
-		return fmt.Errorf("error checking if request exists: %w", err)
+		return fmt.Errorf("error checking if renewal request exists: %w", err)
 	} else if bytes == nil {
-		return fmt.Errorf("request for asset %s does not exist for account %s", transientInput.AssetID, transientInput.Account)
+		return fmt.Errorf("request to renew leases of asset %s does not exist for account %s", transientInput.AssetID, transientInput.Account)
 	}
 
 	// delete request key
@@ -342,69 +1610,88 @@ func (b *BlossomSmartContract) ApproveCheckout(ctx contractapi.TransactionContex
 		return fmt.Errorf("error deleting request: %w", err)
 	}
 
-	req := &CheckoutRequest{}
-	if err = json.Unmarshal(bytes, req); err != nil {
+	req := LeaseRenewalRequest{}
+	if err = json.Unmarshal(bytes, &req); err != nil {
 		return fmt.Errorf("error unmarshaling request: %w", err)
 	}
 
 	acctPub, acctPvt, assetPub, assetPvt, err := getAcctAndAsset(ctx, transientInput.Account, transientInput.AssetID)
 	if err != nil {
-		return fmt.Errorf("error getting account and asset to process checkout: %w", err)
+		return fmt.Errorf("error getting account and asset to renew leases: %w", err)
 	}
 
-	if err = checkout(assetPub, assetPvt, acctPub, acctPvt, req.Amount); err != nil {
-		return fmt.Errorf("error checking out %s for account %s: %w", transientInput.AssetID, transientInput.Account, err)
+	if err = renewLeases(assetPub, assetPvt, acctPub, acctPvt, req); err != nil {
+		return fmt.Errorf("error renewing leases of %s for account %s: %w", transientInput.AssetID, transientInput.Account, err)
 	}
 
-	return putAcctAndAsset(ctx, acctPub, acctPvt, assetPub, assetPvt)
+	return putAcctAndAsset(ctx, "ProcessLeaseRenewal", acctPub, acctPvt, assetPub, assetPvt)
 }
 
-func checkout(assetPub *model.AssetPublic, assetPvt *model.AssetPrivate, acctPub *model.AccountPublic, acctPvt *model.AccountPrivate, amount int) error {
-	// check that the amount requested is less than the amount available
-	if amount > assetPub.Available {
-		return fmt.Errorf("requested amount %v cannot be greater than the available amount %v",
-			amount, assetPub.Available)
+// renewableLicenses checks that every license in the renewal request is held by the account and that its lease had not
+// ended and could be extended when the request was made.  The licenses to renew are returned, which are all of the
+// licenses held by the account for the asset if the request does not list any.
+func renewableLicenses(acctPvt *model.AccountPrivate, account string, req LeaseRenewalRequest) ([]string, error) {
+	held, ok := acctPvt.Assets[req.Asset]
+	if !ok {
+		return nil, fmt.Errorf("account %s has not checked out any licenses for asset %s", account, req.Asset)
 	}
 
-	// update available amount
-	assetPub.Available -= amount
-
-	// get the available licenses
-	fromAvailable := assetPvt.AvailableLicenses[0:amount]
-	// update available licenses
-	assetPvt.AvailableLicenses = assetPvt.AvailableLicenses[amount:]
+	licenses := req.Licenses
+	if len(licenses) == 0 {
+		licenses = make([]string, 0)
+		for license := range held {
+			licenses = append(licenses, license)
+		}
+		sort.Strings(licenses)
+	}
 
-	// create the set of licenses that are checked out including expiration dates
-	retCheckedOutLicenses := make(map[string]string)
-	for _, license := range fromAvailable {
-		retCheckedOutLicenses[license] = assetPvt.Licenses[license]
+	for _, license := range licenses {
+		lease, ok := held[license]
+		if !ok {
+			return nil, fmt.Errorf("license %s was not checked out by %s", license, account)
+		} else if !req.RequestedAt.Before(lease.LeaseEnd) {
+			return nil, fmt.Errorf("lease of license %s ended on %s and cannot be renewed", license, lease.LeaseEnd.Format(time.RFC3339))
+		} else if !lease.LeaseEnd.Before(lease.Expiration) {
+			return nil, fmt.Errorf("lease of license %s already ends when the license expires on %s", license, lease.Expiration.Format(time.RFC3339))
+		}
 	}
 
-	// update the account assets
-	// add to existing asset if they are checking out more of a software asset
-	allCheckedOutAssets, ok := acctPvt.Assets[assetPub.ID]
-	if !ok {
-		allCheckedOutAssets = make(map[string]string)
+	return licenses, nil
+}
+
+// renewLeases extends the leases of the licenses in the renewal request by the term, starting from the end of each
+// lease, in both the account and the asset.  Licenses that were checked in after the request was made are skipped.
+func renewLeases(assetPub *model.AssetPublic, assetPvt *model.AssetPrivate, acctPub *model.AccountPublic, acctPvt *model.AccountPrivate,
+	req LeaseRenewalRequest) error {
+	held := acctPvt.Assets[assetPub.ID]
+
+	stillHeld := make([]string, 0)
+	for _, license := range req.Licenses {
+		if _, ok := held[license]; ok {
+			stillHeld = append(stillHeld, license)
+		}
 	}
 
-	for license, exp := range retCheckedOutLicenses {
-		allCheckedOutAssets[license] = exp
+	if len(stillHeld) == 0 {
+		return nil
 	}
 
-	// update asset in the account
-	acctPvt.Assets[assetPub.ID] = allCheckedOutAssets
+	req.Licenses = stillHeld
+	licenses, err := renewableLicenses(acctPvt, acctPub.Name, req)
+	if err != nil {
+		return err
+	}
 
-	// update the asset's account tracker
-	accountCheckedOut := make(map[string]string)
-	for license, exp := range allCheckedOutAssets {
-		accountCheckedOut[license] = exp
+	for _, license := range licenses {
+		lease := held[license].Extend(held[license].LeaseEnd, req.TermDays)
+		held[license] = lease
+		assetPvt.CheckedOut[acctPub.Name][license] = lease.Unsealed()
 	}
-	assetPvt.CheckedOut[acctPub.Name] = accountCheckedOut
 
 	return nil
 }
 
-func (b *BlossomSmartContract) GetLicenses(ctx contractapi.TransactionContextInterface, account, assetID string) (map[string]string, error) {
+func (b *BlossomSmartContract) GetLicenses(ctx contractapi.TransactionContextInterface, account, assetID string) (map[string]model.LicenseLease, error) {
 	bytes, err := ctx.GetStub().GetPrivateData(collections.Account(account), model.AccountKey(account))
 	if err != nil {
 		return nil, fmt.Errorf("error reading account private data: %w", err)
@@ -514,7 +1801,10 @@ func checkinRequestKey(account, assetID string) string {
 	return fmt.Sprintf("checkin=%s:%s", account, assetID)
 }
 
-func checkin(assetPub *model.AssetPublic, assetPvt *model.AssetPrivate, acctPub *model.AccountPublic, acctPvt *model.AccountPrivate, licenses []string) error {
+// checkin returns the licenses checked out by the account to the available licenses and charges the account for their
+// leases until the given time.
+func checkin(assetPub *model.AssetPublic, assetPvt *model.AssetPrivate, acctPub *model.AccountPublic, acctPvt *model.AccountPrivate,
+	licenses []string, now time.Time) error {
 	checkedOut := acctPvt.Assets[assetPub.ID]
 	for _, license := range licenses {
 		delete(checkedOut, license)
@@ -538,7 +1828,8 @@ func checkin(assetPub *model.AssetPublic, assetPvt *model.AssetPrivate, acctPub
 			return fmt.Errorf("returned license %s was not checked out by %s", license, acctPub.Name)
 		}
 
-		// remove the returned license from the checked out licenses
+		// charge the account for the lease and remove the returned license from the checked out licenses
+		assetPvt.Charge(assetPub, acctPub.Name, license, accountCheckedOut[license], now)
 		delete(accountCheckedOut, license)
 
 		// add the returned license to the available licenses
@@ -595,14 +1886,27 @@ func (b *BlossomSmartContract) ProcessCheckin(ctx contractapi.TransactionContext
 		return err
 	}
 
-	if err = checkin(assetPub, assetPvt, acctPub, acctPvt, req.Licenses); err != nil {
+	now, err := txTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = checkin(assetPub, assetPvt, acctPub, acctPvt, req.Licenses, now); err != nil {
 		return fmt.Errorf("error checking out %s for account %s: %w", transientInput.AssetID, transientInput.Account, err)
 	}
 
-	return putAcctAndAsset(ctx, acctPub, acctPvt, assetPub, assetPvt)
+	// offer the returned licenses to the requests waiting for them
+	accounts := map[string]*model.AccountPrivate{acctPub.Name: acctPvt}
+	if err = offerToWaitlist(ctx, "ProcessCheckin", assetPub, assetPvt, accounts); err != nil {
+		return err
+	}
+
+	return putAcctAndAsset(ctx, "ProcessCheckin", acctPub, acctPvt, assetPub, assetPvt)
 }
 
-func putAcctAndAsset(ctx contractapi.TransactionContextInterface, acctPub *model.AccountPublic, acctPvt *model.AccountPrivate,
+// putAcctAndAsset writes the account and asset to the ledger. Writes to the account and asset private info are recorded
+// in their history under the given operation.
+func putAcctAndAsset(ctx contractapi.TransactionContextInterface, operation string, acctPub *model.AccountPublic, acctPvt *model.AccountPrivate,
 	assetPub *model.AssetPublic, assetPvt *model.AssetPrivate) (err error) {
 	var (
 		bytes    []byte
@@ -620,14 +1924,26 @@ func putAcctAndAsset(ctx contractapi.TransactionContextInterface, acctPub *model
 	}
 
 	// put account private
+	if err = sealLicenseKeys(ctx, acctPvt, assetPub.ID, assetPvt); err != nil {
+		return
+	}
+
 	if bytes, err = json.Marshal(acctPvt); err != nil {
 		return
 	}
 
-	if err = ctx.GetStub().PutPrivateData(acctColl, acctKey, bytes); err != nil {
+	if err = putPrivateData(ctx, acctColl, acctKey, operation, bytes); err != nil {
 		return
 	}
 
+	return putAsset(ctx, operation, assetPub, assetPvt)
+}
+
+// putAsset writes the asset public info to the catalog and the asset private info to the licenses collection.  The
+// write to the asset private info is recorded in its history under the given operation.
+func putAsset(ctx contractapi.TransactionContextInterface, operation string, assetPub *model.AssetPublic, assetPvt *model.AssetPrivate) (err error) {
+	var bytes []byte
+
 	// put asset public (still pdc)
 	if bytes, err = json.Marshal(assetPub); err != nil {
 		return
@@ -637,37 +1953,23 @@ func putAcctAndAsset(ctx contractapi.TransactionContextInterface, acctPub *model
 		return
 	}
 
-	// put asset private
+	// put asset private, the charges are written under their own keys so they are kept after the asset is offboarded
+	if err = putCharges(ctx, assetPvt); err != nil {
+		return
+	}
+
 	if bytes, err = json.Marshal(assetPvt); err != nil {
 		return
 	}
 
-	return ctx.GetStub().PutPrivateData(collections.Licenses(), model.AssetKey(assetPub.ID), bytes)
+	return putPrivateData(ctx, collections.Licenses(), model.AssetKey(assetPub.ID), operation, bytes)
 }
 
 func getAcctAndAsset(ctx contractapi.TransactionContextInterface, account, assetID string) (*model.AccountPublic, *model.AccountPrivate, *model.AssetPublic, *model.AssetPrivate, error) {
-	var (
-		bytes []byte
-		err   error
-	)
-
-	// get licenses from license collection
-	if bytes, err = ctx.GetStub().GetPrivateData(collections.Licenses(), model.AssetKey(assetID)); err != nil {
-		return nil, nil, nil, nil, err
-	}
-
-	assetPvt := model.NewAssetPrivate()
-	if err = json.Unmarshal(bytes, &assetPvt); err != nil {
-		return nil, nil, nil, nil, err
-	}
-
-	// get asset public info from catalog collection to update available
-	if bytes, err = ctx.GetStub().GetPrivateData(collections.Catalog(), model.AssetKey(assetID)); err != nil {
-		return nil, nil, nil, nil, err
-	}
+	var bytes []byte
 
-	assetPub := model.NewAssetPublic()
-	if err = json.Unmarshal(bytes, &assetPub); err != nil {
+	assetPub, assetPvt, err := getAsset(ctx, assetID)
+	if err != nil {
 		return nil, nil, nil, nil, err
 	}
 
@@ -693,3 +1995,33 @@ func getAcctAndAsset(ctx contractapi.TransactionContextInterface, account, asset
 
 	return acctPub, acctPvt, assetPub, assetPvt, nil
 }
+
+// getAsset returns the asset public info from the catalog and the asset private info from the licenses collection.
+func getAsset(ctx contractapi.TransactionContextInterface, assetID string) (*model.AssetPublic, *model.AssetPrivate, error) {
+	var (
+		bytes []byte
+		err   error
+	)
+
+	// get licenses from license collection
+	if bytes, err = ctx.GetStub().GetPrivateData(collections.Licenses(), model.AssetKey(assetID)); err != nil {
+		return nil, nil, err
+	}
+
+	assetPvt := model.NewAssetPrivate()
+	if err = json.Unmarshal(bytes, &assetPvt); err != nil {
+		return nil, nil, err
+	}
+
+	// get asset public info from catalog collection to update available
+	if bytes, err = ctx.GetStub().GetPrivateData(collections.Catalog(), model.AssetKey(assetID)); err != nil {
+		return nil, nil, err
+	}
+
+	assetPub := model.NewAssetPublic()
+	if err = json.Unmarshal(bytes, &assetPub); err != nil {
+		return nil, nil, err
+	}
+
+	return assetPub, assetPvt, nil
+}
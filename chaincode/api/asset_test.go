@@ -7,6 +7,7 @@ import (
 	"github.com/usnistgov/blossom/chaincode/mocks"
 	"github.com/usnistgov/blossom/chaincode/model"
 	"testing"
+	"time"
 )
 
 func TestOnboardAsset(t *testing.T) {
@@ -34,27 +35,615 @@ func TestOnboardAsset(t *testing.T) {
 	err = json.Unmarshal(data, &assetPvt)
 	require.NoError(t, err)
 	require.Equal(t, 2, assetPvt.TotalAmount)
-	require.Equal(t, map[string]string{"1": "exp", "2": "exp"}, assetPvt.Licenses)
+	require.Equal(t, map[string]time.Time{"1": testLicenseExpiration, "2": testLicenseExpiration}, assetPvt.Licenses)
 	require.Equal(t, 2, len(assetPvt.AvailableLicenses))
 	require.Empty(t, assetPvt.CheckedOut)
 }
 
+func TestOnboardAssetDates(t *testing.T) {
+	ctx := newTestStub(t)
+	bcc := BlossomSmartContract{}
+
+	err := ctx.SetClientIdentity(mocks.Super)
+	require.NoError(t, err)
+
+	onboard := func(onboardDate, expiration string, licenses ...model.License) error {
+		err := ctx.SetTransient("asset", onboardAssetTransientInput{Licenses: licenses})
+		require.NoError(t, err)
+		return bcc.OnboardAsset(ctx, "123", "myasset", onboardDate, expiration)
+	}
+
+	var (
+		onboardDate = testOnboardingDate.Format(time.RFC3339)
+		expiration  = testAssetExpiration.Format(time.RFC3339)
+	)
+
+	t.Run("test malformed dates", func(t *testing.T) {
+		err = onboard("01/01/2020", expiration, model.License{LicenseID: "1"})
+		require.Error(t, err)
+		err = onboard(onboardDate, "2100-01-01", model.License{LicenseID: "1"})
+		require.Error(t, err)
+	})
+
+	t.Run("test expiration before onboarding", func(t *testing.T) {
+		err = onboard(expiration, onboardDate, model.License{LicenseID: "1"})
+		require.Error(t, err)
+	})
+
+	t.Run("test license outlives asset", func(t *testing.T) {
+		err = onboard(onboardDate, expiration, model.License{LicenseID: "1", Expiration: testAssetExpiration.AddDate(0, 0, 1)})
+		require.Error(t, err)
+	})
+
+	t.Run("test license expires before onboarding", func(t *testing.T) {
+		err = onboard(onboardDate, expiration, model.License{LicenseID: "1", Expiration: testOnboardingDate.AddDate(0, 0, -1)})
+		require.Error(t, err)
+	})
+
+	t.Run("test license without expiration expires with asset", func(t *testing.T) {
+		err = onboard(onboardDate, expiration, model.License{LicenseID: "1"})
+		require.NoError(t, err)
+
+		asset, err := bcc.GetAsset(ctx, "123")
+		require.NoError(t, err)
+		require.Equal(t, testOnboardingDate, asset.OnboardingDate)
+		require.Equal(t, testAssetExpiration, asset.Expiration)
+		require.Equal(t, map[string]time.Time{"1": testAssetExpiration}, asset.Licenses)
+	})
+}
+
 func TestOffboardAsset(t *testing.T) {
 	ctx := newTestStub(t)
 	bcc := BlossomSmartContract{}
 
-	onboardTestAsset(t, ctx, "123", "myasset", []string{"1", "2"})
-
-	err := bcc.OffboardAsset(ctx, "123")
+	onboardTestAsset(t, ctx, "123", "myasset", []string{"1", "2"})
+
+	err := bcc.OffboardAsset(ctx, "123")
+	require.NoError(t, err)
+
+	data, err := ctx.GetStub().GetPrivateData(collections.Catalog(), model.AssetKey("123"))
+	require.NoError(t, err)
+	require.Nil(t, data)
+
+	data, err = ctx.GetStub().GetPrivateData(collections.Licenses(), model.AssetKey("123"))
+	require.NoError(t, err)
+	require.Nil(t, data)
+}
+
+func TestForceOffboardAsset(t *testing.T) {
+	ctx := newTestStub(t)
+	bcc := BlossomSmartContract{}
+
+	onboardTestAsset(t, ctx, "123", "myasset", []string{"1", "2", "3"})
+	requestTestAccount(t, ctx, Org2MSP)
+	requestTestAccount(t, ctx, Org3MSP)
+
+	checkoutTestAsset(t, ctx, Org2MSP, "123", 2)
+
+	err := ctx.SetClientIdentity(mocks.Org2SystemAdmin)
+	require.NoError(t, err)
+	err = ctx.SetTransient("swid", reportSwIDTransientInput{PrimaryTag: "tag1", Asset: "123", License: "1", Xml: "swid_xml"})
+	require.NoError(t, err)
+	err = bcc.ReportSwID(ctx)
+	require.NoError(t, err)
+
+	err = ctx.SetClientIdentity(mocks.Org3SystemAdmin)
+	require.NoError(t, err)
+	err = ctx.SetTransient("checkout", testCheckoutInput("123", 1))
+	require.NoError(t, err)
+	err = bcc.RequestCheckout(ctx)
+	require.NoError(t, err)
+
+	t.Run("test offboard refuses", func(t *testing.T) {
+		err = ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		err = bcc.OffboardAsset(ctx, "123")
+		require.Error(t, err)
+	})
+
+	t.Run("test force offboard", func(t *testing.T) {
+		err = ctx.SetClientIdentity(mocks.Org2SystemAdmin)
+		require.NoError(t, err)
+		err = bcc.ForceOffboardAsset(ctx, "123", "contract terminated")
+		require.Error(t, err)
+
+		err = ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		err = bcc.ForceOffboardAsset(ctx, "123", "")
+		require.Error(t, err)
+
+		err = bcc.ForceOffboardAsset(ctx, "unknown", "contract terminated")
+		require.EqualError(t, err, "an asset with the ID \"unknown\" does not exist")
+		err = bcc.ForceOffboardAsset(ctx, "123", "contract terminated")
+		require.NoError(t, err)
+
+		data, err := ctx.GetStub().GetPrivateData(collections.Catalog(), model.AssetKey("123"))
+		require.NoError(t, err)
+		require.Nil(t, data)
+
+		acctPvt, err := getAccountPrivate(ctx, Org2MSP)
+		require.NoError(t, err)
+		require.NotContains(t, acctPvt.Assets, "123")
+
+		reqs, err := bcc.GetCheckoutRequests(ctx, Org3MSP)
+		require.NoError(t, err)
+		require.Len(t, reqs, 1)
+		require.Equal(t, CheckoutCancelled, reqs[0].Status)
+
+		swids, err := bcc.GetSwIDsAssociatedWithAsset(ctx, Org2MSP, "123")
+		require.NoError(t, err)
+		require.Len(t, swids, 1)
+		require.True(t, swids[0].Flagged)
+		require.Equal(t, "contract terminated", swids[0].FlagReason)
+	})
+}
+
+func TestAddLicenses(t *testing.T) {
+	ctx := newTestStub(t)
+	bcc := BlossomSmartContract{}
+
+	onboardTestAsset(t, ctx, "123", "myasset", []string{"1", "2"})
+
+	t.Run("test add licenses", func(t *testing.T) {
+		err := ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		err = ctx.SetTransient("licenses", addLicensesTransientInput{Licenses: []model.License{
+			{LicenseID: "3", Expiration: testLicenseExpiration},
+			{LicenseID: "4", Expiration: testLicenseExpiration},
+		}})
+		require.NoError(t, err)
+		err = bcc.AddLicenses(ctx, "123")
+		require.NoError(t, err)
+
+		asset, err := bcc.GetAsset(ctx, "123")
+		require.NoError(t, err)
+		require.Equal(t, 4, asset.TotalAmount)
+		require.Equal(t, 4, asset.Available)
+		require.Equal(t, []string{"1", "2", "3", "4"}, asset.AvailableLicenses)
+		require.Equal(t, map[string]time.Time{"1": testLicenseExpiration, "2": testLicenseExpiration, "3": testLicenseExpiration, "4": testLicenseExpiration}, asset.Licenses)
+	})
+
+	t.Run("test existing license", func(t *testing.T) {
+		err := ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		err = ctx.SetTransient("licenses", addLicensesTransientInput{Licenses: []model.License{
+			{LicenseID: "1", Expiration: testLicenseExpiration},
+		}})
+		require.NoError(t, err)
+		err = bcc.AddLicenses(ctx, "123")
+		require.Error(t, err)
+	})
+
+	t.Run("test license outlives asset", func(t *testing.T) {
+		err := ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		err = ctx.SetTransient("licenses", addLicensesTransientInput{Licenses: []model.License{
+			{LicenseID: "5", Expiration: testAssetExpiration.AddDate(1, 0, 0)},
+		}})
+		require.NoError(t, err)
+		err = bcc.AddLicenses(ctx, "123")
+		require.Error(t, err)
+	})
+
+	t.Run("test unauthorized", func(t *testing.T) {
+		requestTestAccount(t, ctx, Org2MSP)
+
+		err := ctx.SetClientIdentity(mocks.Org2SystemAdmin)
+		require.NoError(t, err)
+		err = ctx.SetTransient("licenses", addLicensesTransientInput{Licenses: []model.License{
+			{LicenseID: "5", Expiration: testLicenseExpiration},
+		}})
+		require.NoError(t, err)
+		err = bcc.AddLicenses(ctx, "123")
+		require.Error(t, err)
+	})
+}
+
+func TestRetireLicenses(t *testing.T) {
+	ctx := newTestStub(t)
+	bcc := BlossomSmartContract{}
+
+	onboardTestAsset(t, ctx, "123", "myasset", []string{"1", "2", "3"})
+	requestTestAccount(t, ctx, Org2MSP)
+	checkoutTestAsset(t, ctx, Org2MSP, "123", 1)
+
+	t.Run("test retire checked out license", func(t *testing.T) {
+		err := ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		err = ctx.SetTransient("licenses", retireLicensesTransientInput{Licenses: []string{"1"}})
+		require.NoError(t, err)
+		err = bcc.RetireLicenses(ctx, "123")
+		require.Error(t, err)
+	})
+
+	t.Run("test retire unknown license", func(t *testing.T) {
+		err := ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		err = ctx.SetTransient("licenses", retireLicensesTransientInput{Licenses: []string{"4"}})
+		require.NoError(t, err)
+		err = bcc.RetireLicenses(ctx, "123")
+		require.Error(t, err)
+	})
+
+	t.Run("test retire licenses", func(t *testing.T) {
+		err := ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		err = ctx.SetTransient("licenses", retireLicensesTransientInput{Licenses: []string{"3"}})
+		require.NoError(t, err)
+		err = bcc.RetireLicenses(ctx, "123")
+		require.NoError(t, err)
+
+		asset, err := bcc.GetAsset(ctx, "123")
+		require.NoError(t, err)
+		require.Equal(t, 2, asset.TotalAmount)
+		require.Equal(t, 1, asset.Available)
+		require.Equal(t, []string{"2"}, asset.AvailableLicenses)
+		require.Equal(t, map[string]time.Time{"1": testLicenseExpiration, "2": testLicenseExpiration}, asset.Licenses)
+	})
+}
+
+func TestUpdateAssetExpiration(t *testing.T) {
+	ctx := newTestStub(t)
+	bcc := BlossomSmartContract{}
+
+	onboardTestAsset(t, ctx, "123", "myasset", []string{"1", "2"})
+
+	err := ctx.SetClientIdentity(mocks.Super)
+	require.NoError(t, err)
+
+	t.Run("test malformed expiration", func(t *testing.T) {
+		err = bcc.UpdateAssetExpiration(ctx, "123", "01/01/2101")
+		require.Error(t, err)
+	})
+
+	t.Run("test expiration before onboarding", func(t *testing.T) {
+		err = bcc.UpdateAssetExpiration(ctx, "123", testOnboardingDate.AddDate(0, 0, -1).Format(time.RFC3339))
+		require.Error(t, err)
+	})
+
+	t.Run("test expiration before license expiration", func(t *testing.T) {
+		err = bcc.UpdateAssetExpiration(ctx, "123", testLicenseExpiration.AddDate(0, 0, -1).Format(time.RFC3339))
+		require.Error(t, err)
+	})
+
+	t.Run("test asset does not exist", func(t *testing.T) {
+		err = bcc.UpdateAssetExpiration(ctx, "321", "2101-01-01T00:00:00Z")
+		require.Error(t, err)
+	})
+
+	err = bcc.UpdateAssetExpiration(ctx, "123", "2101-01-01T00:00:00Z")
+	require.NoError(t, err)
+
+	asset, err := bcc.GetAsset(ctx, "123")
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2101, 1, 1, 0, 0, 0, 0, time.UTC), asset.Expiration)
+}
+
+func TestGetAssetHistory(t *testing.T) {
+	ctx := newTestStub(t)
+	bcc := BlossomSmartContract{}
+
+	onboardTestAsset(t, ctx, "123", "myasset", []string{"1", "2"})
+	requestTestAccount(t, ctx, Org2MSP)
+
+	err := ctx.SetClientIdentity(mocks.Org2SystemAdmin)
+	require.NoError(t, err)
+	err = ctx.SetTransient("checkout", testCheckoutInput("123", 1))
+	require.NoError(t, err)
+	err = bcc.RequestCheckout(ctx)
+	require.NoError(t, err)
+
+	err = ctx.SetClientIdentity(mocks.Super)
+	require.NoError(t, err)
+	err = ctx.SetTransient("checkout", approveCheckoutTransientInput{Account: Org2MSP, AssetID: "123"})
+	require.NoError(t, err)
+	err = bcc.ApproveCheckout(ctx)
+	require.NoError(t, err)
+
+	history, err := bcc.GetAssetHistory(ctx, "123")
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Equal(t, "OnboardAsset", history[0].Operation)
+	require.Equal(t, "ApproveCheckout", history[1].Operation)
+	require.Equal(t, "adminuser:Org1MSP", history[1].Actor)
+	require.NotEmpty(t, history[1].Hash)
+
+	// the account's private history records the checkout too
+	acctHistory, err := bcc.GetAccountPrivateHistory(ctx, Org2MSP)
+	require.NoError(t, err)
+	require.Equal(t, "ApproveCheckout", acctHistory[len(acctHistory)-1].Operation)
+	require.Equal(t, history[1].TxID, acctHistory[len(acctHistory)-1].TxID)
+
+	t.Run("test history is kept after offboarding", func(t *testing.T) {
+		onboardTestAsset(t, ctx, "321", "myasset2", []string{"1"})
+
+		err = ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		err = bcc.OffboardAsset(ctx, "321")
+		require.NoError(t, err)
+
+		history, err = bcc.GetAssetHistory(ctx, "321")
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+		require.Equal(t, "OffboardAsset", history[1].Operation)
+		require.Empty(t, history[1].Hash)
+	})
+
+	t.Run("test accounts cannot read asset history", func(t *testing.T) {
+		err = ctx.SetClientIdentity(mocks.Org2SystemAdmin)
+		require.NoError(t, err)
+
+		_, err = bcc.GetAssetHistory(ctx, "123")
+		require.Error(t, err)
+	})
+}
+
+func TestGetLicenseRollup(t *testing.T) {
+	ctx := newTestStub(t)
+	bcc := BlossomSmartContract{}
+
+	onboardTestAsset(t, ctx, "123", "myasset1", []string{"1", "2", "3"})
+	onboardTestAsset(t, ctx, "321", "myasset2", []string{"1", "2"})
+
+	// Org2 is in NIST and Org3 is directly in the department
+	requestTestAccount(t, ctx, Org2MSP)
+	requestTestAccount(t, ctx, Org3MSP)
+
+	checkoutTestAsset(t, ctx, Org2MSP, "123", 2)
+	checkoutTestAsset(t, ctx, Org3MSP, "123", 1)
+	checkoutTestAsset(t, ctx, Org3MSP, "321", 1)
+
+	rollups, err := bcc.GetLicenseRollup(ctx)
+	require.NoError(t, err)
+	require.Len(t, rollups, 1)
+
+	dept := rollups[0]
+	require.Equal(t, "Department of Commerce", dept.Name)
+	require.Equal(t, model.DepartmentLevel, dept.Level)
+	require.Equal(t, map[string]int{"123": 3, "321": 1}, dept.Assets)
+	require.Equal(t, 4, dept.Total)
+	require.Len(t, dept.Children, 2)
+
+	require.Equal(t, "NIST", dept.Children[0].Name)
+	require.Equal(t, model.SubAgencyLevel, dept.Children[0].Level)
+	require.Equal(t, map[string]int{"123": 2}, dept.Children[0].Assets)
+	require.Len(t, dept.Children[0].Children, 1)
+	require.Equal(t, Org2MSP, dept.Children[0].Children[0].Name)
+	require.Equal(t, 2, dept.Children[0].Children[0].Total)
+
+	require.Equal(t, Org3MSP, dept.Children[1].Name)
+	require.Equal(t, model.AccountLevel, dept.Children[1].Level)
+	require.Equal(t, map[string]int{"123": 1, "321": 1}, dept.Children[1].Assets)
+
+	t.Run("test accounts cannot view roll-up", func(t *testing.T) {
+		err = ctx.SetClientIdentity(mocks.Org2SystemOwner)
+		require.NoError(t, err)
+		_, err = bcc.GetLicenseRollup(ctx)
+		require.Error(t, err)
+	})
+}
+
+func TestSweepExpiredLicenses(t *testing.T) {
+	ctx := newTestStub(t)
+	bcc := BlossomSmartContract{}
+
+	now := time.Now().UTC()
+	err := ctx.SetTxTimestamp(now)
+	require.NoError(t, err)
+
+	err = ctx.SetClientIdentity(mocks.Super)
+	require.NoError(t, err)
+	err = ctx.SetTransient("asset", onboardAssetTransientInput{Licenses: []model.License{
+		{LicenseID: "1", Expiration: now.Add(time.Hour)},
+		{LicenseID: "2", Expiration: now.Add(time.Hour)},
+		{LicenseID: "3", Expiration: now.AddDate(0, 0, 2)},
+		{LicenseID: "4"},
+	}})
+	require.NoError(t, err)
+	err = bcc.OnboardAsset(ctx, "123", "myasset", now.AddDate(0, 0, -1).Format(time.RFC3339), now.AddDate(1, 0, 0).Format(time.RFC3339))
+	require.NoError(t, err)
+
+	requestTestAccount(t, ctx, Org2MSP)
+	checkoutTestAsset(t, ctx, Org2MSP, "123", 1)
+
+	t.Run("test only admin can sweep", func(t *testing.T) {
+		err = ctx.SetClientIdentity(mocks.Org2SystemAdmin)
+		require.NoError(t, err)
+		_, err = bcc.SweepExpiredLicenses(ctx)
+		require.Error(t, err)
+	})
+
+	err = ctx.SetTxTimestamp(now.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	err = ctx.SetClientIdentity(mocks.Super)
+	require.NoError(t, err)
+
+	expired, err := bcc.SweepExpiredLicenses(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{"123": {"2"}}, expired.Removed)
+	require.Equal(t, 1, len(expired.Revoked))
+	require.Equal(t, Org2MSP, expired.Revoked[0].Account)
+	require.Equal(t, map[string][]string{"123": {"1"}}, expired.Revoked[0].Licenses)
+
+	payload, ok := ctx.GetEvent(model.LicensesExpiredEvent)
+	require.True(t, ok)
+
+	event := model.LicensesExpired{}
+	err = json.Unmarshal(payload, &event)
+	require.NoError(t, err)
+	require.Equal(t, *expired, event)
+
+	asset, err := bcc.GetAsset(ctx, "123")
+	require.NoError(t, err)
+	require.Equal(t, 2, asset.TotalAmount)
+	require.Equal(t, 2, asset.Available)
+	require.Equal(t, []string{"3", "4"}, asset.AvailableLicenses)
+	require.Empty(t, asset.CheckedOut)
+
+	licenses, err := bcc.GetLicenses(ctx, Org2MSP, "123")
+	require.NoError(t, err)
+	require.Empty(t, licenses)
+
+	t.Run("test nothing expired", func(t *testing.T) {
+		err = ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		expired, err = bcc.SweepExpiredLicenses(ctx)
+		require.NoError(t, err)
+		require.Empty(t, expired.Removed)
+		require.Empty(t, expired.Revoked)
+	})
+}
+
+func TestCheckoutSkipsExpiredLicenses(t *testing.T) {
+	ctx := newTestStub(t)
+	bcc := BlossomSmartContract{}
+
+	now := time.Now().UTC()
+	err := ctx.SetTxTimestamp(now)
+	require.NoError(t, err)
+
+	err = ctx.SetClientIdentity(mocks.Super)
+	require.NoError(t, err)
+	err = ctx.SetTransient("asset", onboardAssetTransientInput{Licenses: []model.License{
+		{LicenseID: "1", Expiration: now.AddDate(0, 0, -1)},
+		{LicenseID: "2", Expiration: testLicenseExpiration},
+	}})
+	require.NoError(t, err)
+	err = bcc.OnboardAsset(ctx, "123", "myasset", now.AddDate(0, 0, -7).Format(time.RFC3339), testAssetExpiration.Format(time.RFC3339))
+	require.NoError(t, err)
+
+	requestTestAccount(t, ctx, Org2MSP)
+
+	t.Run("test not enough unexpired licenses", func(t *testing.T) {
+		err = ctx.SetClientIdentity(mocks.Org2SystemAdmin)
+		require.NoError(t, err)
+		err = ctx.SetTransient("checkout", testCheckoutInput("123", 2))
+		require.NoError(t, err)
+		err = bcc.RequestCheckout(ctx)
+		require.NoError(t, err)
+
+		err = ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		err = ctx.SetTransient("checkout", approveCheckoutTransientInput{Account: Org2MSP, AssetID: "123"})
+		require.NoError(t, err)
+		err = bcc.ApproveCheckout(ctx)
+		require.NoError(t, err)
+
+		// the expired license is not counted so the request waits for another license
+		reqs, err := bcc.GetCheckoutRequests(ctx, Org2MSP)
+		require.NoError(t, err)
+		require.Equal(t, CheckoutWaitlisted, reqs[0].Status)
+
+		err = ctx.SetTransient("checkout", denyCheckoutTransientInput{Account: Org2MSP, AssetID: "123", Reason: "not enough licenses"})
+		require.NoError(t, err)
+		err = bcc.DenyCheckout(ctx)
+		require.NoError(t, err)
+	})
+
+	t.Run("test skips expired license", func(t *testing.T) {
+		checkoutTestAsset(t, ctx, Org2MSP, "123", 1)
+
+		licenses, err := bcc.GetLicenses(ctx, Org2MSP, "123")
+		require.NoError(t, err)
+		require.Equal(t, 1, len(licenses))
+		require.Equal(t, testLicenseExpiration, licenses["2"].Expiration)
+
+		asset, err := bcc.GetAsset(ctx, "123")
+		require.NoError(t, err)
+		require.Equal(t, []string{"1"}, asset.AvailableLicenses)
+		require.Equal(t, 1, asset.Available)
+	})
+}
+
+func TestAssetDates(t *testing.T) {
+	ctx := newTestStub(t)
+	bcc := BlossomSmartContract{}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	err := ctx.SetTxTimestamp(now)
 	require.NoError(t, err)
 
-	data, err := ctx.GetStub().GetPrivateData(collections.Catalog(), model.AssetKey("123"))
-	require.NoError(t, err)
-	require.Nil(t, data)
+	onboard := func(id string, onboardDate, expiration time.Time) {
+		err = ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		err = ctx.SetTransient("asset", onboardAssetTransientInput{Licenses: []model.License{
+			{LicenseID: "1", Expiration: expiration},
+		}})
+		require.NoError(t, err)
+		err = bcc.OnboardAsset(ctx, id, id, onboardDate.Format(time.RFC3339), expiration.Format(time.RFC3339))
+		require.NoError(t, err)
+	}
 
-	data, err = ctx.GetStub().GetPrivateData(collections.Licenses(), model.AssetKey("123"))
-	require.NoError(t, err)
-	require.Nil(t, data)
+	onboard("soon", now.AddDate(0, 0, -7), now.AddDate(0, 0, 10))
+	onboard("later", now.AddDate(0, 0, -7), now.AddDate(0, 0, 60))
+	onboard("future", now.AddDate(0, 0, 30), testAssetExpiration)
+
+	requestTestAccount(t, ctx, Org2MSP)
+
+	t.Run("test request checkout before onboarding", func(t *testing.T) {
+		err = ctx.SetClientIdentity(mocks.Org2SystemAdmin)
+		require.NoError(t, err)
+		err = ctx.SetTransient("checkout", testCheckoutInput("future", 1))
+		require.NoError(t, err)
+		err = bcc.RequestCheckout(ctx)
+		require.Error(t, err)
+
+		err = ctx.SetTransient("checkout", testCheckoutInput("soon", 1))
+		require.NoError(t, err)
+		err = bcc.RequestCheckout(ctx)
+		require.NoError(t, err)
+	})
+
+	t.Run("test get expiring assets", func(t *testing.T) {
+		err = ctx.SetClientIdentity(mocks.Org2SystemAdmin)
+		require.NoError(t, err)
+		_, err = bcc.GetExpiringAssets(ctx, 30)
+		require.Error(t, err)
+
+		err = ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		_, err = bcc.GetExpiringAssets(ctx, -1)
+		require.Error(t, err)
+
+		assets, err := bcc.GetExpiringAssets(ctx, 30)
+		require.NoError(t, err)
+		require.Len(t, assets, 1)
+		require.Equal(t, "soon", assets[0].ID)
+
+		assets, err = bcc.GetExpiringAssets(ctx, 90)
+		require.NoError(t, err)
+		require.Len(t, assets, 2)
+		require.Equal(t, "soon", assets[0].ID)
+		require.Equal(t, "later", assets[1].ID)
+	})
+
+	t.Run("test approve checkout after expiration", func(t *testing.T) {
+		err = ctx.SetTxTimestamp(now.AddDate(0, 0, 11))
+		require.NoError(t, err)
+
+		err = ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		err = ctx.SetTransient("checkout", approveCheckoutTransientInput{Account: Org2MSP, AssetID: "soon"})
+		require.NoError(t, err)
+		err = bcc.ApproveCheckout(ctx)
+		require.Error(t, err)
+
+		assets, err := bcc.GetAssets(ctx)
+		require.NoError(t, err)
+		status := make(map[string]model.AssetStatus)
+		for _, asset := range assets {
+			status[asset.ID] = asset.Status
+		}
+		require.Equal(t, map[string]model.AssetStatus{
+			"soon":   model.AssetExpired,
+			"later":  model.AssetActive,
+			"future": model.AssetPending,
+		}, status)
+
+		assets, err = bcc.GetExpiringAssets(ctx, 30)
+		require.NoError(t, err)
+		require.Empty(t, assets)
+	})
 }
 
 func TestGetAssets(t *testing.T) {
@@ -85,7 +674,7 @@ func TestGetAsset(t *testing.T) {
 	require.Equal(t, 2, asset.TotalAmount)
 	require.Equal(t, 2, asset.Available)
 	require.Equal(t, 2, len(asset.AvailableLicenses))
-	require.Equal(t, map[string]string{"1": "exp", "2": "exp"}, asset.Licenses)
+	require.Equal(t, map[string]time.Time{"1": testLicenseExpiration, "2": testLicenseExpiration}, asset.Licenses)
 	require.Empty(t, asset.CheckedOut)
 }
 
@@ -97,17 +686,13 @@ func TestCheckout(t *testing.T) {
 
 	requestTestAccount(t, ctx, Org2MSP)
 
-	err := ctx.SetClientIdentity(mocks.Super)
-	require.NoError(t, err)
-
-	err = bcc.UpdateAccountStatus(ctx, Org2MSP, "AUTHORIZED")
-	require.NoError(t, err)
+	var err error
 
 	t.Run("error unauthorized to request checkout", func(t *testing.T) {
 		err = ctx.SetClientIdentity(mocks.Org2SystemOwner)
 		require.NoError(t, err)
 
-		err = ctx.SetTransient("checkout", requestCheckoutTransientInput{"123", 1})
+		err = ctx.SetTransient("checkout", testCheckoutInput("123", 1))
 		require.NoError(t, err)
 		err = bcc.RequestCheckout(ctx)
 		require.Error(t, err)
@@ -116,7 +701,7 @@ func TestCheckout(t *testing.T) {
 	t.Run("authorized request checkout", func(t *testing.T) {
 		err = ctx.SetClientIdentity(mocks.Org2SystemAdmin)
 		require.NoError(t, err)
-		err = ctx.SetTransient("checkout", requestCheckoutTransientInput{"123", 1})
+		err = ctx.SetTransient("checkout", testCheckoutInput("123", 1))
 		require.NoError(t, err)
 		err = bcc.RequestCheckout(ctx)
 		require.NoError(t, err)
@@ -124,7 +709,7 @@ func TestCheckout(t *testing.T) {
 		err = ctx.SetClientIdentity(mocks.Super)
 		require.NoError(t, err)
 
-		err = ctx.SetTransient("checkout", approveCheckoutTransientInput{Org2MSP, "123"})
+		err = ctx.SetTransient("checkout", approveCheckoutTransientInput{Account: Org2MSP, AssetID: "123"})
 		require.NoError(t, err)
 		err = bcc.ApproveCheckout(ctx)
 		require.NoError(t, err)
@@ -132,7 +717,7 @@ func TestCheckout(t *testing.T) {
 		err = ctx.SetClientIdentity(mocks.Org2SystemAdmin)
 		require.NoError(t, err)
 
-		licenses := make(map[string]string, 0)
+		licenses := make(map[string]model.LicenseLease, 0)
 		licenses, err = bcc.GetLicenses(ctx, Org2MSP, "123")
 		require.NoError(t, err)
 		require.Equal(t, 1, len(licenses))
@@ -149,7 +734,7 @@ func TestCheckout(t *testing.T) {
 			require.Equal(t, "myasset", info.Name)
 			require.Equal(t, 1, len(info.AvailableLicenses))
 			require.Equal(t, 1, info.Available)
-			require.Equal(t, map[string]map[string]string{Org2MSP: {"1": licenses["1"]}}, info.CheckedOut)
+			require.Equal(t, map[string]map[string]model.LicenseLease{Org2MSP: {"1": licenses["1"]}}, info.CheckedOut)
 		})
 
 		t.Run("test GetAsset returns only public info for user", func(t *testing.T) {
@@ -190,14 +775,14 @@ func TestCheckout(t *testing.T) {
 		require.Equal(t, 0, len(licenses))
 
 		// update account to pending
-		err = bcc.UpdateAccountStatus(ctx, Org2MSP, "PENDING_ATO")
+		err = bcc.UpdateAccountStatus(ctx, Org2MSP, "PENDING_ATO", "ato under review")
 		require.NoError(t, err)
 
 		// checkout should fail
 		err = ctx.SetClientIdentity(mocks.Org2SystemAdmin)
 		require.NoError(t, err)
 
-		err = ctx.SetTransient("checkout", requestCheckoutTransientInput{"123", 1})
+		err = ctx.SetTransient("checkout", testCheckoutInput("123", 1))
 		require.NoError(t, err)
 		err = bcc.RequestCheckout(ctx)
 		require.Error(t, err)
@@ -215,12 +800,12 @@ func TestCheckoutRequests(t *testing.T) {
 	bcc := BlossomSmartContract{}
 	err := ctx.SetClientIdentity(mocks.Org2SystemAdmin)
 	require.NoError(t, err)
-	err = ctx.SetTransient("checkout", requestCheckoutTransientInput{"123", 1})
+	err = ctx.SetTransient("checkout", testCheckoutInput("123", 1))
 	require.NoError(t, err)
 	err = bcc.RequestCheckout(ctx)
 	require.NoError(t, err)
 
-	err = ctx.SetTransient("checkout", requestCheckoutTransientInput{"456", 1})
+	err = ctx.SetTransient("checkout", testCheckoutInput("456", 1))
 	require.NoError(t, err)
 	err = bcc.RequestCheckout(ctx)
 	require.NoError(t, err)
@@ -230,6 +815,276 @@ func TestCheckoutRequests(t *testing.T) {
 	require.Equal(t, 2, len(result))
 }
 
+func TestRequestCheckoutValidation(t *testing.T) {
+	ctx := newTestStub(t)
+	bcc := BlossomSmartContract{}
+
+	onboardTestAsset(t, ctx, "123", "myasset", []string{"1", "2"})
+	requestTestAccount(t, ctx, Org2MSP)
+
+	tests := map[string]func(input *requestCheckoutTransientInput){
+		"no justification":    func(input *requestCheckoutTransientInput) { input.Justification = "" },
+		"no lease term":       func(input *requestCheckoutTransientInput) { input.LeaseTermDays = 0 },
+		"lease term too long": func(input *requestCheckoutTransientInput) { input.LeaseTermDays = model.MaxLeaseTermDays + 1 },
+		"no fisma system":     func(input *requestCheckoutTransientInput) { input.FISMASystem = "" },
+		"no needed by date":   func(input *requestCheckoutTransientInput) { input.NeededBy = time.Time{} },
+		"negative amount":     func(input *requestCheckoutTransientInput) { input.Amount = -1 },
+	}
+
+	for name, modify := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ctx.SetClientIdentity(mocks.Org2SystemAdmin)
+			require.NoError(t, err)
+
+			input := testCheckoutInput("123", 1)
+			modify(&input)
+			err = ctx.SetTransient("checkout", input)
+			require.NoError(t, err)
+			err = bcc.RequestCheckout(ctx)
+			require.Error(t, err)
+		})
+	}
+
+	err := ctx.SetClientIdentity(mocks.Org2SystemAdmin)
+	require.NoError(t, err)
+	err = ctx.SetTransient("checkout", testCheckoutInput("123", 1))
+	require.NoError(t, err)
+	err = bcc.RequestCheckout(ctx)
+	require.NoError(t, err)
+
+	reqs, err := bcc.GetCheckoutRequests(ctx, Org2MSP)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(reqs))
+	require.Equal(t, "test justification", reqs[0].Justification)
+	require.Equal(t, 90, reqs[0].LeaseTermDays)
+	require.Equal(t, "test system", reqs[0].FISMASystem)
+	require.Equal(t, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), reqs[0].NeededBy)
+}
+
+func TestLeaseRenewal(t *testing.T) {
+	ctx := newTestStub(t)
+	bcc := BlossomSmartContract{}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	err := ctx.SetTxTimestamp(now)
+	require.NoError(t, err)
+
+	onboardTestAsset(t, ctx, "123", "myasset", []string{"1", "2"})
+	requestTestAccount(t, ctx, Org2MSP)
+	checkoutTestAsset(t, ctx, Org2MSP, "123", 2)
+
+	licenses, err := bcc.GetLicenses(ctx, Org2MSP, "123")
+	require.NoError(t, err)
+	leaseEnd := licenses["1"].LeaseEnd
+	require.True(t, leaseEnd.After(now.AddDate(0, 0, 89)))
+	require.True(t, leaseEnd.Before(now.AddDate(0, 0, 91)))
+	require.Equal(t, testLicenseExpiration, licenses["1"].Expiration)
+
+	asset, err := bcc.GetAsset(ctx, "123")
+	require.NoError(t, err)
+	require.Equal(t, licenses, asset.CheckedOut[Org2MSP])
+
+	request := func(licenses []string) error {
+		err := ctx.SetClientIdentity(mocks.Org2SystemAdmin)
+		require.NoError(t, err)
+		err = ctx.SetTransient("renewal", requestLeaseRenewalTransientInput{AssetID: "123", Licenses: licenses, TermDays: 30})
+		require.NoError(t, err)
+		return bcc.RequestLeaseRenewal(ctx)
+	}
+
+	process := func() error {
+		err := ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		err = ctx.SetTransient("renewal", processLeaseRenewalTransientInput{Account: Org2MSP, AssetID: "123"})
+		require.NoError(t, err)
+		return bcc.ProcessLeaseRenewal(ctx)
+	}
+
+	t.Run("test renew license not checked out", func(t *testing.T) {
+		err = request([]string{"3"})
+		require.Error(t, err)
+	})
+
+	t.Run("test process without request", func(t *testing.T) {
+		err = process()
+		require.Error(t, err)
+	})
+
+	t.Run("test renew one license", func(t *testing.T) {
+		err = request([]string{"1"})
+		require.NoError(t, err)
+
+		err = request([]string{"1"})
+		require.Error(t, err, "a second request cannot be made until the first is processed")
+
+		reqs, err := bcc.GetLeaseRenewalRequests(ctx, Org2MSP)
+		require.NoError(t, err)
+		require.Len(t, reqs, 1)
+		require.Equal(t, []string{"1"}, reqs[0].Licenses)
+
+		err = process()
+		require.NoError(t, err)
+
+		reqs, err = bcc.GetLeaseRenewalRequests(ctx, Org2MSP)
+		require.NoError(t, err)
+		require.Len(t, reqs, 0)
+
+		licenses, err = bcc.GetLicenses(ctx, Org2MSP, "123")
+		require.NoError(t, err)
+		require.Equal(t, leaseEnd.AddDate(0, 0, 30), licenses["1"].LeaseEnd)
+		require.Equal(t, leaseEnd, licenses["2"].LeaseEnd)
+
+		asset, err = bcc.GetAsset(ctx, "123")
+		require.NoError(t, err)
+		require.Equal(t, licenses, asset.CheckedOut[Org2MSP])
+	})
+
+	t.Run("test account cannot process renewal", func(t *testing.T) {
+		err = request(nil)
+		require.NoError(t, err)
+
+		err = ctx.SetClientIdentity(mocks.Org2SystemAdmin)
+		require.NoError(t, err)
+		err = ctx.SetTransient("renewal", processLeaseRenewalTransientInput{Account: Org2MSP, AssetID: "123"})
+		require.NoError(t, err)
+		err = bcc.ProcessLeaseRenewal(ctx)
+		require.Error(t, err)
+	})
+
+	t.Run("test renew all licenses", func(t *testing.T) {
+		err = process()
+		require.NoError(t, err)
+
+		licenses, err = bcc.GetLicenses(ctx, Org2MSP, "123")
+		require.NoError(t, err)
+		require.Equal(t, leaseEnd.AddDate(0, 0, 60), licenses["1"].LeaseEnd)
+		require.Equal(t, leaseEnd.AddDate(0, 0, 30), licenses["2"].LeaseEnd)
+	})
+
+	t.Run("test renew after lease ended", func(t *testing.T) {
+		err = ctx.SetTxTimestamp(leaseEnd.AddDate(0, 0, 31))
+		require.NoError(t, err)
+		err = request([]string{"2"})
+		require.Error(t, err)
+	})
+}
+
+func TestResolveCheckoutRequests(t *testing.T) {
+	ctx := newTestStub(t)
+	bcc := BlossomSmartContract{}
+
+	onboardTestAsset(t, ctx, "123", "myasset", []string{"1", "2", "3"})
+	requestTestAccount(t, ctx, Org2MSP)
+
+	request := func(amount int) {
+		err := ctx.SetClientIdentity(mocks.Org2SystemAdmin)
+		require.NoError(t, err)
+		err = ctx.SetTransient("checkout", testCheckoutInput("123", amount))
+		require.NoError(t, err)
+		err = bcc.RequestCheckout(ctx)
+		require.NoError(t, err)
+	}
+
+	lastRequest := func() CheckoutRequest {
+		reqs, err := bcc.GetCheckoutRequests(ctx, Org2MSP)
+		require.NoError(t, err)
+		return reqs[len(reqs)-1]
+	}
+
+	t.Run("test deny", func(t *testing.T) {
+		request(2)
+
+		t.Run("test only admin can deny", func(t *testing.T) {
+			err := ctx.SetTransient("checkout", denyCheckoutTransientInput{Account: Org2MSP, AssetID: "123", Reason: "no"})
+			require.NoError(t, err)
+			err = bcc.DenyCheckout(ctx)
+			require.Error(t, err)
+		})
+
+		err := ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+
+		t.Run("test reason is required", func(t *testing.T) {
+			err = ctx.SetTransient("checkout", denyCheckoutTransientInput{Account: Org2MSP, AssetID: "123"})
+			require.NoError(t, err)
+			err = bcc.DenyCheckout(ctx)
+			require.Error(t, err)
+		})
+
+		err = ctx.SetTransient("checkout", denyCheckoutTransientInput{Account: Org2MSP, AssetID: "123", Reason: "not needed"})
+		require.NoError(t, err)
+		err = bcc.DenyCheckout(ctx)
+		require.NoError(t, err)
+
+		req := lastRequest()
+		require.Equal(t, CheckoutDenied, req.Status)
+		require.Equal(t, 2, len(req.History))
+		require.Equal(t, string(CheckoutPending), req.History[0].Status)
+		require.Equal(t, "not needed", req.History[1].Reason)
+		require.Equal(t, "adminuser:Org1MSP", req.History[1].Actor)
+
+		err = ctx.SetTransient("checkout", approveCheckoutTransientInput{Account: Org2MSP, AssetID: "123"})
+		require.NoError(t, err)
+		err = bcc.ApproveCheckout(ctx)
+		require.Error(t, err)
+	})
+
+	t.Run("test cancel", func(t *testing.T) {
+		request(2)
+
+		err := ctx.SetClientIdentity(mocks.Org2SystemAdmin)
+		require.NoError(t, err)
+		err = ctx.SetTransient("checkout", cancelCheckoutTransientInput{AssetID: "123", Reason: "changed plans"})
+		require.NoError(t, err)
+		err = bcc.CancelCheckout(ctx)
+		require.NoError(t, err)
+
+		req := lastRequest()
+		require.Equal(t, CheckoutCancelled, req.Status)
+		require.Equal(t, "org2user2:Org2MSP", req.History[1].Actor)
+
+		err = ctx.SetTransient("checkout", cancelCheckoutTransientInput{AssetID: "123"})
+		require.NoError(t, err)
+		err = bcc.CancelCheckout(ctx)
+		require.Error(t, err)
+	})
+
+	t.Run("test partial approval", func(t *testing.T) {
+		request(5)
+
+		err := ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+
+		t.Run("test approved amount greater than requested", func(t *testing.T) {
+			err = ctx.SetTransient("checkout", approveCheckoutTransientInput{Account: Org2MSP, AssetID: "123", Amount: 6})
+			require.NoError(t, err)
+			err = bcc.ApproveCheckout(ctx)
+			require.Error(t, err)
+		})
+
+		err = ctx.SetTransient("checkout", approveCheckoutTransientInput{Account: Org2MSP, AssetID: "123", Amount: 2, Reason: "only 3 available"})
+		require.NoError(t, err)
+		err = bcc.ApproveCheckout(ctx)
+		require.NoError(t, err)
+
+		req := lastRequest()
+		require.Equal(t, CheckoutPartiallyApproved, req.Status)
+		require.Equal(t, 2, req.Approved)
+
+		licenses, err := bcc.GetLicenses(ctx, Org2MSP, "123")
+		require.NoError(t, err)
+		require.Equal(t, 2, len(licenses))
+
+		asset, err := bcc.GetAsset(ctx, "123")
+		require.NoError(t, err)
+		require.Equal(t, 1, asset.Available)
+	})
+
+	reqs, err := bcc.GetCheckoutRequests(ctx, Org2MSP)
+	require.NoError(t, err)
+	require.Equal(t, 3, len(reqs))
+}
+
 func TestViewAssetPermissions(t *testing.T) {
 	ctx := newTestStub(t)
 	requestTestAccount(t, ctx, Org2MSP)
@@ -250,7 +1105,7 @@ func TestViewAssetPermissions(t *testing.T) {
 
 	// update account status
 	require.NoError(t, ctx.SetClientIdentity(mocks.Super))
-	err = bcc.UpdateAccountStatus(ctx, Org2MSP, "UNAUTHORIZED_DENIED")
+	err = bcc.UpdateAccountStatus(ctx, Org2MSP, "UNAUTHORIZED_SECURITY_RISK", "security incident")
 	require.NoError(t, err)
 
 	require.NoError(t, ctx.SetClientIdentity(mocks.Org2SystemAdmin))
@@ -261,3 +1116,140 @@ func TestViewAssetPermissions(t *testing.T) {
 	_, err = bcc.GetAsset(ctx, "123")
 	require.Error(t, err)
 }
+
+func TestAllocationStrategies(t *testing.T) {
+	ctx := newTestStub(t)
+	bcc := BlossomSmartContract{}
+
+	requestTestAccount(t, ctx, Org2MSP)
+	requestTestAccount(t, ctx, Org3MSP)
+
+	onboard := func(id string, strategy model.AllocationStrategy, licenses []model.License) {
+		err := ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		err = ctx.SetTransient("asset", onboardAssetTransientInput{Licenses: licenses, AllocationStrategy: strategy})
+		require.NoError(t, err)
+		err = bcc.OnboardAsset(ctx, id, id, testOnboardingDate.Format(time.RFC3339), testAssetExpiration.Format(time.RFC3339))
+		require.NoError(t, err)
+	}
+
+	checkedOut := func(account, assetID string) map[string]model.LicenseLease {
+		err := ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		asset, err := bcc.GetAsset(ctx, assetID)
+		require.NoError(t, err)
+		return asset.CheckedOut[account]
+	}
+
+	allocation := func(account, assetID string) model.AllocationStrategy {
+		reqs, err := bcc.GetCheckoutRequests(ctx, account)
+		require.NoError(t, err)
+		for _, req := range reqs {
+			if req.Asset == assetID {
+				return req.Allocation
+			}
+		}
+
+		return ""
+	}
+
+	t.Run("test invalid strategy", func(t *testing.T) {
+		err := ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		err = ctx.SetTransient("asset", onboardAssetTransientInput{
+			Licenses:           []model.License{{LicenseID: "1"}},
+			AllocationStrategy: "RANDOM",
+		})
+		require.NoError(t, err)
+		err = bcc.OnboardAsset(ctx, "bad", "bad", testOnboardingDate.Format(time.RFC3339), testAssetExpiration.Format(time.RFC3339))
+		require.Error(t, err)
+	})
+
+	t.Run("test expiring first", func(t *testing.T) {
+		onboard("exp", model.AllocationSoonestExpiring, []model.License{
+			{LicenseID: "e1", Expiration: testLicenseExpiration},
+			{LicenseID: "e2", Expiration: testLicenseExpiration.AddDate(-2, 0, 0)},
+			{LicenseID: "e3", Expiration: testLicenseExpiration.AddDate(-1, 0, 0)},
+		})
+
+		checkoutTestAsset(t, ctx, Org2MSP, "exp", 1)
+		require.Contains(t, checkedOut(Org2MSP, "exp"), "e2")
+		require.Equal(t, model.AllocationSoonestExpiring, allocation(Org2MSP, "exp"))
+
+		err := bcc.UpdateAllocationStrategy(ctx, "exp", "UNKNOWN")
+		require.Error(t, err)
+		err = bcc.UpdateAllocationStrategy(ctx, "exp", string(model.AllocationLatestExpiring))
+		require.NoError(t, err)
+
+		checkoutTestAsset(t, ctx, Org3MSP, "exp", 1)
+		require.Contains(t, checkedOut(Org3MSP, "exp"), "e1")
+		require.Equal(t, model.AllocationLatestExpiring, allocation(Org3MSP, "exp"))
+	})
+
+	t.Run("test same batch", func(t *testing.T) {
+		onboard("batch", model.AllocationSameBatch, []model.License{
+			{LicenseID: "b1", Batch: "A"},
+			{LicenseID: "b2", Batch: "B"},
+			{LicenseID: "b3", Batch: "A"},
+			{LicenseID: "b4", Batch: "B"},
+			{LicenseID: "b5", Batch: "A"},
+		})
+
+		// the smallest batch that can hold the whole checkout is used
+		checkoutTestAsset(t, ctx, Org2MSP, "batch", 2)
+		licenses := checkedOut(Org2MSP, "batch")
+		require.Len(t, licenses, 2)
+		require.Contains(t, licenses, "b2")
+		require.Contains(t, licenses, "b4")
+		require.Equal(t, model.AllocationSameBatch, allocation(Org2MSP, "batch"))
+	})
+
+	t.Run("test explicit", func(t *testing.T) {
+		onboard("explicit", model.AllocationExplicit, []model.License{{LicenseID: "x1"}, {LicenseID: "x2"}, {LicenseID: "x3"}})
+
+		err := ctx.SetClientIdentity(mocks.Org3SystemAdmin)
+		require.NoError(t, err)
+		err = ctx.SetTransient("checkout", testCheckoutInput("explicit", 2))
+		require.NoError(t, err)
+		err = bcc.RequestCheckout(ctx)
+		require.NoError(t, err)
+
+		approve := func(licenses ...string) error {
+			err := ctx.SetClientIdentity(mocks.Super)
+			require.NoError(t, err)
+			err = ctx.SetTransient("checkout", approveCheckoutTransientInput{Account: Org3MSP, AssetID: "explicit", Licenses: licenses})
+			require.NoError(t, err)
+			return bcc.ApproveCheckout(ctx)
+		}
+
+		// the approver must choose the licenses
+		require.Error(t, approve())
+		require.Error(t, approve("x1", "x2", "x3"))
+		require.Error(t, approve("x4"))
+		require.NoError(t, approve("x3"))
+
+		licenses := checkedOut(Org3MSP, "explicit")
+		require.Len(t, licenses, 1)
+		require.Contains(t, licenses, "x3")
+
+		reqs, err := bcc.GetCheckoutRequests(ctx, Org3MSP)
+		require.NoError(t, err)
+		require.Equal(t, model.AllocationExplicit, reqs[len(reqs)-1].Allocation)
+		require.Equal(t, CheckoutPartiallyApproved, reqs[len(reqs)-1].Status)
+
+		// licenses can only be chosen for assets with the explicit strategy
+		err = ctx.SetClientIdentity(mocks.Org3SystemAdmin)
+		require.NoError(t, err)
+		err = ctx.SetTransient("checkout", testCheckoutInput("batch", 1))
+		require.NoError(t, err)
+		err = bcc.RequestCheckout(ctx)
+		require.NoError(t, err)
+
+		err = ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		err = ctx.SetTransient("checkout", approveCheckoutTransientInput{Account: Org3MSP, AssetID: "batch", Licenses: []string{"b1"}})
+		require.NoError(t, err)
+		err = bcc.ApproveCheckout(ctx)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,196 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+	"github.com/usnistgov/blossom/chaincode/collections"
+	"github.com/usnistgov/blossom/chaincode/model"
+	decider "github.com/usnistgov/blossom/chaincode/ngac/pdp"
+)
+
+func (b *BlossomSmartContract) GetChargebacks(ctx contractapi.TransactionContextInterface, account string) ([]*model.ChargebackLedger, error) {
+	// ngac check
+	if err := decider.CanViewCostAllocation(ctx); err != nil {
+		return nil, fmt.Errorf("ngac check failed: %w", err)
+	}
+
+	now, err := txTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	charges, err := chargebackEntries(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	ledgers := make([]*model.ChargebackLedger, 0)
+	byPeriod := make(map[string]*model.ChargebackLedger)
+	for _, charge := range charges {
+		if charge.Account != account {
+			continue
+		}
+
+		ledger, ok := byPeriod[charge.Period]
+		if !ok {
+			ledger = &model.ChargebackLedger{
+				Account: account,
+				Period:  charge.Period,
+				Entries: make([]model.ChargebackEntry, 0),
+			}
+			byPeriod[charge.Period] = ledger
+			ledgers = append(ledgers, ledger)
+		}
+
+		ledger.Entries = append(ledger.Entries, charge)
+		ledger.TotalCents += charge.AmountCents
+	}
+
+	return ledgers, nil
+}
+
+func (b *BlossomSmartContract) GetCostAllocation(ctx contractapi.TransactionContextInterface, period string) (*model.CostAllocation, error) {
+	// ngac check
+	if err := decider.CanViewCostAllocation(ctx); err != nil {
+		return nil, fmt.Errorf("ngac check failed: %w", err)
+	}
+
+	now, err := txTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	charges, err := chargebackEntries(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	allocation := &model.CostAllocation{
+		Period:   period,
+		AsOf:     now,
+		Accounts: make(map[string]int64),
+		Assets:   make(map[string]int64),
+	}
+
+	for _, charge := range charges {
+		if period != "" && charge.Period != period {
+			continue
+		}
+
+		allocation.Accounts[charge.Account] += charge.AmountCents
+		allocation.Assets[charge.Asset] += charge.AmountCents
+		allocation.TotalCents += charge.AmountCents
+	}
+
+	return allocation, nil
+}
+
+// putCharges writes the charges held in the asset private info to the licenses collection, each under its own key, and
+// clears them from the asset.
+func putCharges(ctx contractapi.TransactionContextInterface, assetPvt *model.AssetPrivate) error {
+	for _, charge := range assetPvt.Charges {
+		bytes, err := json.Marshal(charge)
+		if err != nil {
+			return fmt.Errorf("error marshaling charge: %w", err)
+		}
+
+		if err = ctx.GetStub().PutPrivateData(collections.Licenses(), model.ChargebackKey(charge), bytes); err != nil {
+			return fmt.Errorf("error writing charge to licenses pdc: %w", err)
+		}
+	}
+
+	assetPvt.Charges = nil
+
+	return nil
+}
+
+// chargebackEntries returns the charges recorded for every lease that has ended and the charges accrued up to the given
+// time for every lease that is still held, ordered by period, account, asset, license and start.
+func chargebackEntries(ctx contractapi.TransactionContextInterface, now time.Time) ([]model.ChargebackEntry, error) {
+	iter, err := ctx.GetStub().GetPrivateDataByRange(collections.Licenses(), "", "")
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	charges := make([]model.ChargebackEntry, 0)
+	for iter.HasNext() {
+		var next *queryresult.KV
+		if next, err = iter.Next(); err != nil {
+			return nil, fmt.Errorf("error getting next KV: %w", err)
+		}
+
+		if strings.HasPrefix(next.Key, model.ChargebackPrefix) {
+			charge := model.ChargebackEntry{}
+			if err = json.Unmarshal(next.Value, &charge); err != nil {
+				return nil, fmt.Errorf("error unmarshaling charge %s: %w", next.Key, err)
+			}
+
+			charges = append(charges, charge)
+			continue
+		} else if !strings.HasPrefix(next.Key, model.AssetPrefix) {
+			continue
+		}
+
+		assetID := strings.TrimPrefix(next.Key, model.AssetPrefix)
+
+		assetPvt := model.NewAssetPrivate()
+		if err = json.Unmarshal(next.Value, assetPvt); err != nil {
+			return nil, fmt.Errorf("error unmarshaling asset %s: %w", assetID, err)
+		}
+
+		// assets recorded before charges were stored under their own keys may still carry them
+		charges = append(charges, assetPvt.Charges...)
+
+		if len(assetPvt.CheckedOut) == 0 {
+			continue
+		}
+
+		// the cost of leases that are still held is in the asset's public info
+		bytes, err := ctx.GetStub().GetPrivateData(collections.Catalog(), next.Key)
+		if err != nil {
+			return nil, fmt.Errorf("error getting asset %s: %w", assetID, err)
+		} else if bytes == nil {
+			// the asset has been offboarded
+			continue
+		}
+
+		assetPub := model.NewAssetPublic()
+		if err = json.Unmarshal(bytes, assetPub); err != nil {
+			return nil, fmt.Errorf("error unmarshaling asset %s: %w", assetID, err)
+		}
+
+		for account, leases := range assetPvt.CheckedOut {
+			for license, lease := range leases {
+				for _, charge := range assetPub.Charges(account, license, lease, now) {
+					charge.Accrued = true
+					charges = append(charges, charge)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(charges, func(i, j int) bool {
+		a, b := charges[i], charges[j]
+		switch {
+		case a.Period != b.Period:
+			return a.Period < b.Period
+		case a.Account != b.Account:
+			return a.Account < b.Account
+		case a.Asset != b.Asset:
+			return a.Asset < b.Asset
+		case a.License != b.License:
+			return a.License < b.License
+		default:
+			return a.Start.Before(b.Start)
+		}
+	})
+
+	return charges, nil
+}
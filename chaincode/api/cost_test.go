@@ -0,0 +1,155 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/usnistgov/blossom/chaincode/collections"
+	"github.com/usnistgov/blossom/chaincode/mocks"
+	"github.com/usnistgov/blossom/chaincode/model"
+)
+
+func TestChargebacks(t *testing.T) {
+	ctx := newTestStub(t)
+	bcc := BlossomSmartContract{}
+
+	onboardTestAsset(t, ctx, "123", "myasset", []string{"1", "2"})
+	requestTestAccount(t, ctx, Org2MSP)
+
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	t.Run("test update asset cost", func(t *testing.T) {
+		err := ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+
+		// $365 a year is $1 a day
+		cost := model.AssetCost{UnitCostCents: 36500, FundingSource: "O&M", ContractLineItem: "0001"}
+		err = ctx.SetTransient("cost", updateAssetCostTransientInput{AssetCost: model.AssetCost{UnitCostCents: -1}})
+		require.NoError(t, err)
+		err = bcc.UpdateAssetCost(ctx, "123")
+		require.Error(t, err)
+
+		err = ctx.SetTransient("cost", updateAssetCostTransientInput{AssetCost: cost})
+		require.NoError(t, err)
+		err = bcc.UpdateAssetCost(ctx, "123")
+		require.NoError(t, err)
+
+		asset, err := bcc.GetAsset(ctx, "123")
+		require.NoError(t, err)
+		require.Equal(t, &cost, asset.Cost)
+	})
+
+	t.Run("test checkin charges lease", func(t *testing.T) {
+		err := ctx.SetClientIdentity(mocks.Org2SystemAdmin)
+		require.NoError(t, err)
+		err = ctx.SetTransient("checkout", testCheckoutInput("123", 2))
+		require.NoError(t, err)
+		err = bcc.RequestCheckout(ctx)
+		require.NoError(t, err)
+
+		err = ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		err = ctx.SetTransient("checkout", approveCheckoutTransientInput{Account: Org2MSP, AssetID: "123"})
+		require.NoError(t, err)
+		err = ctx.SetTxTimestamp(day(2026, time.December, 22))
+		require.NoError(t, err)
+		err = bcc.ApproveCheckout(ctx)
+		require.NoError(t, err)
+
+		err = ctx.SetClientIdentity(mocks.Org2SystemAdmin)
+		require.NoError(t, err)
+		err = ctx.SetTransient("checkin", initiateCheckinTransientInput{AssetID: "123", Licenses: []string{"1"}})
+		require.NoError(t, err)
+		err = bcc.InitiateCheckin(ctx)
+		require.NoError(t, err)
+
+		err = ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		err = ctx.SetTransient("checkin", processCheckinTransientInput{Account: Org2MSP, AssetID: "123"})
+		require.NoError(t, err)
+		err = ctx.SetTxTimestamp(day(2027, time.January, 11))
+		require.NoError(t, err)
+		err = bcc.ProcessCheckin(ctx)
+		require.NoError(t, err)
+
+		// the charges are stored under their own keys, not in the asset
+		data, err := ctx.GetStub().GetPrivateData(collections.Licenses(), model.AssetKey("123"))
+		require.NoError(t, err)
+		assetPvt := model.NewAssetPrivate()
+		err = json.Unmarshal(data, assetPvt)
+		require.NoError(t, err)
+		require.Empty(t, assetPvt.Charges)
+	})
+
+	t.Run("test get chargebacks", func(t *testing.T) {
+		err := ctx.SetTxTimestamp(day(2027, time.January, 21))
+		require.NoError(t, err)
+
+		ledgers, err := bcc.GetChargebacks(ctx, Org2MSP)
+		require.NoError(t, err)
+		require.Len(t, ledgers, 2)
+
+		// 10 days of each license in the first quarter
+		require.Equal(t, "FY2027Q1", ledgers[0].Period)
+		require.Len(t, ledgers[0].Entries, 2)
+		require.Equal(t, int64(2000), ledgers[0].TotalCents)
+
+		// 10 days of the returned license and 20 days of the license still held in the second quarter
+		require.Equal(t, "FY2027Q2", ledgers[1].Period)
+		require.Len(t, ledgers[1].Entries, 2)
+		require.Equal(t, int64(3000), ledgers[1].TotalCents)
+		require.Equal(t, "1", ledgers[1].Entries[0].License)
+		require.False(t, ledgers[1].Entries[0].Accrued)
+		require.Equal(t, "2", ledgers[1].Entries[1].License)
+		require.True(t, ledgers[1].Entries[1].Accrued)
+		require.Equal(t, "0001", ledgers[1].Entries[1].ContractLineItem)
+	})
+
+	t.Run("test get cost allocation", func(t *testing.T) {
+		err := ctx.SetTxTimestamp(day(2027, time.January, 21))
+		require.NoError(t, err)
+
+		allocation, err := bcc.GetCostAllocation(ctx, "FY2027Q2")
+		require.NoError(t, err)
+		require.Equal(t, int64(3000), allocation.Accounts[Org2MSP])
+		require.Equal(t, int64(3000), allocation.Assets["123"])
+		require.Equal(t, int64(3000), allocation.TotalCents)
+
+		allocation, err = bcc.GetCostAllocation(ctx, "")
+		require.NoError(t, err)
+		require.Equal(t, int64(5000), allocation.TotalCents)
+
+		err = ctx.SetClientIdentity(mocks.Org2SystemAdmin)
+		require.NoError(t, err)
+		_, err = bcc.GetCostAllocation(ctx, "")
+		require.Error(t, err)
+	})
+
+	t.Run("test charges outlive offboarded asset", func(t *testing.T) {
+		err := ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		err = ctx.SetTxTimestamp(day(2027, time.January, 31))
+		require.NoError(t, err)
+		err = bcc.ForceOffboardAsset(ctx, "123", "vendor breach")
+		require.NoError(t, err)
+
+		ledgers, err := bcc.GetChargebacks(ctx, Org2MSP)
+		require.NoError(t, err)
+		require.Len(t, ledgers, 2)
+		require.Equal(t, int64(2000), ledgers[0].TotalCents)
+
+		// 10 days of the returned license and 30 days of the revoked license
+		require.Len(t, ledgers[1].Entries, 2)
+		require.Equal(t, int64(4000), ledgers[1].TotalCents)
+		require.False(t, ledgers[1].Entries[1].Accrued)
+
+		allocation, err := bcc.GetCostAllocation(ctx, "")
+		require.NoError(t, err)
+		require.Equal(t, int64(6000), allocation.Assets["123"])
+		require.Equal(t, int64(6000), allocation.TotalCents)
+	})
+}
@@ -0,0 +1,207 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"github.com/usnistgov/blossom/chaincode/collections"
+	"github.com/usnistgov/blossom/chaincode/model"
+	"github.com/usnistgov/blossom/chaincode/ngac/common"
+	decider "github.com/usnistgov/blossom/chaincode/ngac/pdp"
+)
+
+func NewForecastContract() ForecastInterface {
+	return &BlossomSmartContract{}
+}
+
+func (b *BlossomSmartContract) SubmitForecast(ctx contractapi.TransactionContextInterface) error {
+	transientInput, err := getSubmitForecastTransientInput(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting transient input: %w", err)
+	}
+
+	account, err := accountName(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting MSPID from stub: %w", err)
+	}
+
+	// ngac check
+	if err = decider.CanSubmitForecast(ctx, account); err != nil {
+		return fmt.Errorf("ngac check failed: %w", err)
+	}
+
+	now, err := txTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+
+	if current := model.FiscalYear(now); transientInput.FiscalYear < current {
+		return fmt.Errorf("cannot forecast demand for fiscal year %d before the current fiscal year %d",
+			transientInput.FiscalYear, current)
+	}
+
+	user, err := common.GetUser(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting user from ctx: %w", err)
+	}
+
+	txID := ctx.GetStub().GetTxID()
+	forecast := model.DemandForecast{
+		ID:            txID,
+		Product:       transientInput.Product,
+		Quantity:      transientInput.Quantity,
+		FiscalYear:    transientInput.FiscalYear,
+		Justification: transientInput.Justification,
+		SubmittedBy:   user,
+		SubmittedAt:   now,
+	}
+
+	bytes, err := json.Marshal(forecast)
+	if err != nil {
+		return fmt.Errorf("error marshaling forecast: %w", err)
+	}
+
+	if err = ctx.GetStub().PutPrivateData(collections.Account(account), model.ForecastKey(account, txID), bytes); err != nil {
+		return fmt.Errorf("error storing forecast: %w", err)
+	}
+
+	return nil
+}
+
+func (b *BlossomSmartContract) WithdrawForecast(ctx contractapi.TransactionContextInterface, forecastID string) error {
+	account, err := accountName(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting MSPID from stub: %w", err)
+	}
+
+	// ngac check
+	if err = decider.CanSubmitForecast(ctx, account); err != nil {
+		return fmt.Errorf("ngac check failed: %w", err)
+	}
+
+	key := model.ForecastKey(account, forecastID)
+	if bytes, err := ctx.GetStub().GetPrivateData(collections.Account(account), key); err != nil {
+		return fmt.Errorf("error getting forecast %s: %w", forecastID, err)
+	} else if bytes == nil {
+		return fmt.Errorf("forecast %s does not exist for account %s", forecastID, account)
+	}
+
+	if err = ctx.GetStub().DelPrivateData(collections.Account(account), key); err != nil {
+		return fmt.Errorf("error deleting forecast %s: %w", forecastID, err)
+	}
+
+	return nil
+}
+
+func (b *BlossomSmartContract) GetForecasts(ctx contractapi.TransactionContextInterface, account string) ([]*model.DemandForecast, error) {
+	return getForecasts(ctx, account)
+}
+
+// getForecasts returns the forecasts of the account ordered by when they were submitted.
+func getForecasts(ctx contractapi.TransactionContextInterface, account string) ([]*model.DemandForecast, error) {
+	keys, err := accountPrivateKeys(ctx, account, model.ForecastKey(account, ""))
+	if err != nil {
+		return nil, fmt.Errorf("error getting forecasts of account %q: %w", account, err)
+	}
+
+	forecasts := make([]*model.DemandForecast, 0)
+	for _, key := range keys {
+		bytes, err := ctx.GetStub().GetPrivateData(collections.Account(account), key)
+		if err != nil {
+			return nil, fmt.Errorf("error getting forecast %s: %w", key, err)
+		}
+
+		forecast := &model.DemandForecast{}
+		if err = json.Unmarshal(bytes, forecast); err != nil {
+			return nil, fmt.Errorf("error unmarshaling forecast: %w", err)
+		}
+
+		forecasts = append(forecasts, forecast)
+	}
+
+	sort.Slice(forecasts, func(i, j int) bool {
+		if forecasts[i].SubmittedAt.Equal(forecasts[j].SubmittedAt) {
+			return forecasts[i].ID < forecasts[j].ID
+		}
+
+		return forecasts[i].SubmittedAt.Before(forecasts[j].SubmittedAt)
+	})
+
+	return forecasts, nil
+}
+
+func (b *BlossomSmartContract) GetDemand(ctx contractapi.TransactionContextInterface, fiscalYear int) ([]*model.DemandAggregate, error) {
+	// ngac check
+	if err := decider.CanViewDemand(ctx); err != nil {
+		return nil, fmt.Errorf("ngac check failed: %w", err)
+	}
+
+	accounts, err := b.GetAccounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting accounts: %w", err)
+	}
+
+	// accounts are read in order so the product name reported for each aggregate is the same on every peer
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].Name < accounts[j].Name
+	})
+
+	type aggregateKey struct {
+		product    string
+		fiscalYear int
+	}
+
+	aggregates := make(map[aggregateKey]*model.DemandAggregate)
+	for _, acctPub := range accounts {
+		if acctPub.Status == model.Decommissioned {
+			continue
+		}
+
+		forecasts, err := getForecasts(ctx, acctPub.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, forecast := range forecasts {
+			if fiscalYear != 0 && forecast.FiscalYear != fiscalYear {
+				continue
+			}
+
+			key := aggregateKey{product: model.ProductKey(forecast.Product), fiscalYear: forecast.FiscalYear}
+			aggregate, ok := aggregates[key]
+			if !ok {
+				aggregate = &model.DemandAggregate{
+					Product:    forecast.Product,
+					FiscalYear: forecast.FiscalYear,
+					Accounts:   make(map[string]int),
+				}
+				aggregates[key] = aggregate
+			}
+
+			aggregate.Quantity += forecast.Quantity
+			aggregate.Accounts[acctPub.Name] += forecast.Quantity
+		}
+	}
+
+	keys := make([]aggregateKey, 0)
+	for key := range aggregates {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].fiscalYear == keys[j].fiscalYear {
+			return keys[i].product < keys[j].product
+		}
+
+		return keys[i].fiscalYear < keys[j].fiscalYear
+	})
+
+	demand := make([]*model.DemandAggregate, 0)
+	for _, key := range keys {
+		demand = append(demand, aggregates[key])
+	}
+
+	return demand, nil
+}
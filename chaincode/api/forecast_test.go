@@ -0,0 +1,103 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/usnistgov/blossom/chaincode/mocks"
+	"github.com/usnistgov/blossom/chaincode/model"
+)
+
+func TestForecasts(t *testing.T) {
+	ctx := newTestStub(t)
+	bcc := BlossomSmartContract{}
+
+	requestTestAccount(t, ctx, Org2MSP)
+	requestTestAccount(t, ctx, Org3MSP)
+
+	submit := func(identity func() (*mocks.ClientIdentity, error), product string, quantity, fiscalYear int) error {
+		err := ctx.SetClientIdentity(identity)
+		require.NoError(t, err)
+		err = ctx.SetTransient("forecast", submitForecastTransientInput{
+			Product:       product,
+			Quantity:      quantity,
+			FiscalYear:    fiscalYear,
+			Justification: "new hires",
+		})
+		require.NoError(t, err)
+		err = ctx.SetTxTimestamp(time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		return bcc.SubmitForecast(ctx)
+	}
+
+	t.Run("test submit forecast", func(t *testing.T) {
+		err := submit(mocks.Org2SystemAdmin, "Office", 0, 2027)
+		require.Error(t, err)
+
+		// fiscal year 2026 ended on September 30, 2026
+		err = submit(mocks.Org2SystemAdmin, "Office", 10, 2026)
+		require.Error(t, err)
+
+		err = submit(mocks.Org2SystemAdmin, "Office", 10, 2027)
+		require.NoError(t, err)
+		err = submit(mocks.Org2SystemAdmin, "Editor", 2, 2028)
+		require.NoError(t, err)
+		err = submit(mocks.Org3SystemAdmin, " office ", 5, 2027)
+		require.NoError(t, err)
+
+		err = ctx.SetClientIdentity(mocks.Org2SystemAdmin)
+		require.NoError(t, err)
+		forecasts, err := bcc.GetForecasts(ctx, Org2MSP)
+		require.NoError(t, err)
+		require.Len(t, forecasts, 2)
+		require.Equal(t, "Office", forecasts[0].Product)
+		require.Equal(t, 10, forecasts[0].Quantity)
+		require.Equal(t, "Editor", forecasts[1].Product)
+	})
+
+	t.Run("test get demand", func(t *testing.T) {
+		err := ctx.SetClientIdentity(mocks.Org2SystemAdmin)
+		require.NoError(t, err)
+		_, err = bcc.GetDemand(ctx, 0)
+		require.Error(t, err)
+
+		err = ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		demand, err := bcc.GetDemand(ctx, 0)
+		require.NoError(t, err)
+		require.Len(t, demand, 2)
+		require.Equal(t, &model.DemandAggregate{
+			Product:    "Office",
+			FiscalYear: 2027,
+			Quantity:   15,
+			Accounts:   map[string]int{Org2MSP: 10, Org3MSP: 5},
+		}, demand[0])
+		require.Equal(t, 2028, demand[1].FiscalYear)
+
+		demand, err = bcc.GetDemand(ctx, 2028)
+		require.NoError(t, err)
+		require.Len(t, demand, 1)
+		require.Equal(t, "Editor", demand[0].Product)
+	})
+
+	t.Run("test withdraw forecast", func(t *testing.T) {
+		err := ctx.SetClientIdentity(mocks.Org3SystemAdmin)
+		require.NoError(t, err)
+		forecasts, err := bcc.GetForecasts(ctx, Org3MSP)
+		require.NoError(t, err)
+		require.Len(t, forecasts, 1)
+
+		err = bcc.WithdrawForecast(ctx, "unknown")
+		require.Error(t, err)
+		err = bcc.WithdrawForecast(ctx, forecasts[0].ID)
+		require.NoError(t, err)
+
+		err = ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		demand, err := bcc.GetDemand(ctx, 2027)
+		require.NoError(t, err)
+		require.Len(t, demand, 1)
+		require.Equal(t, 10, demand[0].Quantity)
+	})
+}
@@ -0,0 +1,145 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+	"github.com/usnistgov/blossom/chaincode/model"
+	"github.com/usnistgov/blossom/chaincode/ngac/common"
+	"sort"
+	"strings"
+)
+
+// newUpdate returns the record of a change to the given status made by the user submitting the transaction.
+func newUpdate(ctx contractapi.TransactionContextInterface, status, reason string) (model.Update, error) {
+	actor, err := common.GetUser(ctx)
+	if err != nil {
+		return model.Update{}, fmt.Errorf("error getting user from ctx: %w", err)
+	}
+
+	timestamp, err := txTimestamp(ctx)
+	if err != nil {
+		return model.Update{}, err
+	}
+
+	return model.Update{
+		Status:    status,
+		Reason:    reason,
+		Actor:     actor,
+		TxID:      ctx.GetStub().GetTxID(),
+		Timestamp: timestamp,
+	}, nil
+}
+
+// putWithHistory appends the update to the history of the record and writes the record to the key in the given private
+// data collection, or in the world state if the collection is empty.
+func putWithHistory(ctx contractapi.TransactionContextInterface, collection, key string, record interface{},
+	history *[]model.Update, update model.Update) error {
+	*history = append(*history, update)
+
+	bytes, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	if collection == "" {
+		return ctx.GetStub().PutState(key, bytes)
+	}
+
+	return ctx.GetStub().PutPrivateData(collection, key, bytes)
+}
+
+// putPrivateData writes the value to the private data key and records the write in the key's history.
+func putPrivateData(ctx contractapi.TransactionContextInterface, collection, key, operation string, bytes []byte) error {
+	if err := ctx.GetStub().PutPrivateData(collection, key, bytes); err != nil {
+		return err
+	}
+
+	return putPrivateHistory(ctx, collection, key, operation, bytes)
+}
+
+// delPrivateData deletes the private data key and records the deletion in the key's history.
+func delPrivateData(ctx contractapi.TransactionContextInterface, collection, key, operation string) error {
+	if err := ctx.GetStub().DelPrivateData(collection, key); err != nil {
+		return err
+	}
+
+	return putPrivateHistory(ctx, collection, key, operation, nil)
+}
+
+// putPrivateHistory writes a history entry for the mutation of a private data key to the same collection as the key. A
+// nil value records a deletion.
+func putPrivateHistory(ctx contractapi.TransactionContextInterface, collection, key, operation string, value []byte) error {
+	actor, err := common.GetUser(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting user from ctx: %w", err)
+	}
+
+	timestamp, err := txTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+
+	entry := model.PrivateHistoryEntry{
+		TxID:      ctx.GetStub().GetTxID(),
+		Timestamp: timestamp,
+		Actor:     actor,
+		Operation: operation,
+	}
+
+	if value != nil {
+		hash := sha256.Sum256(value)
+		entry.Hash = hex.EncodeToString(hash[:])
+	}
+
+	bytes, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("error marshaling history entry for %s: %w", key, err)
+	}
+
+	if err = ctx.GetStub().PutPrivateData(collection, model.PrivateHistoryKey(key, entry.TxID), bytes); err != nil {
+		return fmt.Errorf("error writing history entry for %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// getPrivateHistory returns the history entries of the private data key ordered from oldest to newest.
+func getPrivateHistory(ctx contractapi.TransactionContextInterface, collection, key string) ([]model.PrivateHistoryEntry, error) {
+	prefix := model.PrivateHistoryKeyPrefix(key)
+
+	iter, err := ctx.GetStub().GetPrivateDataByRange(collection, prefix, prefix+"~")
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	history := make([]model.PrivateHistoryEntry, 0)
+	for iter.HasNext() {
+		var next *queryresult.KV
+		if next, err = iter.Next(); err != nil {
+			return nil, fmt.Errorf("error getting next KV: %w", err)
+		}
+
+		if !strings.HasPrefix(next.Key, prefix) {
+			continue
+		}
+
+		entry := model.PrivateHistoryEntry{}
+		if err = json.Unmarshal(next.Value, &entry); err != nil {
+			return nil, fmt.Errorf("error unmarshaling history entry: %w", err)
+		}
+
+		history = append(history, entry)
+	}
+
+	// keys are ordered by tx ID so sort the entries by the time of the transaction
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].Timestamp.Before(history[j].Timestamp)
+	})
+
+	return history, nil
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"github.com/usnistgov/blossom/chaincode/model"
+	decider "github.com/usnistgov/blossom/chaincode/ngac/pdp"
+	"github.com/usnistgov/blossom/chaincode/seal"
+)
+
+func (b *BlossomSmartContract) RegisterEncryptionKey(ctx contractapi.TransactionContextInterface) error {
+	transientInput, err := getRegisterEncryptionKeyTransientInput(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting transient input: %w", err)
+	}
+
+	account, err := accountName(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting account name from stub: %w", err)
+	}
+
+	// ngac check
+	if err = decider.CanRegisterEncryptionKey(ctx, account); err != nil {
+		return fmt.Errorf("ngac check failed: %w", err)
+	}
+
+	acctPvt, err := getAccountPrivate(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	// keys sealed to a previous key are cleared so they are sealed again to the new one
+	if acctPvt.EncryptionKey != transientInput.PublicKey {
+		for _, held := range acctPvt.Assets {
+			for license, lease := range held {
+				lease.SealedKey = ""
+				held[license] = lease
+			}
+		}
+	}
+
+	acctPvt.EncryptionKey = transientInput.PublicKey
+
+	return putAccountPrivate(ctx, account, "RegisterEncryptionKey", acctPvt)
+}
+
+func (b *BlossomSmartContract) SealLicenseKeys(ctx contractapi.TransactionContextInterface, account string) error {
+	// ngac check
+	if err := decider.CanSealLicenseKeys(ctx, account); err != nil {
+		return fmt.Errorf("ngac check failed: %w", err)
+	}
+
+	acctPvt, err := getAccountPrivate(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	assets := make([]string, 0, len(acctPvt.Assets))
+	for assetID := range acctPvt.Assets {
+		assets = append(assets, assetID)
+	}
+	sort.Strings(assets)
+
+	for _, assetID := range assets {
+		_, assetPvt, err := getAsset(ctx, assetID)
+		if err != nil {
+			return fmt.Errorf("error getting asset %s: %w", assetID, err)
+		}
+
+		if err = sealLicenseKeys(ctx, acctPvt, assetID, assetPvt); err != nil {
+			return fmt.Errorf("error sealing keys of asset %s: %w", assetID, err)
+		}
+	}
+
+	return putAccountPrivate(ctx, account, "SealLicenseKeys", acctPvt)
+}
+
+// sealLicenseKeys seals the keys of the account's licenses of the asset that have a key but no sealed key to the
+// account's encryption key.  Nothing is sealed if the account has not registered an encryption key.  The sealing
+// randomness is derived from the transaction ID and the license key so every endorsing peer writes the same
+// ciphertext, and only holders of the license key can predict it.
+func sealLicenseKeys(ctx contractapi.TransactionContextInterface, acctPvt *model.AccountPrivate, assetID string,
+	assetPvt *model.AssetPrivate) error {
+	held := acctPvt.Assets[assetID]
+	if acctPvt.EncryptionKey == "" || len(held) == 0 || len(assetPvt.Keys) == 0 {
+		return nil
+	}
+
+	pub, err := seal.ParsePublicKey(acctPvt.EncryptionKey)
+	if err != nil {
+		return fmt.Errorf("error parsing encryption key: %w", err)
+	}
+
+	licenses := make([]string, 0)
+	for license, lease := range held {
+		if _, ok := assetPvt.Keys[license]; ok && lease.SealedKey == "" {
+			licenses = append(licenses, license)
+		}
+	}
+	sort.Strings(licenses)
+
+	txID := ctx.GetStub().GetTxID()
+	for _, license := range licenses {
+		key := assetPvt.Keys[license]
+		random := seal.NewDeterministicReader([]byte(txID), []byte(assetID), []byte(license), []byte(key))
+
+		lease := held[license]
+		if lease.SealedKey, err = seal.Seal(pub, []byte(key), []byte(license), random); err != nil {
+			return fmt.Errorf("error sealing key of license %s: %w", license, err)
+		}
+
+		held[license] = lease
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"github.com/stretchr/testify/require"
+	"github.com/usnistgov/blossom/chaincode/collections"
+	"github.com/usnistgov/blossom/chaincode/mocks"
+	"github.com/usnistgov/blossom/chaincode/model"
+	"github.com/usnistgov/blossom/chaincode/seal"
+	"testing"
+	"time"
+)
+
+func TestRegisterEncryptionKey(t *testing.T) {
+	ctx := newTestStub(t)
+	bcc := BlossomSmartContract{}
+
+	priv, err := rsa.GenerateKey(rand.Reader, seal.MinKeyBits)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	require.NoError(t, err)
+	pubPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+	err = ctx.SetTransient("asset", onboardAssetTransientInput{Licenses: []model.License{
+		{LicenseID: "1", Expiration: testLicenseExpiration, Key: "KEY-1"},
+		{LicenseID: "2", Expiration: testLicenseExpiration},
+	}})
+	require.NoError(t, err)
+	err = bcc.OnboardAsset(ctx, "123", "asset", testOnboardingDate.Format(time.RFC3339), testAssetExpiration.Format(time.RFC3339))
+	require.NoError(t, err)
+
+	requestTestAccount(t, ctx, Org2MSP)
+
+	t.Run("test invalid key", func(t *testing.T) {
+		err = ctx.SetClientIdentity(mocks.Org2SystemOwner)
+		require.NoError(t, err)
+		err = ctx.SetTransient("encryption_key", registerEncryptionKeyTransientInput{PublicKey: "not a key"})
+		require.NoError(t, err)
+		err = bcc.RegisterEncryptionKey(ctx)
+		require.Error(t, err)
+	})
+
+	t.Run("test unauthorized", func(t *testing.T) {
+		err = ctx.SetClientIdentity(mocks.Org2SystemAdmin)
+		require.NoError(t, err)
+		err = ctx.SetTransient("encryption_key", registerEncryptionKeyTransientInput{PublicKey: pubPEM})
+		require.NoError(t, err)
+		err = bcc.RegisterEncryptionKey(ctx)
+		require.Error(t, err)
+	})
+
+	t.Run("test register and seal", func(t *testing.T) {
+		err = ctx.SetClientIdentity(mocks.Org2SystemOwner)
+		require.NoError(t, err)
+		err = ctx.SetTransient("encryption_key", registerEncryptionKeyTransientInput{PublicKey: pubPEM})
+		require.NoError(t, err)
+		err = bcc.RegisterEncryptionKey(ctx)
+		require.NoError(t, err)
+
+		checkoutTestAsset(t, ctx, Org2MSP, "123", 2)
+
+		err = ctx.SetClientIdentity(mocks.Org2SystemOwner)
+		require.NoError(t, err)
+		licenses, err := bcc.GetLicenses(ctx, Org2MSP, "123")
+		require.NoError(t, err)
+		require.Len(t, licenses, 2)
+		require.Empty(t, licenses["2"].SealedKey)
+
+		sealed := licenses["1"].SealedKey
+		require.NotEmpty(t, sealed)
+		require.NotContains(t, sealed, "KEY-1")
+
+		key, err := seal.Open(priv, sealed, []byte("1"))
+		require.NoError(t, err)
+		require.Equal(t, "KEY-1", string(key))
+
+		// the sealed key is bound to its license
+		_, err = seal.Open(priv, sealed, []byte("2"))
+		require.Error(t, err)
+
+		// the asset's copy of the lease does not carry the sealed key
+		err = ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		data, err := ctx.GetStub().GetPrivateData(collections.Licenses(), model.AssetKey("123"))
+		require.NoError(t, err)
+		assetPvt := model.AssetPrivate{}
+		err = json.Unmarshal(data, &assetPvt)
+		require.NoError(t, err)
+		require.Empty(t, assetPvt.CheckedOut[Org2MSP]["1"].SealedKey)
+		require.Equal(t, "KEY-1", assetPvt.Keys["1"])
+	})
+
+	t.Run("test rotate and reseal held licenses", func(t *testing.T) {
+		newPriv, err := rsa.GenerateKey(rand.Reader, seal.MinKeyBits)
+		require.NoError(t, err)
+		der, err := x509.MarshalPKIXPublicKey(&newPriv.PublicKey)
+		require.NoError(t, err)
+		newPubPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+		err = ctx.SetClientIdentity(mocks.Org2SystemOwner)
+		require.NoError(t, err)
+		err = ctx.SetTransient("encryption_key", registerEncryptionKeyTransientInput{PublicKey: newPubPEM})
+		require.NoError(t, err)
+		err = bcc.RegisterEncryptionKey(ctx)
+		require.NoError(t, err)
+
+		// the key sealed to the previous key is cleared
+		licenses, err := bcc.GetLicenses(ctx, Org2MSP, "123")
+		require.NoError(t, err)
+		require.Empty(t, licenses["1"].SealedKey)
+
+		// only the admin can read the license keys to seal them again
+		err = bcc.SealLicenseKeys(ctx, Org2MSP)
+		require.Error(t, err)
+
+		err = ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		err = bcc.SealLicenseKeys(ctx, Org2MSP)
+		require.NoError(t, err)
+
+		err = ctx.SetClientIdentity(mocks.Org2SystemOwner)
+		require.NoError(t, err)
+		licenses, err = bcc.GetLicenses(ctx, Org2MSP, "123")
+		require.NoError(t, err)
+		require.Empty(t, licenses["2"].SealedKey)
+
+		key, err := seal.Open(newPriv, licenses["1"].SealedKey, []byte("1"))
+		require.NoError(t, err)
+		require.Equal(t, "KEY-1", string(key))
+
+		_, err = seal.Open(priv, licenses["1"].SealedKey, []byte("1"))
+		require.Error(t, err)
+	})
+}
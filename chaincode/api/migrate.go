@@ -0,0 +1,375 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+	"github.com/usnistgov/blossom/chaincode/collections"
+	"github.com/usnistgov/blossom/chaincode/model"
+	decider "github.com/usnistgov/blossom/chaincode/ngac/pdp"
+	"sort"
+	"strings"
+	"time"
+)
+
+func (b *BlossomSmartContract) MigrateDates(ctx contractapi.TransactionContextInterface) (*model.DateMigration, error) {
+	// ngac check
+	if err := decider.CanMigrateDates(ctx); err != nil {
+		return nil, fmt.Errorf("error migrating dates: %w", err)
+	}
+
+	timestamp, err := txTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	migration := &model.DateMigration{
+		Assets:   make([]string, 0),
+		Accounts: make([]string, 0),
+		Skipped:  make(map[string]string),
+	}
+
+	// legacy assets cannot be unmarshaled into the asset model so the IDs are read from the catalog keys
+	assetIDs, err := catalogAssetIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// the migrated licenses of each asset are the source of the expirations of the licenses held by accounts
+	migrated := make(map[string]*model.AssetPrivate)
+	for _, assetID := range assetIDs {
+		assetPub, assetPvt, changed, err := migrateAssetDates(ctx, assetID, timestamp)
+		if err != nil {
+			migration.Skipped[model.AssetKey(assetID)] = err.Error()
+			continue
+		}
+
+		migrated[assetID] = assetPvt
+		if !changed {
+			continue
+		}
+
+		if err = putAsset(ctx, "MigrateDates", assetPub, assetPvt); err != nil {
+			return nil, fmt.Errorf("error updating asset %s: %w", assetID, err)
+		}
+
+		migration.Assets = append(migration.Assets, assetID)
+	}
+
+	accounts, err := b.GetAccounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting accounts: %w", err)
+	}
+
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].Name < accounts[j].Name
+	})
+
+	for _, acctPub := range accounts {
+		acctPvt, changed, err := migrateAccountDates(ctx, acctPub.Name, migrated)
+		if err != nil {
+			migration.Skipped[model.AccountKey(acctPub.Name)] = err.Error()
+			continue
+		} else if !changed {
+			continue
+		}
+
+		if err = putAccountPrivate(ctx, acctPub.Name, "MigrateDates", acctPvt); err != nil {
+			return nil, err
+		}
+
+		migration.Accounts = append(migration.Accounts, acctPub.Name)
+	}
+
+	return migration, nil
+}
+
+// catalogAssetIDs returns the sorted IDs of the assets in the catalog.
+func catalogAssetIDs(ctx contractapi.TransactionContextInterface) ([]string, error) {
+	iter, err := ctx.GetStub().GetPrivateDataByRange(collections.Catalog(), "", "")
+	if err != nil {
+		return nil, fmt.Errorf("error getting assets: %w", err)
+	}
+	defer iter.Close()
+
+	ids := make([]string, 0)
+	for iter.HasNext() {
+		var next *queryresult.KV
+		if next, err = iter.Next(); err != nil {
+			return nil, fmt.Errorf("error getting next KV: %w", err)
+		}
+
+		if !strings.HasPrefix(next.Key, model.AssetPrefix) {
+			continue
+		}
+
+		ids = append(ids, strings.TrimPrefix(next.Key, model.AssetPrefix))
+	}
+
+	sort.Strings(ids)
+
+	return ids, nil
+}
+
+// migrateAssetDates normalizes the dates of an asset to RFC 3339.  An onboarding date that cannot be parsed is set to
+// the given time, and an expiration that cannot be parsed is set to the latest license expiration.  A license
+// expiration that cannot be parsed or is after the asset expiration is set to the asset expiration.  The migrated asset
+// is returned along with whether it differs from the stored asset.
+func migrateAssetDates(ctx contractapi.TransactionContextInterface, assetID string, now time.Time) (*model.AssetPublic, *model.AssetPrivate, bool, error) {
+	pubBytes, err := ctx.GetStub().GetPrivateData(collections.Catalog(), model.AssetKey(assetID))
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("error getting asset public info: %w", err)
+	}
+
+	pvtBytes, err := ctx.GetStub().GetPrivateData(collections.Licenses(), model.AssetKey(assetID))
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("error getting asset private info: %w", err)
+	}
+
+	pubRaw := make(map[string]json.RawMessage)
+	if err = json.Unmarshal(pubBytes, &pubRaw); err != nil {
+		return nil, nil, false, fmt.Errorf("error unmarshaling asset public info: %w", err)
+	}
+
+	pvtRaw := make(map[string]json.RawMessage)
+	if err = json.Unmarshal(pvtBytes, &pvtRaw); err != nil {
+		return nil, nil, false, fmt.Errorf("error unmarshaling asset private info: %w", err)
+	}
+
+	var (
+		legacyLicenses   map[string]string
+		legacyCheckedOut map[string]map[string]json.RawMessage
+	)
+
+	if err = unmarshalLegacyField(pvtRaw, "licenses", &legacyLicenses); err != nil {
+		return nil, nil, false, err
+	}
+
+	if err = unmarshalLegacyField(pvtRaw, "checked_out", &legacyCheckedOut); err != nil {
+		return nil, nil, false, err
+	}
+
+	licenses := make(map[string]time.Time)
+	latest := time.Time{}
+	for license, exp := range legacyLicenses {
+		if t, err := model.ParseLegacyDate(exp); err == nil {
+			licenses[license] = t
+			if t.After(latest) {
+				latest = t
+			}
+		}
+	}
+
+	onboardingDate, err := legacyDate(pubRaw, "onboarding_date")
+	if err != nil {
+		onboardingDate = now
+	}
+
+	expiration, err := legacyDate(pubRaw, "expiration")
+	if err != nil {
+		if latest.IsZero() {
+			return nil, nil, false, fmt.Errorf("expiration cannot be determined: %w", err)
+		}
+
+		expiration = latest
+	}
+
+	for license := range legacyLicenses {
+		if exp, ok := licenses[license]; !ok || exp.After(expiration) {
+			licenses[license] = expiration
+		}
+	}
+
+	checkedOut := make(map[string]map[string]model.LicenseLease)
+	for account, held := range legacyCheckedOut {
+		checkedOut[account] = make(map[string]model.LicenseLease)
+		for license, legacyLease := range held {
+			exp, ok := licenses[license]
+			if !ok {
+				exp = expiration
+			}
+
+			checkedOut[account][license] = migrateLease(legacyLease, exp)
+		}
+	}
+
+	if err = marshalMigratedField(pubRaw, "onboarding_date", onboardingDate); err != nil {
+		return nil, nil, false, err
+	}
+
+	if err = marshalMigratedField(pubRaw, "expiration", expiration); err != nil {
+		return nil, nil, false, err
+	}
+
+	if err = marshalMigratedField(pvtRaw, "licenses", licenses); err != nil {
+		return nil, nil, false, err
+	}
+
+	if err = marshalMigratedField(pvtRaw, "checked_out", checkedOut); err != nil {
+		return nil, nil, false, err
+	}
+
+	assetPub := model.NewAssetPublic()
+	if err = remarshal(pubRaw, assetPub); err != nil {
+		return nil, nil, false, fmt.Errorf("error migrating asset public info: %w", err)
+	}
+
+	if err = assetPub.ValidateDates(); err != nil {
+		return nil, nil, false, err
+	}
+
+	assetPvt := model.NewAssetPrivate()
+	if err = remarshal(pvtRaw, assetPvt); err != nil {
+		return nil, nil, false, fmt.Errorf("error migrating asset private info: %w", err)
+	}
+
+	changed, err := differs(pubBytes, assetPub)
+	if err != nil || changed {
+		return assetPub, assetPvt, changed, err
+	}
+
+	changed, err = differs(pvtBytes, assetPvt)
+
+	return assetPub, assetPvt, changed, err
+}
+
+// migrateAccountDates normalizes the expirations of the licenses held by an account to RFC 3339.  The expiration of a
+// license of a migrated asset is taken from the asset.  The migrated private info is returned along with whether it
+// differs from the stored private info.  An account without private info is not changed.
+func migrateAccountDates(ctx contractapi.TransactionContextInterface, accountName string, migrated map[string]*model.AssetPrivate) (*model.AccountPrivate, bool, error) {
+	bytes, err := ctx.GetStub().GetPrivateData(collections.Account(accountName), model.AccountKey(accountName))
+	if err != nil {
+		return nil, false, fmt.Errorf("error getting private info: %w", err)
+	} else if bytes == nil {
+		return nil, false, nil
+	}
+
+	raw := make(map[string]json.RawMessage)
+	if err = json.Unmarshal(bytes, &raw); err != nil {
+		return nil, false, fmt.Errorf("error unmarshaling private info: %w", err)
+	}
+
+	var legacyAssets map[string]map[string]json.RawMessage
+	if err = unmarshalLegacyField(raw, "assets", &legacyAssets); err != nil {
+		return nil, false, err
+	}
+
+	assets := make(map[string]map[string]model.LicenseLease)
+	for assetID, held := range legacyAssets {
+		assets[assetID] = make(map[string]model.LicenseLease)
+		for license, legacyLease := range held {
+			if assetPvt, ok := migrated[assetID]; ok {
+				if lease, ok := assetPvt.CheckedOut[accountName][license]; ok {
+					assets[assetID][license] = lease
+					continue
+				}
+			}
+
+			var legacyExp string
+			if err = json.Unmarshal(legacyLease, &legacyExp); err != nil {
+				// the license has a lease and its dates are already valid
+				lease := model.LicenseLease{}
+				if err = json.Unmarshal(legacyLease, &lease); err != nil {
+					return nil, false, fmt.Errorf("error unmarshaling lease of license %s of asset %s: %w", license, assetID, err)
+				}
+
+				assets[assetID][license] = lease
+				continue
+			}
+
+			exp, err := model.ParseLegacyDate(legacyExp)
+			if err != nil {
+				return nil, false, fmt.Errorf("expiration of license %s of asset %s cannot be determined: %w", license, assetID, err)
+			}
+
+			assets[assetID][license] = model.LicenseLease{Expiration: exp, LeaseEnd: exp}
+		}
+	}
+
+	if err = marshalMigratedField(raw, "assets", assets); err != nil {
+		return nil, false, err
+	}
+
+	acctPvt := model.NewAccountPrivate()
+	if err = remarshal(raw, acctPvt); err != nil {
+		return nil, false, fmt.Errorf("error migrating private info: %w", err)
+	}
+
+	changed, err := differs(bytes, acctPvt)
+
+	return acctPvt, changed, err
+}
+
+// migrateLease returns the lease of a checked out license with the given expiration.  The lease end of a lease stored
+// before leases were recorded, or that is after the expiration, is set to the expiration.
+func migrateLease(legacyLease json.RawMessage, expiration time.Time) model.LicenseLease {
+	lease := model.LicenseLease{}
+	if err := json.Unmarshal(legacyLease, &lease); err != nil {
+		// legacy expirations that are not RFC 3339 dates
+		lease = model.LicenseLease{}
+	}
+
+	lease.Expiration = expiration
+	if lease.LeaseEnd.IsZero() || lease.LeaseEnd.After(expiration) {
+		lease.LeaseEnd = expiration
+	}
+
+	return lease
+}
+
+// legacyDate parses the legacy date stored in the field of a raw record.
+func legacyDate(raw map[string]json.RawMessage, field string) (time.Time, error) {
+	var date string
+	if err := unmarshalLegacyField(raw, field, &date); err != nil {
+		return time.Time{}, err
+	}
+
+	return model.ParseLegacyDate(date)
+}
+
+// unmarshalLegacyField unmarshals the field of a raw record.  A missing field leaves the value unchanged.
+func unmarshalLegacyField(raw map[string]json.RawMessage, field string, v interface{}) error {
+	value, ok := raw[field]
+	if !ok {
+		return nil
+	}
+
+	if err := json.Unmarshal(value, v); err != nil {
+		return fmt.Errorf("error unmarshaling %s: %w", field, err)
+	}
+
+	return nil
+}
+
+// marshalMigratedField replaces the field of a raw record with the migrated value.
+func marshalMigratedField(raw map[string]json.RawMessage, field string, v interface{}) error {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("error marshaling %s: %w", field, err)
+	}
+
+	raw[field] = value
+
+	return nil
+}
+
+// remarshal converts a raw record into the given model.
+func remarshal(raw map[string]json.RawMessage, v interface{}) error {
+	bytes, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(bytes, v)
+}
+
+// differs returns true if the stored bytes differ from the marshaled model.
+func differs(stored []byte, v interface{}) (bool, error) {
+	bytes, err := json.Marshal(v)
+	if err != nil {
+		return false, err
+	}
+
+	return string(bytes) != string(stored), nil
+}
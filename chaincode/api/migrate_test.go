@@ -0,0 +1,89 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"github.com/usnistgov/blossom/chaincode/collections"
+	"github.com/usnistgov/blossom/chaincode/mocks"
+	"github.com/usnistgov/blossom/chaincode/model"
+	"testing"
+	"time"
+)
+
+func TestMigrateDates(t *testing.T) {
+	ctx := newTestStub(t)
+	bcc := BlossomSmartContract{}
+
+	onboardTestAsset(t, ctx, "123", "myasset1", []string{"1", "2"})
+	onboardTestAsset(t, ctx, "456", "myasset2", []string{"1"})
+	requestTestAccount(t, ctx, Org2MSP)
+
+	// overwrite the records with the free form dates stored before dates were validated
+	err := ctx.SetClientIdentity(mocks.Super)
+	require.NoError(t, err)
+
+	putLegacy := func(collection, key, value string) {
+		err := ctx.GetStub().PutPrivateData(collection, key, []byte(value))
+		require.NoError(t, err)
+	}
+
+	putLegacy(collections.Catalog(), model.AssetKey("123"),
+		`{"id":"123","name":"myasset1","available":1,"onboarding_date":"01/01/2020","expiration":"2100-01-01"}`)
+	putLegacy(collections.Licenses(), model.AssetKey("123"),
+		`{"total_amount":2,"licenses":{"1":"exp","2":"2099-01-01"},"available_licenses":["2"],"checked_out":{"Org2MSP":{"1":"exp"}}}`)
+	putLegacy(collections.Catalog(), model.AssetKey("456"),
+		`{"id":"456","name":"myasset2","available":1,"onboarding_date":"onboard-date","expiration":"expiration-date"}`)
+	putLegacy(collections.Licenses(), model.AssetKey("456"),
+		`{"total_amount":1,"licenses":{"1":"exp"},"available_licenses":["1"],"checked_out":{}}`)
+
+	acctPvt := make(map[string]interface{})
+	bytes, err := ctx.GetStub().GetPrivateData(collections.Account(Org2MSP), model.AccountKey(Org2MSP))
+	require.NoError(t, err)
+	err = json.Unmarshal(bytes, &acctPvt)
+	require.NoError(t, err)
+	acctPvt["assets"] = map[string]map[string]string{"123": {"1": "exp"}}
+	bytes, err = json.Marshal(acctPvt)
+	require.NoError(t, err)
+	putLegacy(collections.Account(Org2MSP), model.AccountKey(Org2MSP), string(bytes))
+
+	t.Run("test only admin can migrate", func(t *testing.T) {
+		err = ctx.SetClientIdentity(mocks.Org2SystemOwner)
+		require.NoError(t, err)
+		_, err = bcc.MigrateDates(ctx)
+		require.Error(t, err)
+	})
+
+	err = ctx.SetClientIdentity(mocks.Super)
+	require.NoError(t, err)
+
+	migration, err := bcc.MigrateDates(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"123"}, migration.Assets)
+	require.Equal(t, []string{Org2MSP}, migration.Accounts)
+	require.Contains(t, migration.Skipped, model.AssetKey("456"))
+
+	expiration := time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	asset, err := bcc.GetAsset(ctx, "123")
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), asset.OnboardingDate)
+	require.Equal(t, expiration, asset.Expiration)
+	require.Equal(t, map[string]time.Time{"1": expiration, "2": time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC)}, asset.Licenses)
+	lease := model.LicenseLease{Expiration: expiration, LeaseEnd: expiration}
+	require.Equal(t, map[string]map[string]model.LicenseLease{Org2MSP: {"1": lease}}, asset.CheckedOut)
+
+	licenses, err := bcc.GetLicenses(ctx, Org2MSP, "123")
+	require.NoError(t, err)
+	require.Equal(t, map[string]model.LicenseLease{"1": lease}, licenses)
+
+	t.Run("test migration can be run again", func(t *testing.T) {
+		err = ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+
+		migration, err = bcc.MigrateDates(ctx)
+		require.NoError(t, err)
+		require.Empty(t, migration.Assets)
+		require.Empty(t, migration.Accounts)
+		require.Contains(t, migration.Skipped, model.AssetKey("456"))
+	})
+}
@@ -0,0 +1,369 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+	"github.com/usnistgov/blossom/chaincode/collections"
+	"github.com/usnistgov/blossom/chaincode/model"
+	decider "github.com/usnistgov/blossom/chaincode/ngac/pdp"
+)
+
+func NewMarketplaceContract() MarketplaceInterface {
+	return &BlossomSmartContract{}
+}
+
+func (b *BlossomSmartContract) PostOffer(ctx contractapi.TransactionContextInterface) error {
+	transientInput, err := getPostOfferTransientInput(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting transient input: %w", err)
+	}
+
+	account, err := accountName(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting MSPID from stub: %w", err)
+	}
+
+	// ngac check
+	if err = decider.CanPostOffer(ctx, account); err != nil {
+		return fmt.Errorf("ngac check failed: %w", err)
+	}
+
+	now, err := txTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !transientInput.AvailableUntil.After(now) {
+		return fmt.Errorf("available until date %s must be in the future", transientInput.AvailableUntil.Format(time.RFC3339))
+	}
+
+	acctPvt, err := getAccountPrivate(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	// check that the licenses are leased to the account and are not already being returned, transferred or offered
+	unavailable, err := licensesPendingReturn(ctx, account, transientInput.AssetID)
+	if err != nil {
+		return err
+	}
+
+	checkedOut := acctPvt.Assets[transientInput.AssetID]
+	for _, license := range transientInput.Licenses {
+		if _, ok := checkedOut[license]; !ok {
+			return fmt.Errorf("license %s was not checked out by %s", license, account)
+		} else if _, ok = unavailable[license]; ok {
+			return fmt.Errorf("license %s is already being checked in, transferred or offered by %s", license, account)
+		}
+	}
+
+	txID := ctx.GetStub().GetTxID()
+
+	bytes, err := json.Marshal(model.OfferLicenses{
+		Asset:    transientInput.AssetID,
+		Licenses: transientInput.Licenses,
+	})
+	if err != nil {
+		return fmt.Errorf("error marshaling offer licenses: %w", err)
+	}
+
+	if err = ctx.GetStub().PutPrivateData(collections.Account(account), offerLicensesKey(account, txID), bytes); err != nil {
+		return fmt.Errorf("error storing offer licenses: %w", err)
+	}
+
+	offer := &model.Offer{
+		ID:             txID,
+		Asset:          transientInput.AssetID,
+		Account:        account,
+		Amount:         len(transientInput.Licenses),
+		AvailableUntil: transientInput.AvailableUntil,
+		History:        make([]model.Update, 0),
+	}
+
+	return putOffer(ctx, offer, model.OfferOpen, "", "")
+}
+
+// offerLicensesKey returns the key of the licenses of an offer in the offering account's private data collection.
+func offerLicensesKey(account, offerID string) string {
+	return fmt.Sprintf("offer=%s:%s", account, offerID)
+}
+
+func (b *BlossomSmartContract) GetOffers(ctx contractapi.TransactionContextInterface) ([]*model.Offer, error) {
+	// ngac check
+	if err := decider.CanViewOffers(ctx); err != nil {
+		return nil, fmt.Errorf("ngac check failed: %w", err)
+	}
+
+	now, err := txTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	offers, err := getOffers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claimable := make([]*model.Offer, 0)
+	for _, offer := range offers {
+		if offer.IsClaimable(now) {
+			claimable = append(claimable, offer)
+		}
+	}
+
+	return claimable, nil
+}
+
+func (b *BlossomSmartContract) GetOffer(ctx contractapi.TransactionContextInterface, offerID string) (*model.Offer, error) {
+	// ngac check
+	if err := decider.CanViewOffers(ctx); err != nil {
+		return nil, fmt.Errorf("ngac check failed: %w", err)
+	}
+
+	return getOffer(ctx, offerID)
+}
+
+func (b *BlossomSmartContract) ClaimOffer(ctx contractapi.TransactionContextInterface, offerID string, leaseTermDays int) error {
+	if err := model.ValidateLeaseTerm(leaseTermDays); err != nil {
+		return err
+	}
+
+	account, err := accountName(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting MSPID from stub: %w", err)
+	}
+
+	// ngac check
+	if err = decider.CanClaimOffer(ctx, account); err != nil {
+		return fmt.Errorf("ngac check failed: %w", err)
+	}
+
+	offer, err := getOffer(ctx, offerID)
+	if err != nil {
+		return err
+	}
+
+	now, err := txTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+
+	if offer.Account == account {
+		return fmt.Errorf("account %s cannot claim its own offer", account)
+	} else if !offer.IsClaimable(now) {
+		return fmt.Errorf("offer %s is not available to claim", offerID)
+	}
+
+	offer.ClaimedBy = account
+	offer.LeaseTermDays = leaseTermDays
+
+	return putOffer(ctx, offer, model.OfferClaimed, account, "")
+}
+
+func (b *BlossomSmartContract) ProcessOfferClaim(ctx contractapi.TransactionContextInterface, offerID string) error {
+	// ngac check
+	if err := decider.CanProcessOfferClaim(ctx); err != nil {
+		return fmt.Errorf("ngac check failed: %w", err)
+	}
+
+	offer, err := getOffer(ctx, offerID)
+	if err != nil {
+		return err
+	}
+
+	if offer.Status != model.OfferClaimed {
+		return fmt.Errorf("offer %s cannot be processed with status %s", offerID, offer.Status)
+	}
+
+	licenses, err := getOfferLicenses(ctx, offer)
+	if err != nil {
+		return err
+	}
+
+	if err = checkCanReceiveLicenses(ctx, offer.ClaimedBy); err != nil {
+		return err
+	}
+
+	claimantPvt, err := getAccountPrivate(ctx, offer.ClaimedBy)
+	if err != nil {
+		return err
+	}
+
+	acctPub, acctPvt, assetPub, assetPvt, err := getAcctAndAsset(ctx, offer.Account, offer.Asset)
+	if err != nil {
+		return err
+	}
+
+	now, err := txTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+
+	// the offered licenses are returned to the pool by the offering account and then checked out by the claiming
+	// account with a new lease
+	if err = checkin(assetPub, assetPvt, acctPub, acctPvt, licenses.Licenses, now); err != nil {
+		return fmt.Errorf("error checking in %s for account %s: %w", offer.Asset, offer.Account, err)
+	}
+
+	if err = assignLicenses(assetPub, assetPvt, offer.ClaimedBy, claimantPvt, licenses.Licenses, now, offer.LeaseTermDays); err != nil {
+		return fmt.Errorf("error checking out %s for account %s: %w", offer.Asset, offer.ClaimedBy, err)
+	}
+
+	if err = sealLicenseKeys(ctx, claimantPvt, offer.Asset, assetPvt); err != nil {
+		return err
+	}
+
+	if err = putAccountPrivate(ctx, offer.ClaimedBy, "ProcessOfferClaim", claimantPvt); err != nil {
+		return err
+	}
+
+	if err = putAcctAndAsset(ctx, "ProcessOfferClaim", acctPub, acctPvt, assetPub, assetPvt); err != nil {
+		return fmt.Errorf("error updating account %s and asset %s: %w", offer.Account, offer.Asset, err)
+	}
+
+	return putOffer(ctx, offer, model.OfferCompleted, offer.ClaimedBy, "")
+}
+
+func (b *BlossomSmartContract) WithdrawOffer(ctx contractapi.TransactionContextInterface, offerID, reason string) error {
+	if reason == "" {
+		return fmt.Errorf("a reason is required to withdraw an offer")
+	}
+
+	offer, err := getOffer(ctx, offerID)
+	if err != nil {
+		return err
+	}
+
+	account, err := accountName(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting MSPID from stub: %w", err)
+	}
+
+	// ngac check, the offering account can withdraw its offer and the admin can take it down
+	if account == offer.Account {
+		err = decider.CanPostOffer(ctx, account)
+	} else {
+		err = decider.CanProcessOfferClaim(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("ngac check failed: %w", err)
+	}
+
+	if !offer.IsOpen() {
+		return fmt.Errorf("offer %s cannot be withdrawn with status %s", offerID, offer.Status)
+	}
+
+	return putOffer(ctx, offer, model.OfferWithdrawn, "", reason)
+}
+
+// getOffers returns every offer on the marketplace ordered by when it was posted.
+func getOffers(ctx contractapi.TransactionContextInterface) ([]*model.Offer, error) {
+	iter, err := ctx.GetStub().GetStateByRange(model.OfferKeyRange())
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	offers := make([]*model.Offer, 0)
+	for iter.HasNext() {
+		var next *queryresult.KV
+		if next, err = iter.Next(); err != nil {
+			return nil, fmt.Errorf("error getting next KV: %w", err)
+		}
+
+		if !strings.HasPrefix(next.Key, model.OfferPrefix) {
+			continue
+		}
+
+		offer := &model.Offer{}
+		if err = json.Unmarshal(next.Value, offer); err != nil {
+			return nil, fmt.Errorf("error unmarshaling offer: %w", err)
+		}
+
+		offers = append(offers, offer)
+	}
+
+	sort.Slice(offers, func(i, j int) bool {
+		ti, tj := offers[i].History[0].Timestamp, offers[j].History[0].Timestamp
+		if ti.Equal(tj) {
+			return offers[i].ID < offers[j].ID
+		}
+
+		return ti.Before(tj)
+	})
+
+	return offers, nil
+}
+
+// openOffers returns the open offers the account posted or claimed.
+func openOffers(ctx contractapi.TransactionContextInterface, account string) ([]*model.Offer, error) {
+	offers, err := getOffers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting offers of account %q: %w", account, err)
+	}
+
+	open := make([]*model.Offer, 0)
+	for _, offer := range offers {
+		if offer.IsOpen() && (offer.Account == account || offer.ClaimedBy == account) {
+			open = append(open, offer)
+		}
+	}
+
+	return open, nil
+}
+
+func getOffer(ctx contractapi.TransactionContextInterface, offerID string) (*model.Offer, error) {
+	bytes, err := ctx.GetStub().GetState(model.OfferKey(offerID))
+	if err != nil {
+		return nil, fmt.Errorf("error getting offer %s: %w", offerID, err)
+	} else if bytes == nil {
+		return nil, fmt.Errorf("offer %s does not exist", offerID)
+	}
+
+	offer := &model.Offer{}
+	if err = json.Unmarshal(bytes, offer); err != nil {
+		return nil, fmt.Errorf("error unmarshaling offer %s: %w", offerID, err)
+	}
+
+	return offer, nil
+}
+
+// getOfferLicenses returns the licenses of the offer from the offering account's private data collection.
+func getOfferLicenses(ctx contractapi.TransactionContextInterface, offer *model.Offer) (*model.OfferLicenses, error) {
+	bytes, err := ctx.GetStub().GetPrivateData(collections.Account(offer.Account), offerLicensesKey(offer.Account, offer.ID))
+	if err != nil {
+		return nil, fmt.Errorf("error getting licenses of offer %s: %w", offer.ID, err)
+	} else if bytes == nil {
+		return nil, fmt.Errorf("licenses of offer %s do not exist", offer.ID)
+	}
+
+	licenses := &model.OfferLicenses{}
+	if err = json.Unmarshal(bytes, licenses); err != nil {
+		return nil, fmt.Errorf("error unmarshaling licenses of offer %s: %w", offer.ID, err)
+	}
+
+	return licenses, nil
+}
+
+// putOffer updates the status of the offer, records the update and the account it was made for in the offer's
+// history, and writes the offer to the world state.
+func putOffer(ctx contractapi.TransactionContextInterface, offer *model.Offer, status model.OfferStatus, account, reason string) error {
+	update, err := newUpdate(ctx, string(status), reason)
+	if err != nil {
+		return err
+	}
+	update.Account = account
+
+	offer.Status = status
+	if err = putWithHistory(ctx, "", model.OfferKey(offer.ID), offer, &offer.History, update); err != nil {
+		return fmt.Errorf("error updating offer %s: %w", offer.ID, err)
+	}
+
+	return nil
+}
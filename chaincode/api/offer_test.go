@@ -0,0 +1,174 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/usnistgov/blossom/chaincode/mocks"
+	"github.com/usnistgov/blossom/chaincode/model"
+)
+
+func TestMarketplace(t *testing.T) {
+	ctx := newTestStub(t)
+	bcc := BlossomSmartContract{}
+
+	onboardTestAsset(t, ctx, "123", "myasset", []string{"1", "2", "3"})
+	requestTestAccount(t, ctx, Org2MSP)
+	requestTestAccount(t, ctx, Org3MSP)
+
+	checkoutTestAsset(t, ctx, Org2MSP, "123", 2)
+
+	availableUntil := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	post := func(until time.Time, licenses ...string) error {
+		err := ctx.SetClientIdentity(mocks.Org2SystemAdmin)
+		require.NoError(t, err)
+		err = ctx.SetTransient("offer", postOfferTransientInput{AssetID: "123", Licenses: licenses, AvailableUntil: until})
+		require.NoError(t, err)
+		return bcc.PostOffer(ctx)
+	}
+
+	offers := func() []*model.Offer {
+		err := ctx.SetClientIdentity(mocks.Org3SystemAdmin)
+		require.NoError(t, err)
+		offers, err := bcc.GetOffers(ctx)
+		require.NoError(t, err)
+		return offers
+	}
+
+	claim := func(id string) error {
+		err := ctx.SetClientIdentity(mocks.Org3SystemAdmin)
+		require.NoError(t, err)
+		return bcc.ClaimOffer(ctx, id, 30)
+	}
+
+	var offerID string
+
+	t.Run("test post offer", func(t *testing.T) {
+		err := post(availableUntil, "3")
+		require.Error(t, err)
+
+		err = post(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), "1")
+		require.Error(t, err)
+
+		err = post(availableUntil, "1")
+		require.NoError(t, err)
+
+		// a license cannot be in more than one open offer
+		err = post(availableUntil, "1")
+		require.Error(t, err)
+
+		list := offers()
+		require.Len(t, list, 1)
+		require.Equal(t, Org2MSP, list[0].Account)
+		require.Equal(t, 1, list[0].Amount)
+		require.Equal(t, model.OfferOpen, list[0].Status)
+		offerID = list[0].ID
+
+		// the offered license cannot be transferred
+		err = ctx.SetClientIdentity(mocks.Org2SystemAdmin)
+		require.NoError(t, err)
+		err = ctx.SetTransient("transfer", proposeTransferTransientInput{AssetID: "123", Licenses: []string{"1"}, To: Org3MSP})
+		require.NoError(t, err)
+		err = bcc.ProposeTransfer(ctx)
+		require.Error(t, err)
+	})
+
+	t.Run("test claim offer", func(t *testing.T) {
+		err := ctx.SetClientIdentity(mocks.Org2SystemAdmin)
+		require.NoError(t, err)
+		err = bcc.ClaimOffer(ctx, offerID, 30)
+		require.Error(t, err)
+
+		err = claim(offerID)
+		require.NoError(t, err)
+
+		// a claimed offer is no longer listed and cannot be claimed again
+		require.Len(t, offers(), 0)
+		err = claim(offerID)
+		require.Error(t, err)
+
+		offer, err := bcc.GetOffer(ctx, offerID)
+		require.NoError(t, err)
+		require.Equal(t, model.OfferClaimed, offer.Status)
+		require.Equal(t, Org3MSP, offer.ClaimedBy)
+	})
+
+	t.Run("test process offer claim", func(t *testing.T) {
+		err := ctx.SetClientIdentity(mocks.Org3SystemAdmin)
+		require.NoError(t, err)
+		err = bcc.ProcessOfferClaim(ctx, offerID)
+		require.Error(t, err)
+
+		err = ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		err = bcc.ProcessOfferClaim(ctx, offerID)
+		require.NoError(t, err)
+
+		offer, err := bcc.GetOffer(ctx, offerID)
+		require.NoError(t, err)
+		require.Equal(t, model.OfferCompleted, offer.Status)
+
+		asset, err := bcc.GetAsset(ctx, "123")
+		require.NoError(t, err)
+		require.Equal(t, 1, asset.Available)
+		require.Len(t, asset.CheckedOut[Org2MSP], 1)
+		require.Contains(t, asset.CheckedOut[Org3MSP], "1")
+
+		err = ctx.SetClientIdentity(mocks.Org3SystemAdmin)
+		require.NoError(t, err)
+		licenses, err := bcc.GetLicenses(ctx, Org3MSP, "123")
+		require.NoError(t, err)
+		require.Contains(t, licenses, "1")
+
+		err = bcc.ProcessOfferClaim(ctx, offerID)
+		require.Error(t, err)
+	})
+
+	t.Run("test withdraw offer", func(t *testing.T) {
+		err := post(availableUntil, "2")
+		require.NoError(t, err)
+		id := offers()[0].ID
+
+		err = ctx.SetClientIdentity(mocks.Org3SystemAdmin)
+		require.NoError(t, err)
+		err = bcc.WithdrawOffer(ctx, id, "not mine")
+		require.Error(t, err)
+
+		err = ctx.SetClientIdentity(mocks.Org2SystemAdmin)
+		require.NoError(t, err)
+		err = bcc.WithdrawOffer(ctx, id, "")
+		require.Error(t, err)
+		err = bcc.WithdrawOffer(ctx, id, "needed after all")
+		require.NoError(t, err)
+
+		require.Len(t, offers(), 0)
+	})
+
+	t.Run("test opt out reopens claimed offers", func(t *testing.T) {
+		err := post(availableUntil, "2")
+		require.NoError(t, err)
+		id := offers()[0].ID
+
+		err = claim(id)
+		require.NoError(t, err)
+
+		err = ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		err = bcc.UpdateAccountStatus(ctx, Org3MSP, "UNAUTHORIZED_OPTOUT", "opting out")
+		require.NoError(t, err)
+
+		offer, err := bcc.GetOffer(ctx, id)
+		require.NoError(t, err)
+		require.Equal(t, model.OfferOpen, offer.Status)
+		require.Empty(t, offer.ClaimedBy)
+
+		err = bcc.UpdateAccountStatus(ctx, Org2MSP, "UNAUTHORIZED_OPTOUT", "opting out")
+		require.NoError(t, err)
+
+		offer, err = bcc.GetOffer(ctx, id)
+		require.NoError(t, err)
+		require.Equal(t, model.OfferWithdrawn, offer.Status)
+	})
+}
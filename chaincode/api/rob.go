@@ -0,0 +1,186 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"github.com/usnistgov/blossom/chaincode/collections"
+	"github.com/usnistgov/blossom/chaincode/model"
+	"github.com/usnistgov/blossom/chaincode/ngac/common"
+	decider "github.com/usnistgov/blossom/chaincode/ngac/pdp"
+)
+
+func NewROBContract() ROBInterface {
+	return &BlossomSmartContract{}
+}
+
+func (b *BlossomSmartContract) PublishROB(ctx contractapi.TransactionContextInterface, version, digest, uri string, gracePeriodDays int) error {
+	// ngac check
+	if err := decider.CanPublishROB(ctx); err != nil {
+		return fmt.Errorf("error publishing ROB: %w", err)
+	}
+
+	if version == "" {
+		return fmt.Errorf("ROB version cannot be empty")
+	}
+
+	if err := model.ValidateDigest(digest); err != nil {
+		return fmt.Errorf("invalid ROB digest: %w", err)
+	}
+
+	if gracePeriodDays < 0 {
+		return fmt.Errorf("grace period cannot be negative")
+	}
+
+	// published versions cannot be changed
+	if bytes, err := ctx.GetStub().GetState(model.ROBKey(version)); err != nil {
+		return fmt.Errorf("error checking if ROB version %s exists: %w", version, err)
+	} else if bytes != nil {
+		return fmt.Errorf("ROB version %s has already been published", version)
+	}
+
+	publisher, err := common.GetUser(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting user from ctx: %w", err)
+	}
+
+	timestamp, err := txTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+
+	rob := model.ROB{
+		Version:     version,
+		Digest:      digest,
+		URI:         uri,
+		PublishedAt: timestamp,
+		AcceptBy:    timestamp.Add(time.Duration(gracePeriodDays) * 24 * time.Hour),
+		Publisher:   publisher,
+	}
+
+	bytes, err := json.Marshal(rob)
+	if err != nil {
+		return fmt.Errorf("error marshaling ROB version %s: %w", version, err)
+	}
+
+	if err = ctx.GetStub().PutState(model.ROBKey(version), bytes); err != nil {
+		return fmt.Errorf("error publishing ROB version %s: %w", version, err)
+	}
+
+	// the published version becomes the version accounts must accept
+	if err = ctx.GetStub().PutState(model.CurrentROBKey, bytes); err != nil {
+		return fmt.Errorf("error setting current ROB version to %s: %w", version, err)
+	}
+
+	return nil
+}
+
+func (b *BlossomSmartContract) GetROB(ctx contractapi.TransactionContextInterface) (*model.ROB, error) {
+	bytes, err := ctx.GetStub().GetState(model.CurrentROBKey)
+	if err != nil {
+		return nil, fmt.Errorf("error getting current ROB: %w", err)
+	} else if bytes == nil {
+		return nil, fmt.Errorf("no ROB has been published")
+	}
+
+	rob := &model.ROB{}
+	if err = json.Unmarshal(bytes, rob); err != nil {
+		return nil, fmt.Errorf("error unmarshaling current ROB: %w", err)
+	}
+
+	return rob, nil
+}
+
+func (b *BlossomSmartContract) AcceptROB(ctx contractapi.TransactionContextInterface, version, digest string) error {
+	accountName, err := accountName(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting account name from stub: %w", err)
+	}
+
+	if ok, err := accountExists(ctx, accountName); err != nil {
+		return fmt.Errorf("error checking if account %q exists: %w", accountName, err)
+	} else if !ok {
+		return fmt.Errorf("an account with the name %q does not exist", accountName)
+	}
+
+	// ngac check
+	if err = decider.CanAcceptROB(ctx, accountName); err != nil {
+		return fmt.Errorf("error accepting ROB for account %s: %w", accountName, err)
+	}
+
+	rob, err := b.GetROB(ctx)
+	if err != nil {
+		return err
+	}
+
+	// only the current version can be accepted and the system owner must have accepted the published document
+	if version != rob.Version {
+		return fmt.Errorf("ROB version %s is not the current version %s", version, rob.Version)
+	} else if digest != rob.Digest {
+		return fmt.Errorf("digest %s does not match the digest of ROB version %s", digest, rob.Version)
+	}
+
+	bytes, err := ctx.GetStub().GetPrivateData(collections.Account(accountName), model.AccountKey(accountName))
+	if err != nil {
+		return fmt.Errorf("error getting account %q from private data: %w", accountName, err)
+	} else if bytes == nil {
+		return fmt.Errorf("an account with the name %q does not exist", accountName)
+	}
+
+	acctPvt := model.NewAccountPrivate()
+	if err = json.Unmarshal(bytes, acctPvt); err != nil {
+		return fmt.Errorf("error unmarshaling account %q: %w", accountName, err)
+	}
+
+	user, err := common.GetUser(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting user from ctx: %w", err)
+	}
+
+	timestamp, err := txTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+
+	acctPvt.ROBAcceptance = &model.ROBAcceptance{
+		Version:    rob.Version,
+		Digest:     rob.Digest,
+		AcceptedBy: user,
+		TxID:       ctx.GetStub().GetTxID(),
+		Timestamp:  timestamp,
+	}
+
+	return putAccountPrivate(ctx, accountName, "AcceptROB", acctPvt)
+}
+
+func (b *BlossomSmartContract) EnforceROB(ctx contractapi.TransactionContextInterface) ([]string, error) {
+	// ngac check
+	if err := decider.CanEnforceROB(ctx); err != nil {
+		return nil, fmt.Errorf("error enforcing ROB: %w", err)
+	}
+
+	rob, err := b.GetROB(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp, err := txTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// accounts have until the end of the grace period to accept
+	if timestamp.Before(rob.AcceptBy) {
+		return []string{}, nil
+	}
+
+	return b.sweepAuthorizedAccounts(ctx, model.UnauthorizedROB, func(acctPvt *model.AccountPrivate) string {
+		if acctPvt.ROBAcceptance == nil || acctPvt.ROBAcceptance.Version != rob.Version {
+			return fmt.Sprintf("ROB version %s was not accepted by %s", rob.Version, rob.AcceptBy.Format(time.RFC3339))
+		}
+
+		return ""
+	})
+}
@@ -0,0 +1,160 @@
+package api
+
+import (
+	"github.com/stretchr/testify/require"
+	"github.com/usnistgov/blossom/chaincode/mocks"
+	"github.com/usnistgov/blossom/chaincode/model"
+	"testing"
+	"time"
+)
+
+const testROBDigest = "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"
+
+func TestPublishROB(t *testing.T) {
+	ctx := newTestStub(t)
+	bcc := BlossomSmartContract{}
+
+	t.Run("test only admin can publish", func(t *testing.T) {
+		requestTestAccount(t, ctx, Org2MSP)
+
+		err := ctx.SetClientIdentity(mocks.Org2SystemOwner)
+		require.NoError(t, err)
+		err = bcc.PublishROB(ctx, "1.0", testROBDigest, "", 30)
+		require.Error(t, err)
+	})
+
+	err := ctx.SetClientIdentity(mocks.Super)
+	require.NoError(t, err)
+
+	t.Run("test invalid digest", func(t *testing.T) {
+		err = bcc.PublishROB(ctx, "1.0", "abc", "", 30)
+		require.Error(t, err)
+	})
+
+	err = bcc.PublishROB(ctx, "1.0", testROBDigest, "https://example.gov/rob-1.0.pdf", 30)
+	require.NoError(t, err)
+
+	rob, err := bcc.GetROB(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "1.0", rob.Version)
+	require.Equal(t, testROBDigest, rob.Digest)
+	require.Equal(t, "adminuser:Org1MSP", rob.Publisher)
+	require.Equal(t, rob.PublishedAt.AddDate(0, 0, 30), rob.AcceptBy)
+
+	t.Run("test published version cannot change", func(t *testing.T) {
+		err = bcc.PublishROB(ctx, "1.0", testROBDigest, "", 60)
+		require.Error(t, err)
+	})
+}
+
+func TestAcceptROB(t *testing.T) {
+	ctx := newTestStub(t)
+	bcc := BlossomSmartContract{}
+
+	requestTestAccount(t, ctx, Org2MSP)
+
+	err := ctx.SetClientIdentity(mocks.Org2SystemOwner)
+	require.NoError(t, err)
+
+	t.Run("test no rob published", func(t *testing.T) {
+		err = bcc.AcceptROB(ctx, "1.0", testROBDigest)
+		require.Error(t, err)
+	})
+
+	err = ctx.SetClientIdentity(mocks.Super)
+	require.NoError(t, err)
+	err = bcc.PublishROB(ctx, "1.0", testROBDigest, "", 30)
+	require.NoError(t, err)
+
+	t.Run("test system admin cannot accept", func(t *testing.T) {
+		err = ctx.SetClientIdentity(mocks.Org2SystemAdmin)
+		require.NoError(t, err)
+		err = bcc.AcceptROB(ctx, "1.0", testROBDigest)
+		require.Error(t, err)
+	})
+
+	err = ctx.SetClientIdentity(mocks.Org2SystemOwner)
+	require.NoError(t, err)
+
+	t.Run("test digest must match", func(t *testing.T) {
+		err = bcc.AcceptROB(ctx, "1.0", testATOInput(time.Now()).Digest)
+		require.Error(t, err)
+	})
+
+	err = bcc.AcceptROB(ctx, "1.0", testROBDigest)
+	require.NoError(t, err)
+
+	acct, err := bcc.GetAccount(ctx, Org2MSP)
+	require.NoError(t, err)
+	require.Equal(t, "1.0", acct.ROBAcceptance.Version)
+	require.Equal(t, "org2user1:Org2MSP", acct.ROBAcceptance.AcceptedBy)
+	require.Equal(t, ctx.GetStub().GetTxID(), acct.ROBAcceptance.TxID)
+}
+
+func TestEnforceROB(t *testing.T) {
+	ctx := newTestStub(t)
+	bcc := BlossomSmartContract{}
+
+	requestTestAccount(t, ctx, Org2MSP)
+	requestTestAccount(t, ctx, Org3MSP)
+
+	err := ctx.SetClientIdentity(mocks.Super)
+	require.NoError(t, err)
+	err = bcc.PublishROB(ctx, "1.0", testROBDigest, "", 30)
+	require.NoError(t, err)
+
+	// only Org2 accepts
+	err = ctx.SetClientIdentity(mocks.Org2SystemOwner)
+	require.NoError(t, err)
+	err = bcc.AcceptROB(ctx, "1.0", testROBDigest)
+	require.NoError(t, err)
+
+	err = ctx.SetClientIdentity(mocks.Super)
+	require.NoError(t, err)
+
+	t.Run("test grace period", func(t *testing.T) {
+		swept, err := bcc.EnforceROB(ctx)
+		require.NoError(t, err)
+		require.Empty(t, swept)
+	})
+
+	t.Run("test enforce after grace period", func(t *testing.T) {
+		err = ctx.SetTxTimestamp(time.Now().AddDate(0, 0, 31))
+		require.NoError(t, err)
+
+		swept, err := bcc.EnforceROB(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []string{Org3MSP}, swept)
+
+		acct, err := bcc.GetAccount(ctx, Org3MSP)
+		require.NoError(t, err)
+		require.Equal(t, model.UnauthorizedROB, acct.Status)
+
+		acct, err = bcc.GetAccount(ctx, Org2MSP)
+		require.NoError(t, err)
+		require.Equal(t, model.Authorized, acct.Status)
+	})
+
+	t.Run("test account can accept while unauthorized", func(t *testing.T) {
+		err = ctx.SetClientIdentity(mocks.Org3SystemOwner)
+		require.NoError(t, err)
+		err = bcc.AcceptROB(ctx, "1.0", testROBDigest)
+		require.NoError(t, err)
+
+		err = ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		err = bcc.UpdateAccountStatus(ctx, Org3MSP, "AUTHORIZED", "ROB accepted")
+		require.NoError(t, err)
+	})
+
+	t.Run("test new version must be accepted again", func(t *testing.T) {
+		err = bcc.PublishROB(ctx, "2.0", testATOInput(time.Now()).Digest, "", 0)
+		require.NoError(t, err)
+
+		err = ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		swept, err := bcc.EnforceROB(ctx)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{Org2MSP, Org3MSP}, swept)
+	})
+}
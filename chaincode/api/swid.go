@@ -134,6 +134,11 @@ func (b *BlossomSmartContract) GetSwID(ctx contractapi.TransactionContextInterfa
 }
 
 func (b *BlossomSmartContract) GetSwIDsAssociatedWithAsset(ctx contractapi.TransactionContextInterface, account string, assetID string) ([]*model.SwID, error) {
+	return getSwIDsAssociatedWithAsset(ctx, account, assetID)
+}
+
+// getSwIDsAssociatedWithAsset returns the SwIDs the account has reported for the asset.
+func getSwIDsAssociatedWithAsset(ctx contractapi.TransactionContextInterface, account string, assetID string) ([]*model.SwID, error) {
 	resultsIterator, err := ctx.GetStub().GetPrivateDataByRange(collections.Account(account), "", "")
 
 	if err != nil {
@@ -22,7 +22,7 @@ func TestSwID(t *testing.T) {
 	err = ctx.SetClientIdentity(mocks.Org2SystemAdmin)
 	require.NoError(t, err)
 
-	err = ctx.SetTransient("checkout", requestCheckoutTransientInput{"123", 1})
+	err = ctx.SetTransient("checkout", testCheckoutInput("123", 1))
 	require.NoError(t, err)
 	err = bcc.RequestCheckout(ctx)
 	require.NoError(t, err)
@@ -30,7 +30,7 @@ func TestSwID(t *testing.T) {
 	err = ctx.SetClientIdentity(mocks.Super)
 	require.NoError(t, err)
 
-	err = ctx.SetTransient("checkout", approveCheckoutTransientInput{Org2MSP, "123"})
+	err = ctx.SetTransient("checkout", approveCheckoutTransientInput{Account: Org2MSP, AssetID: "123"})
 	require.NoError(t, err)
 	err = bcc.ApproveCheckout(ctx)
 	require.NoError(t, err)
@@ -7,6 +7,7 @@ import (
 	"github.com/usnistgov/blossom/chaincode/mocks"
 	"github.com/usnistgov/blossom/chaincode/model"
 	"testing"
+	"time"
 )
 
 const Org2MSP = "Org2MSP"
@@ -44,9 +45,28 @@ func requestTestAccount(t *testing.T, ctx *mocks.Ctx, account string) {
 	if account == Org2MSP {
 		err := ctx.SetClientIdentity(mocks.Org2SystemOwner)
 		require.NoError(t, err)
+		err = ctx.SetTransient("account", accountTransientInput{
+			SystemOwner:           "org2user1",
+			SystemAdmin:           "org2user2",
+			AcquisitionSpecialist: "org2user3",
+			accountAgencyTransientInput: accountAgencyTransientInput{
+				Department: "Department of Commerce",
+				SubAgency:  "NIST",
+			},
+		})
+		require.NoError(t, err)
 	} else {
 		err := ctx.SetClientIdentity(mocks.Org3SystemOwner)
 		require.NoError(t, err)
+		err = ctx.SetTransient("account", accountTransientInput{
+			SystemOwner:           "org3user1",
+			SystemAdmin:           "org3user2",
+			AcquisitionSpecialist: "org3user3",
+			accountAgencyTransientInput: accountAgencyTransientInput{
+				Department: "Department of Commerce",
+			},
+		})
+		require.NoError(t, err)
 	}
 	err := bcc.RequestAccount(ctx)
 	require.NoError(t, err)
@@ -61,10 +81,14 @@ func requestTestAccount(t *testing.T, ctx *mocks.Ctx, account string) {
 	require.NoError(t, err)
 	require.Equal(t, model.PendingATO, acct.Status)
 
-	err = ctx.SetClientIdentity(mocks.Org2SystemOwner)
+	if account == Org2MSP {
+		err = ctx.SetClientIdentity(mocks.Org2SystemOwner)
+	} else {
+		err = ctx.SetClientIdentity(mocks.Org3SystemOwner)
+	}
 	require.NoError(t, err)
 
-	err = ctx.SetTransient("ato", uploadATOTransientInput{ATO: "test ato"})
+	err = ctx.SetTransient("ato", testATOInput(time.Now().AddDate(1, 0, 0)))
 	require.NoError(t, err)
 	err = bcc.UploadATO(ctx)
 	require.NoError(t, err)
@@ -74,22 +98,73 @@ func requestTestAccount(t *testing.T, ctx *mocks.Ctx, account string) {
 	err = ctx.SetClientIdentity(mocks.Super)
 	require.NoError(t, err)
 
-	err = bcc.UpdateAccountStatus(ctx, account, "AUTHORIZED")
+	err = bcc.UpdateAccountStatus(ctx, account, "AUTHORIZED", "ato reviewed")
 	require.NoError(t, err)
 }
 
+func testATOInput(expiration time.Time) uploadATOTransientInput {
+	return uploadATOTransientInput{
+		AuthorizingOfficial: "test official",
+		SystemName:          "test system",
+		IssueDate:           expiration.AddDate(-3, 0, 0),
+		ExpirationDate:      expiration,
+		ImpactLevel:         string(model.ModerateImpact),
+		Digest:              "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+	}
+}
+
+var (
+	testOnboardingDate    = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	testAssetExpiration   = time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC)
+	testLicenseExpiration = time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
 func onboardTestAsset(t *testing.T, ctx *mocks.Ctx, id, name string, licenses []string) {
 	licensesMap := make([]model.License, 0)
 	for _, l := range licenses {
 		licensesMap = append(licensesMap, model.License{
 			LicenseID:  l,
-			Expiration: "exp",
+			Expiration: testLicenseExpiration,
 		})
 	}
 
 	bcc := BlossomSmartContract{}
 	err := ctx.SetTransient("asset", onboardAssetTransientInput{Licenses: licensesMap})
 	require.NoError(t, err)
-	err = bcc.OnboardAsset(ctx, id, name, "onboard-date", "expiration-date")
+	err = bcc.OnboardAsset(ctx, id, name, testOnboardingDate.Format(time.RFC3339), testAssetExpiration.Format(time.RFC3339))
+	require.NoError(t, err)
+}
+
+func testCheckoutInput(assetID string, amount int) requestCheckoutTransientInput {
+	return requestCheckoutTransientInput{
+		AssetID:       assetID,
+		Amount:        amount,
+		Justification: "test justification",
+		LeaseTermDays: 90,
+		FISMASystem:   "test system",
+		NeededBy:      time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func checkoutTestAsset(t *testing.T, ctx *mocks.Ctx, account, assetID string, amount int) {
+	bcc := BlossomSmartContract{}
+	var err error
+	if account == Org2MSP {
+		err = ctx.SetClientIdentity(mocks.Org2SystemAdmin)
+	} else {
+		err = ctx.SetClientIdentity(mocks.Org3SystemAdmin)
+	}
+	require.NoError(t, err)
+
+	err = ctx.SetTransient("checkout", testCheckoutInput(assetID, amount))
+	require.NoError(t, err)
+	err = bcc.RequestCheckout(ctx)
+	require.NoError(t, err)
+
+	err = ctx.SetClientIdentity(mocks.Super)
+	require.NoError(t, err)
+	err = ctx.SetTransient("checkout", approveCheckoutTransientInput{Account: account, AssetID: assetID})
+	require.NoError(t, err)
+	err = bcc.ApproveCheckout(ctx)
 	require.NoError(t, err)
 }
@@ -0,0 +1,507 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+	"github.com/usnistgov/blossom/chaincode/collections"
+	"github.com/usnistgov/blossom/chaincode/model"
+	decider "github.com/usnistgov/blossom/chaincode/ngac/pdp"
+)
+
+func NewTransferContract() TransferInterface {
+	return &BlossomSmartContract{}
+}
+
+func (b *BlossomSmartContract) ProposeTransfer(ctx contractapi.TransactionContextInterface) error {
+	transientInput, err := getProposeTransferTransientInput(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting transient input: %w", err)
+	}
+
+	account, err := accountName(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting MSPID from stub: %w", err)
+	}
+
+	// ngac check
+	if err = decider.CanProposeTransfer(ctx, account); err != nil {
+		return fmt.Errorf("ngac check failed: %w", err)
+	}
+
+	if transientInput.To == account {
+		return fmt.Errorf("account %s cannot transfer licenses to itself", account)
+	}
+
+	if bytes, err := ctx.GetStub().GetState(model.AccountKey(transientInput.To)); err != nil {
+		return fmt.Errorf("error checking if account %q exists: %w", transientInput.To, err)
+	} else if bytes == nil {
+		return fmt.Errorf("account %q does not exist", transientInput.To)
+	}
+
+	acctPvt, err := getAccountPrivate(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	// check that the licenses are leased to the account and are not already being returned or transferred
+	unavailable, err := licensesPendingReturn(ctx, account, transientInput.AssetID)
+	if err != nil {
+		return err
+	}
+
+	checkedOut := acctPvt.Assets[transientInput.AssetID]
+	for _, license := range transientInput.Licenses {
+		if _, ok := checkedOut[license]; !ok {
+			return fmt.Errorf("license %s was not checked out by %s", license, account)
+		} else if _, ok = unavailable[license]; ok {
+			return fmt.Errorf("license %s is already being checked in or transferred by %s", license, account)
+		}
+	}
+
+	txID := ctx.GetStub().GetTxID()
+
+	bytes, err := json.Marshal(model.TransferLicenses{
+		Asset:    transientInput.AssetID,
+		Licenses: transientInput.Licenses,
+	})
+	if err != nil {
+		return fmt.Errorf("error marshaling transfer licenses: %w", err)
+	}
+
+	if err = ctx.GetStub().PutPrivateData(collections.Account(account), transferLicensesKey(account, txID), bytes); err != nil {
+		return fmt.Errorf("error storing transfer licenses: %w", err)
+	}
+
+	transfer := &model.Transfer{
+		ID:      txID,
+		Asset:   transientInput.AssetID,
+		From:    account,
+		To:      transientInput.To,
+		Amount:  len(transientInput.Licenses),
+		History: make([]model.Update, 0),
+	}
+
+	return putTransfer(ctx, transfer, model.TransferProposed, "")
+}
+
+// transferLicensesKey returns the key of the licenses of a transfer in the donating account's private data collection.
+func transferLicensesKey(account, transferID string) string {
+	return fmt.Sprintf("transfer=%s:%s", account, transferID)
+}
+
+// licensesPendingReturn returns the licenses of the asset the account has asked to check in, has proposed to
+// transfer in an open transfer, or has offered in an open offer.
+func licensesPendingReturn(ctx contractapi.TransactionContextInterface, account, assetID string) (map[string]struct{}, error) {
+	licenses := make(map[string]struct{})
+
+	bytes, err := ctx.GetStub().GetPrivateData(collections.Account(account), checkinRequestKey(account, assetID))
+	if err != nil {
+		return nil, fmt.Errorf("error getting checkin request: %w", err)
+	} else if bytes != nil {
+		req := CheckinRequest{}
+		if err = json.Unmarshal(bytes, &req); err != nil {
+			return nil, fmt.Errorf("error unmarshaling checkin request: %w", err)
+		}
+
+		for _, license := range req.Licenses {
+			licenses[license] = struct{}{}
+		}
+	}
+
+	prefix := transferLicensesKey(account, "")
+	keys, err := accountPrivateKeys(ctx, account, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("error getting transfers of account %q: %w", account, err)
+	}
+
+	for _, key := range keys {
+		transfer, err := getTransfer(ctx, strings.TrimPrefix(key, prefix))
+		if err != nil {
+			return nil, err
+		} else if transfer.Asset != assetID || !transfer.IsOpen() {
+			continue
+		}
+
+		transferred, err := getTransferLicenses(ctx, transfer)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, license := range transferred.Licenses {
+			licenses[license] = struct{}{}
+		}
+	}
+
+	prefix = offerLicensesKey(account, "")
+	if keys, err = accountPrivateKeys(ctx, account, prefix); err != nil {
+		return nil, fmt.Errorf("error getting offers of account %q: %w", account, err)
+	}
+
+	for _, key := range keys {
+		offer, err := getOffer(ctx, strings.TrimPrefix(key, prefix))
+		if err != nil {
+			return nil, err
+		} else if offer.Asset != assetID || !offer.IsOpen() {
+			continue
+		}
+
+		offered, err := getOfferLicenses(ctx, offer)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, license := range offered.Licenses {
+			licenses[license] = struct{}{}
+		}
+	}
+
+	return licenses, nil
+}
+
+func (b *BlossomSmartContract) AcceptTransfer(ctx contractapi.TransactionContextInterface, transferID string) error {
+	account, err := accountName(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting MSPID from stub: %w", err)
+	}
+
+	// ngac check
+	if err = decider.CanAcceptTransfer(ctx, account); err != nil {
+		return fmt.Errorf("ngac check failed: %w", err)
+	}
+
+	transfer, err := getTransfer(ctx, transferID)
+	if err != nil {
+		return err
+	}
+
+	if transfer.To != account {
+		return fmt.Errorf("transfer %s is not to account %s", transferID, account)
+	} else if transfer.Status != model.TransferProposed {
+		return fmt.Errorf("transfer %s cannot be accepted with status %s", transferID, transfer.Status)
+	}
+
+	return putTransfer(ctx, transfer, model.TransferAccepted, "")
+}
+
+func (b *BlossomSmartContract) ApproveTransfer(ctx contractapi.TransactionContextInterface, transferID string) error {
+	// ngac check
+	if err := decider.CanApproveTransfer(ctx); err != nil {
+		return fmt.Errorf("ngac check failed: %w", err)
+	}
+
+	transfer, err := getTransfer(ctx, transferID)
+	if err != nil {
+		return err
+	}
+
+	if transfer.Status != model.TransferAccepted {
+		return fmt.Errorf("transfer %s cannot be approved with status %s", transferID, transfer.Status)
+	}
+
+	if err = checkCanReceiveLicenses(ctx, transfer.To); err != nil {
+		return err
+	}
+
+	licenses, err := getTransferLicenses(ctx, transfer)
+	if err != nil {
+		return err
+	}
+
+	fromPvt, err := getAccountPrivate(ctx, transfer.From)
+	if err != nil {
+		return err
+	}
+
+	toPvt, err := getAccountPrivate(ctx, transfer.To)
+	if err != nil {
+		return err
+	}
+
+	assetPub, assetPvt, err := getAsset(ctx, transfer.Asset)
+	if err != nil {
+		return fmt.Errorf("error getting asset %s: %w", transfer.Asset, err)
+	}
+
+	now, err := txTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = moveLicenses(assetPub, assetPvt, transfer.From, fromPvt, transfer.To, toPvt, licenses.Licenses, now); err != nil {
+		return fmt.Errorf("error transferring licenses of %s from %s to %s: %w", transfer.Asset, transfer.From, transfer.To, err)
+	}
+
+	if err = putAccountPrivate(ctx, transfer.From, "ApproveTransfer", fromPvt); err != nil {
+		return err
+	}
+
+	if err = sealLicenseKeys(ctx, toPvt, transfer.Asset, assetPvt); err != nil {
+		return err
+	}
+
+	if err = putAccountPrivate(ctx, transfer.To, "ApproveTransfer", toPvt); err != nil {
+		return err
+	}
+
+	if err = putAsset(ctx, "ApproveTransfer", assetPub, assetPvt); err != nil {
+		return fmt.Errorf("error updating asset %s: %w", transfer.Asset, err)
+	}
+
+	return putTransfer(ctx, transfer, model.TransferApproved, "")
+}
+
+// checkCanReceiveLicenses checks that the account is still authorized to hold licenses.
+func checkCanReceiveLicenses(ctx contractapi.TransactionContextInterface, account string) error {
+	bytes, err := ctx.GetStub().GetState(model.AccountKey(account))
+	if err != nil {
+		return fmt.Errorf("error getting account %q from world state: %w", account, err)
+	} else if bytes == nil {
+		return fmt.Errorf("account %q does not exist", account)
+	}
+
+	acctPub := model.NewAccountPublic()
+	if err = json.Unmarshal(bytes, acctPub); err != nil {
+		return fmt.Errorf("error unmarshaling account %q: %w", account, err)
+	} else if acctPub.Status != model.Authorized {
+		return fmt.Errorf("account %q cannot receive licenses with status %q", account, acctPub.Status)
+	}
+
+	return nil
+}
+
+// moveLicenses moves the licenses, with their leases, from one account to another in both accounts and in the
+// asset's checked out licenses.  The donating account is charged for the leases until the given time and the
+// recipient's leases start at the given time.  The number of available licenses does not change.
+func moveLicenses(assetPub *model.AssetPublic, assetPvt *model.AssetPrivate, from string, fromPvt *model.AccountPrivate,
+	to string, toPvt *model.AccountPrivate, licenses []string, now time.Time) error {
+	assetID := assetPub.ID
+	fromCheckedOut := fromPvt.Assets[assetID]
+	fromAssetCheckedOut := assetPvt.CheckedOut[from]
+
+	toCheckedOut, ok := toPvt.Assets[assetID]
+	if !ok {
+		toCheckedOut = make(map[string]model.LicenseLease)
+	}
+
+	toAssetCheckedOut, ok := assetPvt.CheckedOut[to]
+	if !ok {
+		toAssetCheckedOut = make(map[string]model.LicenseLease)
+	}
+
+	for _, license := range licenses {
+		lease, ok := fromCheckedOut[license]
+		if !ok {
+			return fmt.Errorf("license %s is no longer checked out by %s", license, from)
+		}
+
+		assetPvt.Charge(assetPub, from, license, fromAssetCheckedOut[license], now)
+		delete(fromCheckedOut, license)
+		delete(fromAssetCheckedOut, license)
+
+		// the key sealed to the donating account is sealed again to the receiving account when it is written
+		lease.Start = now
+		lease.SealedKey = ""
+		toCheckedOut[license] = lease
+		toAssetCheckedOut[license] = lease
+	}
+
+	// remove the asset from the donating account if all of its licenses were transferred
+	if len(fromCheckedOut) == 0 {
+		delete(fromPvt.Assets, assetID)
+	}
+
+	if len(fromAssetCheckedOut) == 0 {
+		delete(assetPvt.CheckedOut, from)
+	}
+
+	toPvt.Assets[assetID] = toCheckedOut
+	assetPvt.CheckedOut[to] = toAssetCheckedOut
+
+	return nil
+}
+
+func (b *BlossomSmartContract) CancelTransfer(ctx contractapi.TransactionContextInterface, transferID, reason string) error {
+	if reason == "" {
+		return fmt.Errorf("a reason is required to cancel a transfer")
+	}
+
+	transfer, err := getTransfer(ctx, transferID)
+	if err != nil {
+		return err
+	}
+
+	account, err := accountName(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting MSPID from stub: %w", err)
+	}
+
+	// ngac check, either account can withdraw from the transfer and the admin can deny it
+	switch account {
+	case transfer.From:
+		err = decider.CanProposeTransfer(ctx, account)
+	case transfer.To:
+		err = decider.CanAcceptTransfer(ctx, account)
+	default:
+		err = decider.CanApproveTransfer(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("ngac check failed: %w", err)
+	}
+
+	if !transfer.IsOpen() {
+		return fmt.Errorf("transfer %s cannot be cancelled with status %s", transferID, transfer.Status)
+	}
+
+	return putTransfer(ctx, transfer, model.TransferCancelled, reason)
+}
+
+func (b *BlossomSmartContract) GetTransfers(ctx contractapi.TransactionContextInterface, account string) ([]*model.Transfer, error) {
+	return getTransfers(ctx, account)
+}
+
+// getTransfers returns the transfers the account is donating or receiving licenses in ordered by when they were
+// proposed.
+func getTransfers(ctx contractapi.TransactionContextInterface, account string) ([]*model.Transfer, error) {
+	iter, err := ctx.GetStub().GetStateByRange(model.TransferKeyRange())
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	transfers := make([]*model.Transfer, 0)
+	for iter.HasNext() {
+		var next *queryresult.KV
+		if next, err = iter.Next(); err != nil {
+			return nil, fmt.Errorf("error getting next KV: %w", err)
+		}
+
+		if !strings.HasPrefix(next.Key, model.TransferPrefix) {
+			continue
+		}
+
+		transfer := &model.Transfer{}
+		if err = json.Unmarshal(next.Value, transfer); err != nil {
+			return nil, fmt.Errorf("error unmarshaling transfer: %w", err)
+		}
+
+		if transfer.From == account || transfer.To == account {
+			transfers = append(transfers, transfer)
+		}
+	}
+
+	// order the transfers by when they were proposed
+	sort.Slice(transfers, func(i, j int) bool {
+		ti, tj := transfers[i].History[0].Timestamp, transfers[j].History[0].Timestamp
+		if ti.Equal(tj) {
+			return transfers[i].ID < transfers[j].ID
+		}
+
+		return ti.Before(tj)
+	})
+
+	return transfers, nil
+}
+
+// openTransfers returns the open transfers the account is donating or receiving licenses in.
+func openTransfers(ctx contractapi.TransactionContextInterface, account string) ([]*model.Transfer, error) {
+	transfers, err := getTransfers(ctx, account)
+	if err != nil {
+		return nil, fmt.Errorf("error getting transfers of account %q: %w", account, err)
+	}
+
+	open := make([]*model.Transfer, 0)
+	for _, transfer := range transfers {
+		if transfer.IsOpen() {
+			open = append(open, transfer)
+		}
+	}
+
+	return open, nil
+}
+
+// assetTransfers returns the open transfers of licenses of the asset.
+func assetTransfers(ctx contractapi.TransactionContextInterface, assetID string) ([]*model.Transfer, error) {
+	iter, err := ctx.GetStub().GetStateByRange(model.TransferKeyRange())
+	if err != nil {
+		return nil, fmt.Errorf("error getting transfers of asset %s: %w", assetID, err)
+	}
+	defer iter.Close()
+
+	open := make([]*model.Transfer, 0)
+	for iter.HasNext() {
+		var next *queryresult.KV
+		if next, err = iter.Next(); err != nil {
+			return nil, fmt.Errorf("error getting next KV: %w", err)
+		}
+
+		if !strings.HasPrefix(next.Key, model.TransferPrefix) {
+			continue
+		}
+
+		transfer := &model.Transfer{}
+		if err = json.Unmarshal(next.Value, transfer); err != nil {
+			return nil, fmt.Errorf("error unmarshaling transfer: %w", err)
+		}
+
+		if transfer.Asset == assetID && transfer.IsOpen() {
+			open = append(open, transfer)
+		}
+	}
+
+	return open, nil
+}
+
+func getTransfer(ctx contractapi.TransactionContextInterface, transferID string) (*model.Transfer, error) {
+	bytes, err := ctx.GetStub().GetState(model.TransferKey(transferID))
+	if err != nil {
+		return nil, fmt.Errorf("error getting transfer %s: %w", transferID, err)
+	} else if bytes == nil {
+		return nil, fmt.Errorf("transfer %s does not exist", transferID)
+	}
+
+	transfer := &model.Transfer{}
+	if err = json.Unmarshal(bytes, transfer); err != nil {
+		return nil, fmt.Errorf("error unmarshaling transfer %s: %w", transferID, err)
+	}
+
+	return transfer, nil
+}
+
+// getTransferLicenses returns the licenses of the transfer from the donating account's private data collection.
+func getTransferLicenses(ctx contractapi.TransactionContextInterface, transfer *model.Transfer) (*model.TransferLicenses, error) {
+	bytes, err := ctx.GetStub().GetPrivateData(collections.Account(transfer.From), transferLicensesKey(transfer.From, transfer.ID))
+	if err != nil {
+		return nil, fmt.Errorf("error getting licenses of transfer %s: %w", transfer.ID, err)
+	} else if bytes == nil {
+		return nil, fmt.Errorf("licenses of transfer %s do not exist", transfer.ID)
+	}
+
+	licenses := &model.TransferLicenses{}
+	if err = json.Unmarshal(bytes, licenses); err != nil {
+		return nil, fmt.Errorf("error unmarshaling licenses of transfer %s: %w", transfer.ID, err)
+	}
+
+	return licenses, nil
+}
+
+// putTransfer updates the status of the transfer, records the update in the transfer's history, and writes the
+// transfer to the world state.
+func putTransfer(ctx contractapi.TransactionContextInterface, transfer *model.Transfer, status model.TransferStatus, reason string) error {
+	update, err := newUpdate(ctx, string(status), reason)
+	if err != nil {
+		return err
+	}
+
+	transfer.Status = status
+	if err = putWithHistory(ctx, "", model.TransferKey(transfer.ID), transfer, &transfer.History, update); err != nil {
+		return fmt.Errorf("error updating transfer %s: %w", transfer.ID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/usnistgov/blossom/chaincode/mocks"
+	"github.com/usnistgov/blossom/chaincode/model"
+)
+
+func TestTransferLicenses(t *testing.T) {
+	ctx := newTestStub(t)
+	bcc := BlossomSmartContract{}
+
+	onboardTestAsset(t, ctx, "123", "myasset", []string{"1", "2", "3"})
+	requestTestAccount(t, ctx, Org2MSP)
+	requestTestAccount(t, ctx, Org3MSP)
+
+	checkoutTestAsset(t, ctx, Org2MSP, "123", 2)
+
+	propose := func(to string, licenses ...string) error {
+		err := ctx.SetClientIdentity(mocks.Org2SystemAdmin)
+		require.NoError(t, err)
+		err = ctx.SetTransient("transfer", proposeTransferTransientInput{AssetID: "123", Licenses: licenses, To: to})
+		require.NoError(t, err)
+		return bcc.ProposeTransfer(ctx)
+	}
+
+	lastTransfer := func() *model.Transfer {
+		transfers, err := bcc.GetTransfers(ctx, Org2MSP)
+		require.NoError(t, err)
+		return transfers[len(transfers)-1]
+	}
+
+	t.Run("test propose transfer", func(t *testing.T) {
+		err := propose(Org2MSP, "1")
+		require.Error(t, err)
+
+		err = propose(Org3MSP, "3")
+		require.Error(t, err)
+
+		err = propose("unknown", "1")
+		require.Error(t, err)
+
+		err = propose(Org3MSP, "1")
+		require.NoError(t, err)
+
+		transfer := lastTransfer()
+		require.Equal(t, model.TransferProposed, transfer.Status)
+		require.Equal(t, Org2MSP, transfer.From)
+		require.Equal(t, Org3MSP, transfer.To)
+		require.Equal(t, 1, transfer.Amount)
+
+		// a license cannot be in more than one open transfer
+		err = propose(Org3MSP, "1")
+		require.Error(t, err)
+	})
+
+	t.Run("test approve before accept", func(t *testing.T) {
+		err := ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		err = bcc.ApproveTransfer(ctx, lastTransfer().ID)
+		require.Error(t, err)
+	})
+
+	t.Run("test accept transfer", func(t *testing.T) {
+		id := lastTransfer().ID
+
+		err := ctx.SetClientIdentity(mocks.Org2SystemAdmin)
+		require.NoError(t, err)
+		err = bcc.AcceptTransfer(ctx, id)
+		require.Error(t, err)
+
+		err = ctx.SetClientIdentity(mocks.Org3SystemAdmin)
+		require.NoError(t, err)
+		err = bcc.AcceptTransfer(ctx, id)
+		require.NoError(t, err)
+		require.Equal(t, model.TransferAccepted, lastTransfer().Status)
+	})
+
+	t.Run("test approve transfer", func(t *testing.T) {
+		err := ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		err = bcc.ApproveTransfer(ctx, lastTransfer().ID)
+		require.NoError(t, err)
+		require.Equal(t, model.TransferApproved, lastTransfer().Status)
+
+		err = ctx.SetClientIdentity(mocks.Org2SystemAdmin)
+		require.NoError(t, err)
+		licenses, err := bcc.GetLicenses(ctx, Org2MSP, "123")
+		require.NoError(t, err)
+		require.Len(t, licenses, 1)
+		require.NotContains(t, licenses, "1")
+
+		err = ctx.SetClientIdentity(mocks.Org3SystemAdmin)
+		require.NoError(t, err)
+		licenses, err = bcc.GetLicenses(ctx, Org3MSP, "123")
+		require.NoError(t, err)
+		require.Len(t, licenses, 1)
+		require.Contains(t, licenses, "1")
+
+		err = ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		asset, err := bcc.GetAsset(ctx, "123")
+		require.NoError(t, err)
+		require.Equal(t, 1, asset.Available)
+		require.Len(t, asset.CheckedOut[Org2MSP], 1)
+		require.Len(t, asset.CheckedOut[Org3MSP], 1)
+
+		// an approved transfer cannot be cancelled
+		err = bcc.CancelTransfer(ctx, lastTransfer().ID, "too late")
+		require.Error(t, err)
+	})
+
+	t.Run("test cancel transfer", func(t *testing.T) {
+		err := propose(Org3MSP, "2")
+		require.NoError(t, err)
+
+		err = bcc.CancelTransfer(ctx, lastTransfer().ID, "")
+		require.Error(t, err)
+
+		err = bcc.CancelTransfer(ctx, lastTransfer().ID, "changed my mind")
+		require.NoError(t, err)
+
+		transfer := lastTransfer()
+		require.Equal(t, model.TransferCancelled, transfer.Status)
+		require.Equal(t, "changed my mind", transfer.History[len(transfer.History)-1].Reason)
+
+		err = propose(Org3MSP, "2")
+		require.NoError(t, err)
+
+		err = ctx.SetClientIdentity(mocks.Org3SystemAdmin)
+		require.NoError(t, err)
+		err = bcc.CancelTransfer(ctx, lastTransfer().ID, "not needed")
+		require.NoError(t, err)
+		require.Equal(t, model.TransferCancelled, lastTransfer().Status)
+	})
+
+	t.Run("test opt out cancels open transfers", func(t *testing.T) {
+		err := propose(Org3MSP, "2")
+		require.NoError(t, err)
+
+		err = ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		err = bcc.UpdateAccountStatus(ctx, Org2MSP, "UNAUTHORIZED_OPTOUT", "opting out")
+		require.NoError(t, err)
+
+		require.Equal(t, model.TransferCancelled, lastTransfer().Status)
+
+		asset, err := bcc.GetAsset(ctx, "123")
+		require.NoError(t, err)
+		require.Equal(t, 2, asset.Available)
+		require.NotContains(t, asset.CheckedOut, Org2MSP)
+	})
+}
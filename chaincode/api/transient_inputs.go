@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"github.com/hyperledger/fabric-contract-api-go/contractapi"
 	"github.com/usnistgov/blossom/chaincode/model"
+	"github.com/usnistgov/blossom/chaincode/seal"
+	"strings"
+	"time"
 )
 
 type (
@@ -12,24 +15,94 @@ type (
 		SystemOwner           string `json:"system_owner,omitempty"`
 		SystemAdmin           string `json:"system_admin,omitempty"`
 		AcquisitionSpecialist string `json:"acquisition_specialist,omitempty"`
+		accountAgencyTransientInput
+	}
+
+	accountAgencyTransientInput struct {
+		Department      string                 `json:"department,omitempty"`
+		SubAgency       string                 `json:"sub_agency,omitempty"`
+		PointsOfContact []model.PointOfContact `json:"points_of_contact,omitempty"`
+	}
+
+	accountUserTransientInput struct {
+		Username string `json:"username,omitempty"`
+		Role     string `json:"role,omitempty"`
 	}
 
 	uploadATOTransientInput struct {
-		ATO string `json:"ato,omitempty"`
+		AuthorizingOfficial string    `json:"authorizing_official,omitempty"`
+		SystemName          string    `json:"system_name,omitempty"`
+		IssueDate           time.Time `json:"issue_date,omitempty"`
+		ExpirationDate      time.Time `json:"expiration_date,omitempty"`
+		ImpactLevel         string    `json:"impact_level,omitempty"`
+		Digest              string    `json:"digest,omitempty"`
 	}
 
 	onboardAssetTransientInput struct {
 		Licenses []model.License `json:"licenses,omitempty"`
+		// Cost is the optional price information of the asset
+		Cost *model.AssetCost `json:"cost,omitempty"`
+		// AllocationStrategy is how licenses are selected when they are checked out.  If empty licenses are selected
+		// in pool order.
+		AllocationStrategy model.AllocationStrategy `json:"allocation_strategy,omitempty"`
+	}
+
+	updateAssetCostTransientInput struct {
+		model.AssetCost
+	}
+
+	addLicensesTransientInput struct {
+		Licenses []model.License `json:"licenses,omitempty"`
+	}
+
+	retireLicensesTransientInput struct {
+		Licenses []string `json:"licenses,omitempty"`
 	}
 
 	requestCheckoutTransientInput struct {
+		AssetID       string    `json:"asset_id,omitempty"`
+		Amount        int       `json:"amount,omitempty"`
+		Justification string    `json:"justification,omitempty"`
+		LeaseTermDays int       `json:"lease_term_days,omitempty"`
+		FISMASystem   string    `json:"fisma_system,omitempty"`
+		NeededBy      time.Time `json:"needed_by,omitempty"`
+	}
+
+	requestLeaseRenewalTransientInput struct {
+		AssetID string `json:"asset_id,omitempty"`
+		// Licenses are the licenses to renew.  If empty all of the licenses held for the asset are renewed.
+		Licenses []string `json:"licenses,omitempty"`
+		TermDays int      `json:"term_days,omitempty"`
+	}
+
+	processLeaseRenewalTransientInput struct {
+		Account string `json:"account,omitempty"`
 		AssetID string `json:"asset_id,omitempty"`
-		Amount  int    `json:"amount,omitempty"`
 	}
 
 	approveCheckoutTransientInput struct {
 		Account string `json:"account,omitempty"`
 		AssetID string `json:"asset_id,omitempty"`
+		// Amount is the number of licenses to approve.  If not set the full amount requested is approved.
+		Amount int    `json:"amount,omitempty"`
+		Reason string `json:"reason,omitempty"`
+		// Priority orders the request on the asset's waitlist if there are not enough licenses available.  Requests
+		// with a higher priority are offered licenses first.
+		Priority int `json:"priority,omitempty"`
+		// Licenses are the licenses chosen by the approver for assets with the EXPLICIT allocation strategy.  The
+		// number of licenses is the amount approved.
+		Licenses []string `json:"licenses,omitempty"`
+	}
+
+	denyCheckoutTransientInput struct {
+		Account string `json:"account,omitempty"`
+		AssetID string `json:"asset_id,omitempty"`
+		Reason  string `json:"reason,omitempty"`
+	}
+
+	cancelCheckoutTransientInput struct {
+		AssetID string `json:"asset_id,omitempty"`
+		Reason  string `json:"reason,omitempty"`
 	}
 
 	initiateCheckinTransientInput struct {
@@ -42,6 +115,53 @@ type (
 		AssetID string `json:"asset_id,omitempty"`
 	}
 
+	proposeTransferTransientInput struct {
+		AssetID  string   `json:"asset_id,omitempty"`
+		Licenses []string `json:"licenses,omitempty"`
+		// To is the account receiving the licenses
+		To string `json:"to,omitempty"`
+	}
+
+	postOfferTransientInput struct {
+		AssetID  string   `json:"asset_id,omitempty"`
+		Licenses []string `json:"licenses,omitempty"`
+		// AvailableUntil is the date after which the offer can no longer be claimed
+		AvailableUntil time.Time `json:"available_until,omitempty"`
+	}
+
+	submitForecastTransientInput struct {
+		Product       string `json:"product,omitempty"`
+		Quantity      int    `json:"quantity,omitempty"`
+		FiscalYear    int    `json:"fiscal_year,omitempty"`
+		Justification string `json:"justification,omitempty"`
+	}
+
+	requestAcquisitionTransientInput struct {
+		Product       string `json:"product,omitempty"`
+		Quantity      int    `json:"quantity,omitempty"`
+		Justification string `json:"justification,omitempty"`
+	}
+
+	attachQuoteTransientInput struct {
+		Vendor      string `json:"vendor,omitempty"`
+		Digest      string `json:"digest,omitempty"`
+		AmountCents int64  `json:"amount_cents,omitempty"`
+		URI         string `json:"uri,omitempty"`
+	}
+
+	recordPurchaseTransientInput struct {
+		OrderNumber string          `json:"order_number,omitempty"`
+		QuoteDigest string          `json:"quote_digest,omitempty"`
+		Licenses    []model.License `json:"licenses,omitempty"`
+		// Cost is the optional price information the asset is onboarded with
+		Cost *model.AssetCost `json:"cost,omitempty"`
+	}
+
+	registerEncryptionKeyTransientInput struct {
+		// PublicKey is the PEM encoded RSA public key
+		PublicKey string `json:"public_key,omitempty"`
+	}
+
 	reportSwIDTransientInput struct {
 		PrimaryTag string `json:"primary_tag,omitempty"`
 		Asset      string `json:"asset,omitempty"`
@@ -76,15 +196,77 @@ func getAccountTransientInput(ctx contractapi.TransactionContextInterface) (acco
 		return accountTransientInput{}, fmt.Errorf("error unmarshaling json: %w", err)
 	}
 
-	if len(input.SystemOwner) == 0 {
-		return accountTransientInput{}, fmt.Errorf("account system owner cannot be nil")
-	}
 	if len(input.SystemAdmin) == 0 {
 		return accountTransientInput{}, fmt.Errorf("account system admin cannot be nil")
 	}
 	if len(input.AcquisitionSpecialist) == 0 {
 		return accountTransientInput{}, fmt.Errorf("account acquisition specialist cannot be nil")
 	}
+	// each user has one role in the account
+	if input.SystemOwner == input.SystemAdmin || input.SystemOwner == input.AcquisitionSpecialist ||
+		input.SystemAdmin == input.AcquisitionSpecialist {
+		return accountTransientInput{}, fmt.Errorf("account system owner, system admin and acquisition specialist must be different users")
+	}
+	if err = input.accountAgencyTransientInput.validate(); err != nil {
+		return accountTransientInput{}, err
+	}
+
+	return input, nil
+}
+
+func getAccountAgencyTransientInput(ctx contractapi.TransactionContextInterface) (accountAgencyTransientInput, error) {
+	transientMap, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return accountAgencyTransientInput{}, fmt.Errorf("error getting transient: %w", err)
+	}
+
+	transientAgencyJson, ok := transientMap["agency"]
+	if !ok {
+		return accountAgencyTransientInput{}, fmt.Errorf("agency not found in transient map input")
+	}
+
+	var input accountAgencyTransientInput
+	if err = json.Unmarshal(transientAgencyJson, &input); err != nil {
+		return accountAgencyTransientInput{}, fmt.Errorf("error unmarshaling json: %w", err)
+	}
+
+	if err = input.validate(); err != nil {
+		return accountAgencyTransientInput{}, err
+	}
+
+	return input, nil
+}
+
+func (i accountAgencyTransientInput) validate() error {
+	if len(i.Department) == 0 {
+		return fmt.Errorf("account department cannot be nil")
+	}
+
+	return model.ValidatePointsOfContact(i.PointsOfContact)
+}
+
+func getAccountUserTransientInput(ctx contractapi.TransactionContextInterface) (accountUserTransientInput, error) {
+	transientMap, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return accountUserTransientInput{}, fmt.Errorf("error getting transient: %w", err)
+	}
+
+	transientAccountJson, ok := transientMap["user"]
+	if !ok {
+		return accountUserTransientInput{}, fmt.Errorf("user not found in transient map input")
+	}
+
+	var input accountUserTransientInput
+	if err = json.Unmarshal(transientAccountJson, &input); err != nil {
+		return accountUserTransientInput{}, fmt.Errorf("error unmarshaling json: %w", err)
+	}
+
+	if input.Username == "" {
+		return accountUserTransientInput{}, fmt.Errorf("username cannot be nil")
+	}
+	if input.Role == "" {
+		return accountUserTransientInput{}, fmt.Errorf("role cannot be nil")
+	}
 
 	return input, nil
 }
@@ -105,13 +287,24 @@ func getUploadATOTransientInput(ctx contractapi.TransactionContextInterface) (up
 		return uploadATOTransientInput{}, fmt.Errorf("error unmarshaling json: %w", err)
 	}
 
-	if len(input.ATO) == 0 {
-		return uploadATOTransientInput{}, fmt.Errorf("ato cannot be nil")
+	if err = input.toATO().Validate(); err != nil {
+		return uploadATOTransientInput{}, fmt.Errorf("invalid ato: %w", err)
 	}
 
 	return input, nil
 }
 
+func (i uploadATOTransientInput) toATO() *model.ATO {
+	return &model.ATO{
+		AuthorizingOfficial: i.AuthorizingOfficial,
+		SystemName:          i.SystemName,
+		IssueDate:           i.IssueDate.UTC(),
+		ExpirationDate:      i.ExpirationDate.UTC(),
+		ImpactLevel:         model.ImpactLevel(i.ImpactLevel),
+		Digest:              i.Digest,
+	}
+}
+
 func getOnboardAssetTransientInput(ctx contractapi.TransactionContextInterface) (onboardAssetTransientInput, error) {
 	transientMap, err := ctx.GetStub().GetTransient()
 	if err != nil {
@@ -131,6 +324,60 @@ func getOnboardAssetTransientInput(ctx contractapi.TransactionContextInterface)
 	if len(input.Licenses) == 0 {
 		return onboardAssetTransientInput{}, fmt.Errorf("licenses cannot be empty")
 	}
+	if input.Cost != nil {
+		if err = input.Cost.Validate(); err != nil {
+			return onboardAssetTransientInput{}, err
+		}
+	}
+	if err = model.ValidateAllocationStrategy(input.AllocationStrategy); err != nil {
+		return onboardAssetTransientInput{}, err
+	}
+
+	return input, nil
+}
+
+func getAddLicensesTransientInput(ctx contractapi.TransactionContextInterface) (addLicensesTransientInput, error) {
+	transientMap, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return addLicensesTransientInput{}, fmt.Errorf("error getting transient: %w", err)
+	}
+
+	transientLicensesJson, ok := transientMap["licenses"]
+	if !ok {
+		return addLicensesTransientInput{}, fmt.Errorf("licenses not found in transient map input")
+	}
+
+	var input addLicensesTransientInput
+	if err = json.Unmarshal(transientLicensesJson, &input); err != nil {
+		return addLicensesTransientInput{}, fmt.Errorf("error unmarshaling json: %w", err)
+	}
+
+	if len(input.Licenses) == 0 {
+		return addLicensesTransientInput{}, fmt.Errorf("licenses cannot be empty")
+	}
+
+	return input, nil
+}
+
+func getRetireLicensesTransientInput(ctx contractapi.TransactionContextInterface) (retireLicensesTransientInput, error) {
+	transientMap, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return retireLicensesTransientInput{}, fmt.Errorf("error getting transient: %w", err)
+	}
+
+	transientLicensesJson, ok := transientMap["licenses"]
+	if !ok {
+		return retireLicensesTransientInput{}, fmt.Errorf("licenses not found in transient map input")
+	}
+
+	var input retireLicensesTransientInput
+	if err = json.Unmarshal(transientLicensesJson, &input); err != nil {
+		return retireLicensesTransientInput{}, fmt.Errorf("error unmarshaling json: %w", err)
+	}
+
+	if len(input.Licenses) == 0 {
+		return retireLicensesTransientInput{}, fmt.Errorf("licenses cannot be empty")
+	}
 
 	return input, nil
 }
@@ -154,8 +401,72 @@ func getRequestCheckoutTransientInput(ctx contractapi.TransactionContextInterfac
 	if input.AssetID == "" {
 		return requestCheckoutTransientInput{}, fmt.Errorf("asset id cannot be empty")
 	}
-	if input.Amount == 0 {
-		return requestCheckoutTransientInput{}, fmt.Errorf("amount cannot be empty")
+	if input.Amount <= 0 {
+		return requestCheckoutTransientInput{}, fmt.Errorf("amount must be greater than 0")
+	}
+	if input.Justification == "" {
+		return requestCheckoutTransientInput{}, fmt.Errorf("justification cannot be empty")
+	}
+	if err = model.ValidateLeaseTerm(input.LeaseTermDays); err != nil {
+		return requestCheckoutTransientInput{}, err
+	}
+	if input.FISMASystem == "" {
+		return requestCheckoutTransientInput{}, fmt.Errorf("fisma system cannot be empty")
+	}
+	if input.NeededBy.IsZero() {
+		return requestCheckoutTransientInput{}, fmt.Errorf("needed by date cannot be empty")
+	}
+
+	return input, nil
+}
+
+func getRequestLeaseRenewalTransientInput(ctx contractapi.TransactionContextInterface) (requestLeaseRenewalTransientInput, error) {
+	transientMap, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return requestLeaseRenewalTransientInput{}, fmt.Errorf("error getting transient: %w", err)
+	}
+
+	transientRenewalJson, ok := transientMap["renewal"]
+	if !ok {
+		return requestLeaseRenewalTransientInput{}, fmt.Errorf("renewal not found in transient map input")
+	}
+
+	var input requestLeaseRenewalTransientInput
+	if err = json.Unmarshal(transientRenewalJson, &input); err != nil {
+		return requestLeaseRenewalTransientInput{}, fmt.Errorf("error unmarshaling json: %w", err)
+	}
+
+	if input.AssetID == "" {
+		return requestLeaseRenewalTransientInput{}, fmt.Errorf("asset id cannot be empty")
+	}
+	if err = model.ValidateLeaseTerm(input.TermDays); err != nil {
+		return requestLeaseRenewalTransientInput{}, err
+	}
+
+	return input, nil
+}
+
+func getProcessLeaseRenewalTransientInput(ctx contractapi.TransactionContextInterface) (processLeaseRenewalTransientInput, error) {
+	transientMap, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return processLeaseRenewalTransientInput{}, fmt.Errorf("error getting transient: %w", err)
+	}
+
+	transientRenewalJson, ok := transientMap["renewal"]
+	if !ok {
+		return processLeaseRenewalTransientInput{}, fmt.Errorf("renewal not found in transient map input")
+	}
+
+	var input processLeaseRenewalTransientInput
+	if err = json.Unmarshal(transientRenewalJson, &input); err != nil {
+		return processLeaseRenewalTransientInput{}, fmt.Errorf("error unmarshaling json: %w", err)
+	}
+
+	if input.Account == "" {
+		return processLeaseRenewalTransientInput{}, fmt.Errorf("account cannot be empty")
+	}
+	if input.AssetID == "" {
+		return processLeaseRenewalTransientInput{}, fmt.Errorf("asset id cannot be empty")
 	}
 
 	return input, nil
@@ -183,6 +494,70 @@ func getApproveCheckoutTransientInput(ctx contractapi.TransactionContextInterfac
 	if input.AssetID == "" {
 		return approveCheckoutTransientInput{}, fmt.Errorf("asset id cannot be empty")
 	}
+	if input.Amount < 0 {
+		return approveCheckoutTransientInput{}, fmt.Errorf("approved amount cannot be negative")
+	}
+
+	chosen := make(map[string]bool)
+	for _, license := range input.Licenses {
+		if chosen[license] {
+			return approveCheckoutTransientInput{}, fmt.Errorf("license %s was chosen more than once", license)
+		}
+
+		chosen[license] = true
+	}
+
+	return input, nil
+}
+
+func getDenyCheckoutTransientInput(ctx contractapi.TransactionContextInterface) (denyCheckoutTransientInput, error) {
+	transientMap, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return denyCheckoutTransientInput{}, fmt.Errorf("error getting transient: %w", err)
+	}
+
+	transientAccountJson, ok := transientMap["checkout"]
+	if !ok {
+		return denyCheckoutTransientInput{}, fmt.Errorf("checkout not found in transient map input")
+	}
+
+	var input denyCheckoutTransientInput
+	if err = json.Unmarshal(transientAccountJson, &input); err != nil {
+		return denyCheckoutTransientInput{}, fmt.Errorf("error unmarshaling json: %w", err)
+	}
+
+	if input.Account == "" {
+		return denyCheckoutTransientInput{}, fmt.Errorf("account cannot be empty")
+	}
+	if input.AssetID == "" {
+		return denyCheckoutTransientInput{}, fmt.Errorf("asset id cannot be empty")
+	}
+	if input.Reason == "" {
+		return denyCheckoutTransientInput{}, fmt.Errorf("reason cannot be empty")
+	}
+
+	return input, nil
+}
+
+func getCancelCheckoutTransientInput(ctx contractapi.TransactionContextInterface) (cancelCheckoutTransientInput, error) {
+	transientMap, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return cancelCheckoutTransientInput{}, fmt.Errorf("error getting transient: %w", err)
+	}
+
+	transientAccountJson, ok := transientMap["checkout"]
+	if !ok {
+		return cancelCheckoutTransientInput{}, fmt.Errorf("checkout not found in transient map input")
+	}
+
+	var input cancelCheckoutTransientInput
+	if err = json.Unmarshal(transientAccountJson, &input); err != nil {
+		return cancelCheckoutTransientInput{}, fmt.Errorf("error unmarshaling json: %w", err)
+	}
+
+	if input.AssetID == "" {
+		return cancelCheckoutTransientInput{}, fmt.Errorf("asset id cannot be empty")
+	}
 
 	return input, nil
 }
@@ -322,3 +697,247 @@ func getGetSwIDsAssociatedWithAssetTransientInput(ctx contractapi.TransactionCon
 
 	return input, nil
 }
+
+func getProposeTransferTransientInput(ctx contractapi.TransactionContextInterface) (proposeTransferTransientInput, error) {
+	transientMap, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return proposeTransferTransientInput{}, fmt.Errorf("error getting transient: %w", err)
+	}
+
+	transientTransferJson, ok := transientMap["transfer"]
+	if !ok {
+		return proposeTransferTransientInput{}, fmt.Errorf("transfer not found in transient map input")
+	}
+
+	var input proposeTransferTransientInput
+	if err = json.Unmarshal(transientTransferJson, &input); err != nil {
+		return proposeTransferTransientInput{}, fmt.Errorf("error unmarshaling json: %w", err)
+	}
+
+	if input.AssetID == "" {
+		return proposeTransferTransientInput{}, fmt.Errorf("asset id cannot be empty")
+	}
+	if len(input.Licenses) == 0 {
+		return proposeTransferTransientInput{}, fmt.Errorf("licenses cannot be empty")
+	}
+	if input.To == "" {
+		return proposeTransferTransientInput{}, fmt.Errorf("recipient account cannot be empty")
+	}
+
+	seen := make(map[string]bool)
+	for _, license := range input.Licenses {
+		if seen[license] {
+			return proposeTransferTransientInput{}, fmt.Errorf("license %s is listed more than once", license)
+		}
+		seen[license] = true
+	}
+
+	return input, nil
+}
+
+func getPostOfferTransientInput(ctx contractapi.TransactionContextInterface) (postOfferTransientInput, error) {
+	transientMap, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return postOfferTransientInput{}, fmt.Errorf("error getting transient: %w", err)
+	}
+
+	transientOfferJson, ok := transientMap["offer"]
+	if !ok {
+		return postOfferTransientInput{}, fmt.Errorf("offer not found in transient map input")
+	}
+
+	var input postOfferTransientInput
+	if err = json.Unmarshal(transientOfferJson, &input); err != nil {
+		return postOfferTransientInput{}, fmt.Errorf("error unmarshaling json: %w", err)
+	}
+
+	if input.AssetID == "" {
+		return postOfferTransientInput{}, fmt.Errorf("asset id cannot be empty")
+	}
+	if len(input.Licenses) == 0 {
+		return postOfferTransientInput{}, fmt.Errorf("licenses cannot be empty")
+	}
+	if input.AvailableUntil.IsZero() {
+		return postOfferTransientInput{}, fmt.Errorf("available until date cannot be empty")
+	}
+
+	seen := make(map[string]bool)
+	for _, license := range input.Licenses {
+		if seen[license] {
+			return postOfferTransientInput{}, fmt.Errorf("license %s is listed more than once", license)
+		}
+		seen[license] = true
+	}
+
+	return input, nil
+}
+
+func getUpdateAssetCostTransientInput(ctx contractapi.TransactionContextInterface) (updateAssetCostTransientInput, error) {
+	transientMap, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return updateAssetCostTransientInput{}, fmt.Errorf("error getting transient: %w", err)
+	}
+
+	transientCostJson, ok := transientMap["cost"]
+	if !ok {
+		return updateAssetCostTransientInput{}, fmt.Errorf("cost not found in transient map input")
+	}
+
+	var input updateAssetCostTransientInput
+	if err = json.Unmarshal(transientCostJson, &input); err != nil {
+		return updateAssetCostTransientInput{}, fmt.Errorf("error unmarshaling json: %w", err)
+	}
+
+	if err = input.Validate(); err != nil {
+		return updateAssetCostTransientInput{}, err
+	}
+
+	return input, nil
+}
+
+func getSubmitForecastTransientInput(ctx contractapi.TransactionContextInterface) (submitForecastTransientInput, error) {
+	transientMap, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return submitForecastTransientInput{}, fmt.Errorf("error getting transient: %w", err)
+	}
+
+	transientForecastJson, ok := transientMap["forecast"]
+	if !ok {
+		return submitForecastTransientInput{}, fmt.Errorf("forecast not found in transient map input")
+	}
+
+	var input submitForecastTransientInput
+	if err = json.Unmarshal(transientForecastJson, &input); err != nil {
+		return submitForecastTransientInput{}, fmt.Errorf("error unmarshaling json: %w", err)
+	}
+
+	if strings.TrimSpace(input.Product) == "" {
+		return submitForecastTransientInput{}, fmt.Errorf("product cannot be empty")
+	}
+	if input.Quantity <= 0 {
+		return submitForecastTransientInput{}, fmt.Errorf("quantity must be greater than 0")
+	}
+	if input.FiscalYear == 0 {
+		return submitForecastTransientInput{}, fmt.Errorf("fiscal year cannot be empty")
+	}
+	if input.Justification == "" {
+		return submitForecastTransientInput{}, fmt.Errorf("justification cannot be empty")
+	}
+
+	return input, nil
+}
+
+func getRequestAcquisitionTransientInput(ctx contractapi.TransactionContextInterface) (requestAcquisitionTransientInput, error) {
+	transientMap, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return requestAcquisitionTransientInput{}, fmt.Errorf("error getting transient: %w", err)
+	}
+
+	transientAcquisitionJson, ok := transientMap["acquisition"]
+	if !ok {
+		return requestAcquisitionTransientInput{}, fmt.Errorf("acquisition not found in transient map input")
+	}
+
+	var input requestAcquisitionTransientInput
+	if err = json.Unmarshal(transientAcquisitionJson, &input); err != nil {
+		return requestAcquisitionTransientInput{}, fmt.Errorf("error unmarshaling json: %w", err)
+	}
+
+	if strings.TrimSpace(input.Product) == "" {
+		return requestAcquisitionTransientInput{}, fmt.Errorf("product cannot be empty")
+	}
+	if input.Quantity <= 0 {
+		return requestAcquisitionTransientInput{}, fmt.Errorf("quantity must be greater than 0")
+	}
+	if input.Justification == "" {
+		return requestAcquisitionTransientInput{}, fmt.Errorf("justification cannot be empty")
+	}
+
+	return input, nil
+}
+
+func getAttachQuoteTransientInput(ctx contractapi.TransactionContextInterface) (attachQuoteTransientInput, error) {
+	transientMap, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return attachQuoteTransientInput{}, fmt.Errorf("error getting transient: %w", err)
+	}
+
+	transientQuoteJson, ok := transientMap["quote"]
+	if !ok {
+		return attachQuoteTransientInput{}, fmt.Errorf("quote not found in transient map input")
+	}
+
+	var input attachQuoteTransientInput
+	if err = json.Unmarshal(transientQuoteJson, &input); err != nil {
+		return attachQuoteTransientInput{}, fmt.Errorf("error unmarshaling json: %w", err)
+	}
+
+	if input.Vendor == "" {
+		return attachQuoteTransientInput{}, fmt.Errorf("vendor cannot be empty")
+	}
+	if err = model.ValidateDigest(input.Digest); err != nil {
+		return attachQuoteTransientInput{}, fmt.Errorf("invalid quote digest: %w", err)
+	}
+	if input.AmountCents < 0 {
+		return attachQuoteTransientInput{}, fmt.Errorf("quoted amount cannot be negative")
+	}
+
+	return input, nil
+}
+
+func getRecordPurchaseTransientInput(ctx contractapi.TransactionContextInterface) (recordPurchaseTransientInput, error) {
+	transientMap, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return recordPurchaseTransientInput{}, fmt.Errorf("error getting transient: %w", err)
+	}
+
+	transientPurchaseJson, ok := transientMap["purchase"]
+	if !ok {
+		return recordPurchaseTransientInput{}, fmt.Errorf("purchase not found in transient map input")
+	}
+
+	var input recordPurchaseTransientInput
+	if err = json.Unmarshal(transientPurchaseJson, &input); err != nil {
+		return recordPurchaseTransientInput{}, fmt.Errorf("error unmarshaling json: %w", err)
+	}
+
+	if input.OrderNumber == "" {
+		return recordPurchaseTransientInput{}, fmt.Errorf("order number cannot be empty")
+	}
+	if input.QuoteDigest == "" {
+		return recordPurchaseTransientInput{}, fmt.Errorf("quote digest cannot be empty")
+	}
+	if len(input.Licenses) == 0 {
+		return recordPurchaseTransientInput{}, fmt.Errorf("licenses cannot be empty")
+	}
+	if input.Cost != nil {
+		if err = input.Cost.Validate(); err != nil {
+			return recordPurchaseTransientInput{}, err
+		}
+	}
+
+	return input, nil
+}
+
+func getRegisterEncryptionKeyTransientInput(ctx contractapi.TransactionContextInterface) (registerEncryptionKeyTransientInput, error) {
+	transientMap, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return registerEncryptionKeyTransientInput{}, fmt.Errorf("error getting transient: %w", err)
+	}
+
+	transientKeyJson, ok := transientMap["encryption_key"]
+	if !ok {
+		return registerEncryptionKeyTransientInput{}, fmt.Errorf("encryption_key not found in transient map input")
+	}
+
+	var input registerEncryptionKeyTransientInput
+	if err = json.Unmarshal(transientKeyJson, &input); err != nil {
+		return registerEncryptionKeyTransientInput{}, fmt.Errorf("error unmarshaling json: %w", err)
+	}
+
+	if _, err = seal.ParsePublicKey(input.PublicKey); err != nil {
+		return registerEncryptionKeyTransientInput{}, fmt.Errorf("invalid encryption key: %w", err)
+	}
+
+	return input, nil
+}
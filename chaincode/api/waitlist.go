@@ -0,0 +1,264 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"github.com/usnistgov/blossom/chaincode/collections"
+	"github.com/usnistgov/blossom/chaincode/model"
+	"github.com/usnistgov/blossom/chaincode/ngac/pdp"
+)
+
+func (b *BlossomSmartContract) GetWaitlist(ctx contractapi.TransactionContextInterface, assetID string) (*model.Waitlist, error) {
+	if ok, err := b.assetExists(ctx, assetID); err != nil {
+		return nil, fmt.Errorf("error checking if asset exists: %w", err)
+	} else if !ok {
+		return nil, fmt.Errorf("an asset with the ID %q does not exist", assetID)
+	}
+
+	// ngac check
+	if err := pdp.CanViewAssetPublic(ctx); err != nil {
+		return nil, fmt.Errorf("ngac check failed: %w", err)
+	}
+
+	return getWaitlist(ctx, assetID)
+}
+
+// getWaitlist returns the waitlist of the asset from the catalog.  An asset without a waitlist has an empty waitlist.
+func getWaitlist(ctx contractapi.TransactionContextInterface, assetID string) (*model.Waitlist, error) {
+	bytes, err := ctx.GetStub().GetPrivateData(collections.Catalog(), model.WaitlistKey(assetID))
+	if err != nil {
+		return nil, fmt.Errorf("error getting waitlist of asset %s: %w", assetID, err)
+	}
+
+	waitlist := model.NewWaitlist(assetID)
+	if bytes == nil {
+		return waitlist, nil
+	}
+
+	if err = json.Unmarshal(bytes, waitlist); err != nil {
+		return nil, fmt.Errorf("error unmarshaling waitlist of asset %s: %w", assetID, err)
+	}
+
+	return waitlist, nil
+}
+
+func putWaitlist(ctx contractapi.TransactionContextInterface, waitlist *model.Waitlist) error {
+	bytes, err := json.Marshal(waitlist)
+	if err != nil {
+		return fmt.Errorf("error marshaling waitlist of asset %s: %w", waitlist.Asset, err)
+	}
+
+	if err = ctx.GetStub().PutPrivateData(collections.Catalog(), model.WaitlistKey(waitlist.Asset), bytes); err != nil {
+		return fmt.Errorf("error updating waitlist of asset %s: %w", waitlist.Asset, err)
+	}
+
+	return nil
+}
+
+// removeFromWaitlist removes the request with the given key from the waitlist of the asset.
+func removeFromWaitlist(ctx contractapi.TransactionContextInterface, assetID, requestKey string) error {
+	waitlist, err := getWaitlist(ctx, assetID)
+	if err != nil {
+		return err
+	}
+
+	if !waitlist.Remove(requestKey) {
+		return nil
+	}
+
+	return putWaitlist(ctx, waitlist)
+}
+
+// waitlistCheckout adds an approved request to the waitlist of the asset and then offers the available licenses to
+// the waitlist, which checks the licenses out for the request right away if it is at the head of the waitlist and
+// enough licenses are available.
+func waitlistCheckout(ctx contractapi.TransactionContextInterface, input approveCheckoutTransientInput, key string, req *CheckoutRequest,
+	amount int, acctPvt *model.AccountPrivate, assetPub *model.AssetPublic, assetPvt *model.AssetPrivate, waitlist *model.Waitlist,
+	now time.Time) error {
+	if err := putCheckoutRequest(ctx, input.Account, key, req, CheckoutWaitlisted, 0, input.Reason); err != nil {
+		return fmt.Errorf("error updating request: %w", err)
+	}
+
+	waitlist.Add(model.WaitlistEntry{
+		Account:    input.Account,
+		RequestKey: key,
+		Amount:     amount,
+		Priority:   input.Priority,
+		AddedAt:    now,
+	})
+
+	accounts := map[string]*model.AccountPrivate{input.Account: acctPvt}
+	requests := map[string]*CheckoutRequest{key: req}
+	fulfilled, err := fulfillWaitlist(ctx, "ApproveCheckout", assetPub, assetPvt, waitlist, accounts, requests)
+	if err != nil {
+		return err
+	}
+
+	if err = putWaitlist(ctx, waitlist); err != nil {
+		return err
+	}
+
+	if len(fulfilled) == 0 {
+		return nil
+	}
+
+	if _, ok := fulfilled[input.Account]; ok {
+		if err = sealLicenseKeys(ctx, acctPvt, assetPub.ID, assetPvt); err != nil {
+			return err
+		}
+
+		if err = putAccountPrivate(ctx, input.Account, "ApproveCheckout", acctPvt); err != nil {
+			return err
+		}
+	}
+
+	return putAsset(ctx, "ApproveCheckout", assetPub, assetPvt)
+}
+
+// offerToWaitlist offers the available licenses of the asset to the requests on its waitlist.  The accounts given
+// were loaded by the caller and must be written by the caller along with the asset.
+func offerToWaitlist(ctx contractapi.TransactionContextInterface, operation string, assetPub *model.AssetPublic,
+	assetPvt *model.AssetPrivate, accounts map[string]*model.AccountPrivate) error {
+	waitlist, err := getWaitlist(ctx, assetPub.ID)
+	if err != nil {
+		return err
+	}
+
+	waiting := len(waitlist.Entries)
+	if waiting == 0 {
+		return nil
+	}
+
+	if _, err = fulfillWaitlist(ctx, operation, assetPub, assetPvt, waitlist, accounts, make(map[string]*CheckoutRequest)); err != nil {
+		return err
+	}
+
+	if len(waitlist.Entries) == waiting {
+		return nil
+	}
+
+	return putWaitlist(ctx, waitlist)
+}
+
+// fulfillWaitlist checks out the available licenses of the asset for the requests on its waitlist in order.  Requests
+// are fulfilled while the request at the head of the waitlist can be checked out in full, a request is never passed
+// over for a smaller request behind it.  Entries for requests that are no longer waitlisted (i.e. cancelled by the
+// account) are dropped.  Entries of accounts that are not authorized to receive licenses are skipped and kept on the
+// waitlist in their place.  Each fulfilled request is marked as approved and removed from the waitlist.
+//
+// The accounts and requests given were loaded by the caller, which writes the accounts.  Any other account that is
+// offered licenses is loaded and written here.  The caller writes the waitlist and the asset.  Returns the set of
+// accounts that were offered licenses.
+func fulfillWaitlist(ctx contractapi.TransactionContextInterface, operation string, assetPub *model.AssetPublic,
+	assetPvt *model.AssetPrivate, waitlist *model.Waitlist, accounts map[string]*model.AccountPrivate,
+	requests map[string]*CheckoutRequest) (map[string]struct{}, error) {
+	now, err := txTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fulfilled := make(map[string]struct{})
+	loaded := make([]string, 0)
+	skipped := make([]model.WaitlistEntry, 0)
+
+	// the waitlist waits while the asset is not active
+	if assetPub.StatusAt(now) != model.AssetActive {
+		return fulfilled, nil
+	}
+
+	for len(waitlist.Entries) > 0 {
+		entry := waitlist.Entries[0]
+
+		req, ok := requests[entry.RequestKey]
+		if !ok {
+			if req, err = getCheckoutRequest(ctx, entry.Account, entry.RequestKey); err != nil {
+				return nil, err
+			}
+		}
+
+		if req == nil || req.Status != CheckoutWaitlisted {
+			waitlist.Entries = waitlist.Entries[1:]
+			continue
+		}
+
+		// the account's status may have changed since it joined the waitlist (i.e. an ATO lapse or a security risk)
+		if err = checkCanReceiveLicenses(ctx, entry.Account); err != nil {
+			skipped = append(skipped, entry)
+			waitlist.Entries = waitlist.Entries[1:]
+			continue
+		}
+
+		if unexpiredAvailable(assetPvt, now) < entry.Amount {
+			break
+		}
+
+		acctPvt, ok := accounts[entry.Account]
+		if !ok {
+			if acctPvt, err = getAccountPrivate(ctx, entry.Account); err != nil {
+				return nil, err
+			}
+
+			accounts[entry.Account] = acctPvt
+			loaded = append(loaded, entry.Account)
+		}
+
+		if req.Allocation, err = checkout(assetPub, assetPvt, entry.Account, acctPvt, entry.Amount, now, req.LeaseTermDays); err != nil {
+			return nil, fmt.Errorf("error checking out %s for account %s: %w", assetPub.ID, entry.Account, err)
+		}
+
+		if err = putCheckoutRequest(ctx, entry.Account, entry.RequestKey, req, approvalStatus(req, entry.Amount), entry.Amount,
+			"licenses offered from the waitlist"); err != nil {
+			return nil, fmt.Errorf("error updating request: %w", err)
+		}
+
+		waitlist.Entries = waitlist.Entries[1:]
+		fulfilled[entry.Account] = struct{}{}
+	}
+
+	waitlist.Entries = append(skipped, waitlist.Entries...)
+
+	// accounts are loaded in waitlist order so they are written in the same order on every peer
+	for _, account := range loaded {
+		if err = sealLicenseKeys(ctx, accounts[account], assetPub.ID, assetPvt); err != nil {
+			return nil, err
+		}
+
+		if err = putAccountPrivate(ctx, account, operation, accounts[account]); err != nil {
+			return nil, err
+		}
+	}
+
+	return fulfilled, nil
+}
+
+// getCheckoutRequest returns the checkout request of the account with the given key or nil if it does not exist.
+func getCheckoutRequest(ctx contractapi.TransactionContextInterface, account, key string) (*CheckoutRequest, error) {
+	bytes, err := ctx.GetStub().GetPrivateData(collections.Account(account), key)
+	if err != nil {
+		return nil, fmt.Errorf("error getting request %s: %w", key, err)
+	} else if bytes == nil {
+		return nil, nil
+	}
+
+	req := &CheckoutRequest{}
+	if err = json.Unmarshal(bytes, req); err != nil {
+		return nil, fmt.Errorf("error unmarshaling request: %w", err)
+	}
+
+	return req, nil
+}
+
+// unexpiredAvailable returns the number of available licenses of the asset that have not expired as of the given time.
+func unexpiredAvailable(assetPvt *model.AssetPrivate, now time.Time) int {
+	count := 0
+	for _, license := range assetPvt.AvailableLicenses {
+		if !model.IsLicenseExpired(assetPvt.Licenses[license], now) {
+			count++
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,336 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/usnistgov/blossom/chaincode/mocks"
+	"github.com/usnistgov/blossom/chaincode/model"
+)
+
+func TestWaitlist(t *testing.T) {
+	ctx := newTestStub(t)
+	bcc := BlossomSmartContract{}
+
+	onboardTestAsset(t, ctx, "123", "myasset", []string{"1", "2", "3"})
+	requestTestAccount(t, ctx, Org2MSP)
+	requestTestAccount(t, ctx, Org3MSP)
+
+	checkoutTestAsset(t, ctx, Org2MSP, "123", 3)
+
+	request := func(account string, amount int) {
+		var err error
+		if account == Org2MSP {
+			err = ctx.SetClientIdentity(mocks.Org2SystemAdmin)
+		} else {
+			err = ctx.SetClientIdentity(mocks.Org3SystemAdmin)
+		}
+		require.NoError(t, err)
+		err = ctx.SetTransient("checkout", testCheckoutInput("123", amount))
+		require.NoError(t, err)
+		err = bcc.RequestCheckout(ctx)
+		require.NoError(t, err)
+	}
+
+	approve := func(account string, priority int) {
+		err := ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		err = ctx.SetTransient("checkout", approveCheckoutTransientInput{Account: account, AssetID: "123", Priority: priority})
+		require.NoError(t, err)
+		err = bcc.ApproveCheckout(ctx)
+		require.NoError(t, err)
+	}
+
+	checkin := func(licenses ...string) {
+		err := ctx.SetClientIdentity(mocks.Org2SystemAdmin)
+		require.NoError(t, err)
+		err = ctx.SetTransient("checkin", initiateCheckinTransientInput{AssetID: "123", Licenses: licenses})
+		require.NoError(t, err)
+		err = bcc.InitiateCheckin(ctx)
+		require.NoError(t, err)
+
+		err = ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		err = ctx.SetTransient("checkin", processCheckinTransientInput{Account: Org2MSP, AssetID: "123"})
+		require.NoError(t, err)
+		err = bcc.ProcessCheckin(ctx)
+		require.NoError(t, err)
+	}
+
+	lastRequest := func(account string) CheckoutRequest {
+		reqs, err := bcc.GetCheckoutRequests(ctx, account)
+		require.NoError(t, err)
+		return reqs[len(reqs)-1]
+	}
+
+	waitlist := func() []model.WaitlistEntry {
+		err := ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		w, err := bcc.GetWaitlist(ctx, "123")
+		require.NoError(t, err)
+		return w.Entries
+	}
+
+	t.Run("test approve oversubscribed asset joins waitlist", func(t *testing.T) {
+		request(Org3MSP, 2)
+		approve(Org3MSP, 0)
+
+		require.Equal(t, CheckoutWaitlisted, lastRequest(Org3MSP).Status)
+
+		entries := waitlist()
+		require.Len(t, entries, 1)
+		require.Equal(t, Org3MSP, entries[0].Account)
+		require.Equal(t, 2, entries[0].Amount)
+
+		// a waitlisted request cannot be approved again or duplicated
+		err := ctx.SetTransient("checkout", approveCheckoutTransientInput{Account: Org3MSP, AssetID: "123"})
+		require.NoError(t, err)
+		err = bcc.ApproveCheckout(ctx)
+		require.Error(t, err)
+
+		err = ctx.SetClientIdentity(mocks.Org3SystemAdmin)
+		require.NoError(t, err)
+		err = ctx.SetTransient("checkout", testCheckoutInput("123", 1))
+		require.NoError(t, err)
+		err = bcc.RequestCheckout(ctx)
+		require.Error(t, err)
+	})
+
+	t.Run("test head of waitlist is not passed over", func(t *testing.T) {
+		// one license is not enough for the request at the head of the waitlist
+		checkin("1")
+
+		require.Equal(t, CheckoutWaitlisted, lastRequest(Org3MSP).Status)
+		require.Len(t, waitlist(), 1)
+
+		asset, err := bcc.GetAsset(ctx, "123")
+		require.NoError(t, err)
+		require.Equal(t, 1, asset.Available)
+	})
+
+	t.Run("test checkin offers licenses to head of waitlist", func(t *testing.T) {
+		checkin("2")
+
+		req := lastRequest(Org3MSP)
+		require.Equal(t, CheckoutApproved, req.Status)
+		require.Equal(t, 2, req.Approved)
+		require.Len(t, waitlist(), 0)
+
+		err := ctx.SetClientIdentity(mocks.Org3SystemAdmin)
+		require.NoError(t, err)
+		licenses, err := bcc.GetLicenses(ctx, Org3MSP, "123")
+		require.NoError(t, err)
+		require.Len(t, licenses, 2)
+
+		err = ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		asset, err := bcc.GetAsset(ctx, "123")
+		require.NoError(t, err)
+		require.Equal(t, 0, asset.Available)
+		require.Len(t, asset.CheckedOut[Org3MSP], 2)
+	})
+
+	t.Run("test priority orders waitlist", func(t *testing.T) {
+		request(Org3MSP, 1)
+		approve(Org3MSP, 0)
+		request(Org2MSP, 1)
+		approve(Org2MSP, 10)
+
+		entries := waitlist()
+		require.Len(t, entries, 2)
+		require.Equal(t, Org2MSP, entries[0].Account)
+		require.Equal(t, Org3MSP, entries[1].Account)
+
+		checkin("3")
+
+		require.Equal(t, CheckoutApproved, lastRequest(Org2MSP).Status)
+		require.Equal(t, CheckoutWaitlisted, lastRequest(Org3MSP).Status)
+		require.Len(t, waitlist(), 1)
+	})
+
+	t.Run("test offboard blocked by waitlist", func(t *testing.T) {
+		err := ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		err = bcc.OffboardAsset(ctx, "123")
+		require.Error(t, err)
+	})
+
+	t.Run("test cancelled request is dropped from waitlist", func(t *testing.T) {
+		err := ctx.SetClientIdentity(mocks.Org3SystemAdmin)
+		require.NoError(t, err)
+		err = ctx.SetTransient("checkout", cancelCheckoutTransientInput{AssetID: "123", Reason: "no longer needed"})
+		require.NoError(t, err)
+		err = bcc.CancelCheckout(ctx)
+		require.NoError(t, err)
+
+		// the account cannot write to the catalog so the entry is dropped when licenses are next offered
+		require.Len(t, waitlist(), 1)
+
+		request(Org2MSP, 1)
+		approve(Org2MSP, 0)
+
+		err = ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		err = ctx.SetTransient("licenses", addLicensesTransientInput{Licenses: []model.License{{LicenseID: "4"}}})
+		require.NoError(t, err)
+		err = bcc.AddLicenses(ctx, "123")
+		require.NoError(t, err)
+
+		require.Equal(t, CheckoutCancelled, lastRequest(Org3MSP).Status)
+		require.Equal(t, CheckoutApproved, lastRequest(Org2MSP).Status)
+		require.Len(t, waitlist(), 0)
+	})
+
+	t.Run("test deny removes request from waitlist", func(t *testing.T) {
+		request(Org3MSP, 1)
+		approve(Org3MSP, 0)
+		require.Len(t, waitlist(), 1)
+
+		err := ctx.SetTransient("checkout", denyCheckoutTransientInput{Account: Org3MSP, AssetID: "123", Reason: "denied"})
+		require.NoError(t, err)
+		err = bcc.DenyCheckout(ctx)
+		require.NoError(t, err)
+
+		require.Equal(t, CheckoutDenied, lastRequest(Org3MSP).Status)
+		require.Len(t, waitlist(), 0)
+	})
+}
+
+func TestOptOutOffersReclaimedLicenses(t *testing.T) {
+	ctx := newTestStub(t)
+	bcc := BlossomSmartContract{}
+
+	onboardTestAsset(t, ctx, "123", "myasset", []string{"1", "2"})
+	requestTestAccount(t, ctx, Org2MSP)
+	requestTestAccount(t, ctx, Org3MSP)
+
+	checkoutTestAsset(t, ctx, Org2MSP, "123", 2)
+	checkoutTestAsset(t, ctx, Org3MSP, "123", 1)
+
+	err := ctx.SetClientIdentity(mocks.Super)
+	require.NoError(t, err)
+	w, err := bcc.GetWaitlist(ctx, "123")
+	require.NoError(t, err)
+	require.Len(t, w.Entries, 1)
+
+	err = bcc.UpdateAccountStatus(ctx, Org2MSP, "UNAUTHORIZED_OPTOUT", "opting out")
+	require.NoError(t, err)
+
+	w, err = bcc.GetWaitlist(ctx, "123")
+	require.NoError(t, err)
+	require.Len(t, w.Entries, 0)
+
+	reqs, err := bcc.GetCheckoutRequests(ctx, Org3MSP)
+	require.NoError(t, err)
+	require.Equal(t, CheckoutApproved, reqs[0].Status)
+
+	asset, err := bcc.GetAsset(ctx, "123")
+	require.NoError(t, err)
+	require.Equal(t, 1, asset.Available)
+	require.Len(t, asset.CheckedOut[Org3MSP], 1)
+	require.NotContains(t, asset.CheckedOut, Org2MSP)
+}
+
+func TestWaitlistSkipsUnauthorizedAccounts(t *testing.T) {
+	ctx := newTestStub(t)
+	bcc := BlossomSmartContract{}
+
+	onboardTestAsset(t, ctx, "123", "myasset", []string{"1", "2"})
+	requestTestAccount(t, ctx, Org2MSP)
+	requestTestAccount(t, ctx, Org3MSP)
+
+	checkoutTestAsset(t, ctx, Org2MSP, "123", 2)
+	checkoutTestAsset(t, ctx, Org3MSP, "123", 1)
+
+	checkin := func(license string) {
+		err := ctx.SetClientIdentity(mocks.Org2SystemAdmin)
+		require.NoError(t, err)
+		err = ctx.SetTransient("checkin", initiateCheckinTransientInput{AssetID: "123", Licenses: []string{license}})
+		require.NoError(t, err)
+		err = bcc.InitiateCheckin(ctx)
+		require.NoError(t, err)
+
+		err = ctx.SetClientIdentity(mocks.Super)
+		require.NoError(t, err)
+		err = ctx.SetTransient("checkin", processCheckinTransientInput{Account: Org2MSP, AssetID: "123"})
+		require.NoError(t, err)
+		err = bcc.ProcessCheckin(ctx)
+		require.NoError(t, err)
+	}
+
+	err := ctx.SetClientIdentity(mocks.Super)
+	require.NoError(t, err)
+	err = bcc.UpdateAccountStatus(ctx, Org3MSP, "UNAUTHORIZED_SECURITY_RISK", "security incident")
+	require.NoError(t, err)
+
+	checkin("1")
+
+	w, err := bcc.GetWaitlist(ctx, "123")
+	require.NoError(t, err)
+	require.Len(t, w.Entries, 1)
+
+	reqs, err := bcc.GetCheckoutRequests(ctx, Org3MSP)
+	require.NoError(t, err)
+	require.Equal(t, CheckoutWaitlisted, reqs[0].Status)
+
+	asset, err := bcc.GetAsset(ctx, "123")
+	require.NoError(t, err)
+	require.Equal(t, 1, asset.Available)
+	require.NotContains(t, asset.CheckedOut, Org3MSP)
+
+	// once the account is authorized again its request is offered licenses from its place on the waitlist
+	err = bcc.UpdateAccountStatus(ctx, Org3MSP, "PENDING_ATO", "incident resolved")
+	require.NoError(t, err)
+	err = bcc.UpdateAccountStatus(ctx, Org3MSP, "AUTHORIZED", "ato reviewed")
+	require.NoError(t, err)
+
+	checkin("2")
+
+	reqs, err = bcc.GetCheckoutRequests(ctx, Org3MSP)
+	require.NoError(t, err)
+	require.Equal(t, CheckoutApproved, reqs[0].Status)
+
+	asset, err = bcc.GetAsset(ctx, "123")
+	require.NoError(t, err)
+	require.Len(t, asset.CheckedOut[Org3MSP], 1)
+}
+
+func TestOffboardAssetWithCancelledWaitlistEntry(t *testing.T) {
+	ctx := newTestStub(t)
+	bcc := BlossomSmartContract{}
+
+	onboardTestAsset(t, ctx, "123", "myasset", []string{"1"})
+	requestTestAccount(t, ctx, Org3MSP)
+
+	// the request waits for more licenses than the asset has
+	require.NoError(t, ctx.SetClientIdentity(mocks.Org3SystemAdmin))
+	require.NoError(t, ctx.SetTransient("checkout", testCheckoutInput("123", 2)))
+	require.NoError(t, bcc.RequestCheckout(ctx))
+
+	require.NoError(t, ctx.SetClientIdentity(mocks.Super))
+	require.NoError(t, ctx.SetTransient("checkout", approveCheckoutTransientInput{Account: Org3MSP, AssetID: "123"}))
+	require.NoError(t, bcc.ApproveCheckout(ctx))
+
+	t.Run("test waitlisted request blocks offboard", func(t *testing.T) {
+		err := bcc.OffboardAsset(ctx, "123")
+		require.EqualError(t, err, "asset 123 still has requests on its waitlist")
+	})
+
+	require.NoError(t, ctx.SetClientIdentity(mocks.Org3SystemAdmin))
+	require.NoError(t, ctx.SetTransient("checkout", cancelCheckoutTransientInput{AssetID: "123", Reason: "no longer needed"}))
+	require.NoError(t, bcc.CancelCheckout(ctx))
+
+	t.Run("test cancelled request does not block offboard", func(t *testing.T) {
+		require.NoError(t, ctx.SetClientIdentity(mocks.Super))
+		w, err := bcc.GetWaitlist(ctx, "123")
+		require.NoError(t, err)
+		require.Len(t, w.Entries, 1)
+
+		err = bcc.OffboardAsset(ctx, "123")
+		require.NoError(t, err)
+
+		ok, err := bcc.assetExists(ctx, "123")
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+}
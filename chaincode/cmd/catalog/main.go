@@ -0,0 +1,286 @@
+// Command catalog imports license manifests into the Blossom catalog and exports the catalog to CSV.
+//
+// The import command validates a CSV or JSON license manifest and onboards the asset in chunks, with OnboardAsset for
+// the first chunk and AddLicenses for the rest.  Licenses already on the asset are skipped, so an import that fails
+// part way is resumed by running the same command again.  The export command writes one row for every license of
+// every asset, with the account holding it, for reconciliation with vendor statements.
+//
+// Transactions are submitted with the peer CLI, configured with the CORE_PEER_* environment variables as in
+// scripts/demo.sh.  Both commands must be run as the Blossom admin.
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/usnistgov/blossom/chaincode/model"
+)
+
+const usage = `usage:
+  catalog import -asset <id> -name <name> -onboard <date> -expiration <date> -manifest <file.csv|file.json> [flags]
+  catalog export [-out <file.csv>] [flags]`
+
+func main() {
+	log.SetFlags(0)
+
+	if len(os.Args) < 2 {
+		log.Fatal(usage)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "import":
+		err = runImport(os.Args[2:])
+	case "export":
+		err = runExport(os.Args[2:])
+	default:
+		log.Fatal(usage)
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// peerFlags adds the flags used to reach the orderer and chaincode to the flag set.
+func peerFlags(fs *flag.FlagSet) *peer {
+	p := &peer{}
+	fs.StringVar(&p.bin, "peer", "peer", "path of the peer CLI")
+	fs.StringVar(&p.orderer, "orderer", "localhost:7050", "orderer address")
+	fs.StringVar(&p.hostOverride, "orderer-host", "orderer.example.com", "orderer TLS host name override")
+	fs.StringVar(&p.caFile, "cafile", os.Getenv("ORDERER_CA"), "orderer TLS CA certificate, defaults to $ORDERER_CA")
+	fs.StringVar(&p.channel, "channel", "mychannel", "channel name")
+	fs.StringVar(&p.chaincode, "chaincode", "blossomcc", "chaincode name")
+	return p
+}
+
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	p := peerFlags(fs)
+	assetID := fs.String("asset", "", "ID of the asset")
+	name := fs.String("name", "", "name of the asset")
+	onboard := fs.String("onboard", "", "RFC 3339 onboarding date of the asset")
+	expiration := fs.String("expiration", "", "RFC 3339 expiration of the asset")
+	manifest := fs.String("manifest", "", "CSV or JSON license manifest")
+	allocation := fs.String("allocation", "", "allocation strategy of the asset, defaults to POOL_ORDER")
+	chunkSize := fs.Int("chunk", 500, "number of licenses submitted per transaction")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *assetID == "" || *name == "" || *manifest == "" {
+		return fmt.Errorf("-asset, -name and -manifest are required\n%s", usage)
+	} else if p.caFile == "" {
+		return fmt.Errorf("-cafile or $ORDERER_CA is required to submit transactions")
+	} else if *chunkSize <= 0 {
+		return fmt.Errorf("-chunk must be greater than 0")
+	}
+
+	asset := &model.AssetPublic{
+		ID:                 *assetID,
+		Name:               *name,
+		AllocationStrategy: model.AllocationStrategy(*allocation),
+	}
+
+	var err error
+	if asset.OnboardingDate, err = model.ParseDate(*onboard); err != nil {
+		return fmt.Errorf("invalid onboarding date: %w", err)
+	} else if asset.Expiration, err = model.ParseDate(*expiration); err != nil {
+		return fmt.Errorf("invalid expiration: %w", err)
+	} else if err = model.ValidateAllocationStrategy(asset.AllocationStrategy); err != nil {
+		return err
+	}
+
+	licenses, err := readManifest(*manifest)
+	if err != nil {
+		return err
+	}
+
+	if err = validateManifest(asset, licenses); err != nil {
+		return fmt.Errorf("invalid manifest: %w", err)
+	}
+
+	existing, err := getAsset(p, asset.ID)
+	if err != nil {
+		return err
+	}
+
+	// resume an earlier import by skipping the licenses already on the asset
+	remaining := licenses
+	if existing != nil {
+		if existing.Name != asset.Name || !existing.OnboardingDate.Equal(asset.OnboardingDate) ||
+			!existing.Expiration.Equal(asset.Expiration) {
+			return fmt.Errorf("asset %s already exists with a different name or dates", asset.ID)
+		}
+
+		remaining = make([]model.License, 0)
+		for _, license := range licenses {
+			if _, ok := existing.Licenses[license.LicenseID]; !ok {
+				remaining = append(remaining, license)
+			}
+		}
+
+		log.Printf("asset %s exists, %d of %d licenses remain to be imported", asset.ID, len(remaining), len(licenses))
+	}
+
+	chunks := chunkLicenses(remaining, *chunkSize)
+	submitted := len(licenses) - len(remaining)
+	for i, chunk := range chunks {
+		if existing == nil && i == 0 {
+			err = p.invoke(
+				[]string{"OnboardAsset", asset.ID, asset.Name, *onboard, *expiration},
+				map[string]interface{}{"asset": onboardAssetInput{Licenses: chunk, AllocationStrategy: asset.AllocationStrategy}},
+			)
+		} else {
+			err = p.invoke(
+				[]string{"AddLicenses", asset.ID},
+				map[string]interface{}{"licenses": addLicensesInput{Licenses: chunk}},
+			)
+		}
+		if err != nil {
+			return fmt.Errorf("chunk %d of %d failed, run the import again to resume: %w", i+1, len(chunks), err)
+		}
+
+		submitted += len(chunk)
+		log.Printf("imported %d of %d licenses of asset %s", submitted, len(licenses), asset.ID)
+	}
+
+	return nil
+}
+
+// onboardAssetInput is the transient input of OnboardAsset.
+type onboardAssetInput struct {
+	Licenses           []model.License          `json:"licenses"`
+	AllocationStrategy model.AllocationStrategy `json:"allocation_strategy,omitempty"`
+}
+
+// addLicensesInput is the transient input of AddLicenses.
+type addLicensesInput struct {
+	Licenses []model.License `json:"licenses"`
+}
+
+// getAsset returns the asset, or nil if it does not exist.
+func getAsset(p *peer, assetID string) (*model.Asset, error) {
+	bytes, err := p.query("GetAsset", assetID)
+	if err != nil {
+		if strings.Contains(err.Error(), "does not exist") {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("error getting asset %s: %w", assetID, err)
+	}
+
+	asset := model.NewAsset()
+	if err = json.Unmarshal(bytes, asset); err != nil {
+		return nil, fmt.Errorf("error unmarshaling asset %s: %w", assetID, err)
+	}
+
+	return asset, nil
+}
+
+// exportColumns are the columns of the catalog export.  Account and lease_end are empty for available licenses.
+var exportColumns = []string{"asset_id", "asset_name", "status", "onboarding_date", "asset_expiration", "license_id",
+	"license_expiration", "account", "lease_end"}
+
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	p := peerFlags(fs)
+	out := fs.String("out", "", "CSV file to write, defaults to stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	bytes, err := p.query("GetAssets")
+	if err != nil {
+		return fmt.Errorf("error getting assets: %w", err)
+	}
+
+	assets := make([]*model.AssetPublic, 0)
+	if err = json.Unmarshal(bytes, &assets); err != nil {
+		return fmt.Errorf("error unmarshaling assets: %w", err)
+	}
+
+	sort.Slice(assets, func(i, j int) bool {
+		return assets[i].ID < assets[j].ID
+	})
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			return fmt.Errorf("error creating %s: %w", *out, err)
+		}
+		defer file.Close()
+
+		w = file
+	}
+
+	writer := csv.NewWriter(w)
+	if err = writer.Write(exportColumns); err != nil {
+		return err
+	}
+
+	for _, assetPub := range assets {
+		asset, err := getAsset(p, assetPub.ID)
+		if err != nil {
+			return err
+		} else if asset == nil {
+			// offboarded since the catalog was read
+			continue
+		}
+
+		if err = writer.WriteAll(exportRows(assetPub, asset)); err != nil {
+			return fmt.Errorf("error writing asset %s: %w", asset.ID, err)
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// exportRows returns a row for each license of the asset in license ID order, or a single row without a license if
+// the asset has no licenses.
+func exportRows(assetPub *model.AssetPublic, asset *model.Asset) [][]string {
+	holders := make(map[string]string)
+	for account, leases := range asset.CheckedOut {
+		for license := range leases {
+			holders[license] = account
+		}
+	}
+
+	ids := make([]string, 0, len(asset.Licenses))
+	for id := range asset.Licenses {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	row := func(license string) []string {
+		return []string{asset.ID, asset.Name, string(assetPub.Status), formatDate(asset.OnboardingDate),
+			formatDate(asset.Expiration), license, "", "", ""}
+	}
+
+	if len(ids) == 0 {
+		return [][]string{row("")}
+	}
+
+	rows := make([][]string, 0, len(ids))
+	for _, id := range ids {
+		r := row(id)
+		r[6] = formatDate(asset.Licenses[id])
+		if account, ok := holders[id]; ok {
+			r[7] = account
+			r[8] = formatDate(asset.CheckedOut[account][id].LeaseEnd)
+		}
+
+		rows = append(rows, r)
+	}
+
+	return rows
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/usnistgov/blossom/chaincode/model"
+)
+
+// manifestColumns are the columns of a CSV license manifest.  Only license_id is required.
+var manifestColumns = []string{"license_id", "expiration", "batch", "key"}
+
+// readManifest reads the licenses in a CSV or JSON license manifest.  The format is chosen by the file extension.
+func readManifest(path string) ([]model.License, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening manifest: %w", err)
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return readCSVManifest(file)
+	case ".json":
+		return readJSONManifest(file)
+	default:
+		return nil, fmt.Errorf("manifest %s must be a .csv or .json file", path)
+	}
+}
+
+// readCSVManifest reads a CSV manifest with a header row naming the license_id, expiration, batch and key columns, in
+// any order.  Expirations are RFC 3339 dates and can be empty for licenses that expire with the asset.
+func readCSVManifest(r io.Reader) ([]model.License, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("error reading manifest header: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	if _, ok := columns["license_id"]; !ok {
+		return nil, fmt.Errorf("manifest header must have a license_id column, columns are %s", strings.Join(manifestColumns, ","))
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+
+		return ""
+	}
+
+	licenses := make([]model.License, 0)
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("error reading manifest: %w", err)
+		}
+
+		license := model.License{
+			LicenseID: field(record, "license_id"),
+			Batch:     field(record, "batch"),
+			Key:       field(record, "key"),
+		}
+
+		if exp := field(record, "expiration"); exp != "" {
+			if license.Expiration, err = model.ParseDate(exp); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+		}
+
+		licenses = append(licenses, license)
+	}
+
+	return licenses, nil
+}
+
+// readJSONManifest reads a JSON manifest in the format of the OnboardAsset transient input, {"licenses":[...]}, or a
+// bare array of licenses.
+func readJSONManifest(r io.Reader) ([]model.License, error) {
+	bytes, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("error reading manifest: %w", err)
+	}
+
+	licenses := make([]model.License, 0)
+	if strings.HasPrefix(strings.TrimSpace(string(bytes)), "[") {
+		err = json.Unmarshal(bytes, &licenses)
+	} else {
+		input := struct {
+			Licenses []model.License `json:"licenses"`
+		}{}
+		err = json.Unmarshal(bytes, &input)
+		licenses = input.Licenses
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling manifest: %w", err)
+	}
+
+	return licenses, nil
+}
+
+// validateManifest checks that every license has a unique ID and an expiration the asset accepts, so a bad manifest
+// is rejected before anything is submitted.
+func validateManifest(asset *model.AssetPublic, licenses []model.License) error {
+	if len(licenses) == 0 {
+		return fmt.Errorf("manifest does not have any licenses")
+	}
+
+	if err := asset.ValidateDates(); err != nil {
+		return err
+	}
+
+	seen := make(map[string]int)
+	for i, license := range licenses {
+		if license.LicenseID == "" {
+			return fmt.Errorf("license %d does not have an ID", i+1)
+		} else if prev, ok := seen[license.LicenseID]; ok {
+			return fmt.Errorf("license %d has the same ID %s as license %d", i+1, license.LicenseID, prev)
+		}
+
+		seen[license.LicenseID] = i + 1
+
+		if license.Expiration.IsZero() {
+			continue
+		}
+
+		if err := asset.ValidateLicenseExpiration(license.LicenseID, license.Expiration); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// chunkLicenses splits the licenses into chunks of at most size licenses, keeping their order.
+func chunkLicenses(licenses []model.License, size int) [][]model.License {
+	chunks := make([][]model.License, 0)
+	for len(licenses) > size {
+		chunks = append(chunks, licenses[:size])
+		licenses = licenses[size:]
+	}
+
+	if len(licenses) > 0 {
+		chunks = append(chunks, licenses)
+	}
+
+	return chunks
+}
+
+// formatDate formats a date for a CSV export, leaving unset dates empty.
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
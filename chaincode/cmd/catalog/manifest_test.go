@@ -0,0 +1,81 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/usnistgov/blossom/chaincode/model"
+)
+
+func TestManifest(t *testing.T) {
+	asset := &model.AssetPublic{
+		ID:             "101",
+		OnboardingDate: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		Expiration:     time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	t.Run("test read csv manifest", func(t *testing.T) {
+		licenses, err := readCSVManifest(strings.NewReader("batch,license_id,expiration,key\nPO-1,l1,2025-01-01T00:00:00Z,K1\n,l2,,\n"))
+		require.NoError(t, err)
+		require.Equal(t, []model.License{
+			{LicenseID: "l1", Expiration: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Batch: "PO-1", Key: "K1"},
+			{LicenseID: "l2"},
+		}, licenses)
+		require.NoError(t, validateManifest(asset, licenses))
+
+		_, err = readCSVManifest(strings.NewReader("license_id,expiration\nl1,01/01/2025\n"))
+		require.Error(t, err)
+
+		_, err = readCSVManifest(strings.NewReader("id,expiration\nl1,\n"))
+		require.Error(t, err)
+	})
+
+	t.Run("test read json manifest", func(t *testing.T) {
+		licenses, err := readJSONManifest(strings.NewReader(`{"licenses":[{"license_id":"l1","expiration":"2025-01-01T00:00:00Z"}]}`))
+		require.NoError(t, err)
+		require.Len(t, licenses, 1)
+
+		licenses, err = readJSONManifest(strings.NewReader(`[{"license_id":"l1"},{"license_id":"l2"}]`))
+		require.NoError(t, err)
+		require.Len(t, licenses, 2)
+	})
+
+	t.Run("test validate manifest", func(t *testing.T) {
+		require.Error(t, validateManifest(asset, []model.License{}))
+		require.Error(t, validateManifest(asset, []model.License{{LicenseID: ""}}))
+		require.Error(t, validateManifest(asset, []model.License{{LicenseID: "l1"}, {LicenseID: "l1"}}))
+		require.Error(t, validateManifest(asset, []model.License{
+			{LicenseID: "l1", Expiration: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		}))
+	})
+
+	t.Run("test chunk licenses", func(t *testing.T) {
+		licenses := []model.License{{LicenseID: "1"}, {LicenseID: "2"}, {LicenseID: "3"}, {LicenseID: "4"}, {LicenseID: "5"}}
+		chunks := chunkLicenses(licenses, 2)
+		require.Len(t, chunks, 3)
+		require.Equal(t, []model.License{{LicenseID: "5"}}, chunks[2])
+		require.Len(t, chunkLicenses(licenses, 5), 1)
+	})
+
+	t.Run("test export rows", func(t *testing.T) {
+		exp := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		a := &model.Asset{
+			ID:             "101",
+			Name:           "asset1",
+			OnboardingDate: asset.OnboardingDate,
+			Expiration:     asset.Expiration,
+			Licenses:       map[string]time.Time{"l2": exp, "l1": exp},
+			CheckedOut: map[string]map[string]model.LicenseLease{
+				"A1MSP": {"l2": {Expiration: exp, LeaseEnd: exp}},
+			},
+		}
+
+		rows := exportRows(&model.AssetPublic{ID: "101", Status: model.AssetActive}, a)
+		require.Equal(t, [][]string{
+			{"101", "asset1", "active", "2022-01-01T00:00:00Z", "2026-01-01T00:00:00Z", "l1", "2025-01-01T00:00:00Z", "", ""},
+			{"101", "asset1", "active", "2022-01-01T00:00:00Z", "2026-01-01T00:00:00Z", "l2", "2025-01-01T00:00:00Z", "A1MSP", "2025-01-01T00:00:00Z"},
+		}, rows)
+	})
+}
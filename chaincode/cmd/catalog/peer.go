@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// peer submits transactions to the Blossom chaincode with the peer CLI.  The peer the CLI connects to and the
+// identity it uses are set with the CORE_PEER_* environment variables, as in scripts/demo.sh.
+type peer struct {
+	bin          string
+	orderer      string
+	hostOverride string
+	caFile       string
+	channel      string
+	chaincode    string
+}
+
+// invoke submits the transaction and waits for it to be committed so a later chunk never depends on an uncommitted
+// one.  Each transient value is marshaled to JSON and base64 encoded.
+func (p *peer) invoke(args []string, transient map[string]interface{}) error {
+	ctor, err := json.Marshal(map[string][]string{"Args": args})
+	if err != nil {
+		return fmt.Errorf("error marshaling args: %w", err)
+	}
+
+	cmdArgs := []string{"chaincode", "invoke", "-o", p.orderer, "--ordererTLSHostnameOverride", p.hostOverride,
+		"--tls", "--cafile", p.caFile, "-C", p.channel, "-n", p.chaincode, "-c", string(ctor), "--waitForEvent"}
+
+	if len(transient) > 0 {
+		encoded := make(map[string]string)
+		for key, value := range transient {
+			bytes, err := json.Marshal(value)
+			if err != nil {
+				return fmt.Errorf("error marshaling transient %s: %w", key, err)
+			}
+
+			encoded[key] = base64.StdEncoding.EncodeToString(bytes)
+		}
+
+		bytes, err := json.Marshal(encoded)
+		if err != nil {
+			return fmt.Errorf("error marshaling transient map: %w", err)
+		}
+
+		cmdArgs = append(cmdArgs, "--transient", string(bytes))
+	}
+
+	_, err = p.run(cmdArgs)
+	return err
+}
+
+// query evaluates the transaction and returns its result.
+func (p *peer) query(args ...string) ([]byte, error) {
+	ctor, err := json.Marshal(map[string][]string{"Args": args})
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling args: %w", err)
+	}
+
+	return p.run([]string{"chaincode", "query", "-C", p.channel, "-n", p.chaincode, "-c", string(ctor)})
+}
+
+func (p *peer) run(args []string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command(p.bin, args...)
+	cmd.Env = os.Environ()
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s %s %s: %w: %s", p.bin, args[0], args[1], err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,181 @@
+// Command licensekey creates an account's encryption key and opens the license keys sealed to it.
+//
+// The keygen command writes a new RSA private key and prints its public key, which the system owner registers with
+// RegisterEncryptionKey.  The open command reads the output of GetLicenses and prints the ID and key of every license
+// with a sealed key as CSV.  The private key never leaves the machine the command runs on.
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"sort"
+
+	"github.com/usnistgov/blossom/chaincode/model"
+	"github.com/usnistgov/blossom/chaincode/seal"
+)
+
+const usage = `usage:
+  licensekey keygen -out <private.pem> [-bits 3072]
+  licensekey open -key <private.pem> [-in <licenses.json>]`
+
+func main() {
+	log.SetFlags(0)
+
+	if len(os.Args) < 2 {
+		log.Fatal(usage)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "keygen":
+		err = runKeygen(os.Args[2:], os.Stdout)
+	case "open":
+		err = runOpen(os.Args[2:], os.Stdin, os.Stdout)
+	default:
+		log.Fatal(usage)
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func runKeygen(args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("keygen", flag.ExitOnError)
+	out := fs.String("out", "", "file to write the PEM encoded private key to")
+	bits := fs.Int("bits", 3072, "size of the RSA key")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *out == "" {
+		return fmt.Errorf("-out is required\n%s", usage)
+	} else if *bits < seal.MinKeyBits {
+		return fmt.Errorf("-bits must be at least %d", seal.MinKeyBits)
+	}
+
+	priv, err := rsa.GenerateKey(rand.Reader, *bits)
+	if err != nil {
+		return fmt.Errorf("error generating key: %w", err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return fmt.Errorf("error marshaling private key: %w", err)
+	}
+
+	// do not overwrite an existing key, licenses sealed to it could no longer be opened
+	file, err := os.OpenFile(*out, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return fmt.Errorf("error creating %s: %w", *out, err)
+	}
+	defer file.Close()
+
+	if err = pem.Encode(file, &pem.Block{Type: "PRIVATE KEY", Bytes: der}); err != nil {
+		return fmt.Errorf("error writing private key: %w", err)
+	}
+
+	if der, err = x509.MarshalPKIXPublicKey(&priv.PublicKey); err != nil {
+		return fmt.Errorf("error marshaling public key: %w", err)
+	}
+
+	return pem.Encode(w, &pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func runOpen(args []string, stdin io.Reader, w io.Writer) error {
+	fs := flag.NewFlagSet("open", flag.ExitOnError)
+	keyFile := fs.String("key", "", "PEM encoded private key of the account")
+	in := fs.String("in", "", "output of GetLicenses, defaults to stdin")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *keyFile == "" {
+		return fmt.Errorf("-key is required\n%s", usage)
+	}
+
+	pemKey, err := readFile(*keyFile)
+	if err != nil {
+		return err
+	}
+
+	priv, err := seal.ParsePrivateKey(pemKey)
+	if err != nil {
+		return err
+	}
+
+	r := stdin
+	if *in != "" {
+		file, err := os.Open(*in)
+		if err != nil {
+			return fmt.Errorf("error opening %s: %w", *in, err)
+		}
+		defer file.Close()
+
+		r = file
+	}
+
+	leases := make(map[string]model.LicenseLease)
+	if err = json.NewDecoder(r).Decode(&leases); err != nil {
+		return fmt.Errorf("error reading licenses: %w", err)
+	}
+
+	keys, err := openLicenseKeys(priv, leases)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	if err = writer.Write([]string{"license_id", "key"}); err != nil {
+		return err
+	}
+
+	if err = writer.WriteAll(keys); err != nil {
+		return err
+	}
+
+	return writer.Error()
+}
+
+// openLicenseKeys returns the ID and key of each license with a sealed key, in license ID order.
+func openLicenseKeys(priv *rsa.PrivateKey, leases map[string]model.LicenseLease) ([][]string, error) {
+	licenses := make([]string, 0)
+	for license, lease := range leases {
+		if lease.SealedKey != "" {
+			licenses = append(licenses, license)
+		}
+	}
+	sort.Strings(licenses)
+
+	keys := make([][]string, 0, len(licenses))
+	for _, license := range licenses {
+		key, err := seal.Open(priv, leases[license].SealedKey, []byte(license))
+		if err != nil {
+			return nil, fmt.Errorf("error opening key of license %s: %w", license, err)
+		}
+
+		keys = append(keys, []string{license, string(key)})
+	}
+
+	return keys, nil
+}
+
+func readFile(path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening %s: %w", path, err)
+	}
+	defer file.Close()
+
+	return ioutil.ReadAll(file)
+}
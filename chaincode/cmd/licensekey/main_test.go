@@ -0,0 +1,29 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/usnistgov/blossom/chaincode/model"
+	"github.com/usnistgov/blossom/chaincode/seal"
+)
+
+func TestOpenLicenseKeys(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, seal.MinKeyBits)
+	require.NoError(t, err)
+
+	sealed, err := seal.Seal(&priv.PublicKey, []byte("KEY-2"), []byte("l2"), rand.Reader)
+	require.NoError(t, err)
+
+	keys, err := openLicenseKeys(priv, map[string]model.LicenseLease{
+		"l2": {SealedKey: sealed},
+		"l1": {},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"l2", "KEY-2"}}, keys)
+
+	_, err = openLicenseKeys(priv, map[string]model.LicenseLease{"l1": {SealedKey: sealed}})
+	require.Error(t, err)
+}
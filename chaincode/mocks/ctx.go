@@ -3,8 +3,10 @@ package mocks
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/hyperledger/fabric-chaincode-go/pkg/cid"
 	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"time"
 )
 
 type (
@@ -32,10 +34,34 @@ func (c *Ctx) SetTransient(key string, value interface{}) error {
 	}
 
 	c.stub.(*stub).transient[key] = bytes
+	c.stub.(*stub).nextTx()
 
 	return nil
 }
 
+// SetTxID sets the transaction ID returned by the stub until the next transaction starts.
+func (c *Ctx) SetTxID(txID string) {
+	c.stub.(*stub).txID = txID
+}
+
+// SetTxTimestamp sets the transaction timestamp returned by the stub.
+func (c *Ctx) SetTxTimestamp(t time.Time) error {
+	ts, err := ptypes.TimestampProto(t)
+	if err != nil {
+		return fmt.Errorf("error converting time to timestamp: %w", err)
+	}
+
+	c.stub.(*stub).txTime = ts
+
+	return nil
+}
+
+// GetEvent returns the payload of the last event set with the given name.
+func (c *Ctx) GetEvent(name string) ([]byte, bool) {
+	payload, ok := c.stub.(*stub).events[name]
+	return payload, ok
+}
+
 func (c *Ctx) GetStub() shim.ChaincodeStubInterface {
 	return c.stub
 }
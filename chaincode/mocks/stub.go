@@ -2,12 +2,15 @@ package mocks
 
 import (
 	"encoding/json"
+	"fmt"
 	"github.com/PM-Master/policy-machine-go/policy"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/hyperledger/fabric-chaincode-go/shim"
 	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
 	"github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/usnistgov/blossom/chaincode/ngac/common"
+	"sort"
 )
 
 type (
@@ -19,6 +22,10 @@ type (
 		iterator  []*kv
 		transient map[string][]byte
 		pvtData   *PvtData
+		txCount   int
+		txID      string
+		txTime    *timestamp.Timestamp
+		events    map[string][]byte
 	}
 
 	kv struct {
@@ -50,9 +57,20 @@ func newStub() *stub {
 		user:      &ClientIdentity{},
 		transient: make(map[string][]byte),
 		pvtData:   NewPvtData(),
+		txID:      "tx0",
+		txTime:    ptypes.TimestampNow(),
+		events:    make(map[string][]byte),
 	}
 }
 
+// nextTx starts a new mock transaction by assigning a new transaction ID and advancing the transaction timestamp by a
+// second so transactions are ordered.
+func (s *stub) nextTx() {
+	s.txCount++
+	s.txID = fmt.Sprintf("tx%d", s.txCount)
+	s.txTime = &timestamp.Timestamp{Seconds: s.txTime.Seconds + 1, Nanos: s.txTime.Nanos}
+}
+
 func (s *stub) PutNGAC(collection string, policyStore policy.Store) error {
 	bytes, err := policyStore.Graph().MarshalJSON()
 	if err != nil {
@@ -97,6 +115,7 @@ func (s *stub) SetFunctionAndArgs(function string, args ...string) {
 
 func (s *stub) SetClientIdentity(clientIdentity *ClientIdentity) {
 	s.user = clientIdentity
+	s.nextTx()
 }
 
 func (s *stub) SetTransient(key string, value interface{}) error {
@@ -131,7 +150,7 @@ func (s *stub) GetArgsSlice() ([]byte, error) {
 }
 
 func (s *stub) GetTxID() string {
-	panic("implement me")
+	return s.txID
 }
 
 func (s *stub) GetChannelID() string {
@@ -165,16 +184,37 @@ func (s *stub) GetStateValidationParameter(key string) ([]byte, error) {
 }
 
 func (s *stub) GetStateByRange(startKey, endKey string) (shim.StateQueryIteratorInterface, error) {
+	iter, _, err := s.GetStateByRangeWithPagination(startKey, endKey, 0, "")
+	return iter, err
+}
+
+func (s *stub) GetStateByRangeWithPagination(startKey, endKey string, pageSize int32, bookmark string) (shim.StateQueryIteratorInterface, *peer.QueryResponseMetadata, error) {
+	// the bookmark is the first key of the next page
+	if bookmark != "" {
+		startKey = bookmark
+	}
+
+	keys := make([]string, 0)
+	for k := range s.state {
+		if k >= startKey && (endKey == "" || k < endKey) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	next := ""
+	if pageSize > 0 && len(keys) > int(pageSize) {
+		next = keys[pageSize]
+		keys = keys[:pageSize]
+	}
+
 	kvs := make([]*kv, 0)
-	for k, v := range s.state {
-		kvs = append(kvs, &kv{k, v})
+	for _, k := range keys {
+		kvs = append(kvs, &kv{k, s.state[k]})
 	}
 	s.iterator = kvs
-	return s, nil
-}
 
-func (s *stub) GetStateByRangeWithPagination(startKey, endKey string, pageSize int32, bookmark string) (shim.StateQueryIteratorInterface, *peer.QueryResponseMetadata, error) {
-	panic("implement me")
+	return s, &peer.QueryResponseMetadata{FetchedRecordsCount: int32(len(kvs)), Bookmark: next}, nil
 }
 
 func (s *stub) GetStateByPartialCompositeKey(objectType string, keys []string) (shim.StateQueryIteratorInterface, error) {
@@ -283,9 +323,10 @@ func (s *stub) GetSignedProposal() (*peer.SignedProposal, error) {
 }
 
 func (s *stub) GetTxTimestamp() (*timestamp.Timestamp, error) {
-	panic("implement me")
+	return s.txTime, nil
 }
 
 func (s *stub) SetEvent(name string, payload []byte) error {
-	panic("implement me")
+	s.events[name] = payload
+	return nil
 }
@@ -6,22 +6,56 @@ import (
 
 type (
 	AccountPrivate struct {
-		ATO    string                       `json:"ato"`
-		Assets map[string]map[string]string `json:"assets" json:"assets"`
+		ATO    *ATO                               `json:"ato,omitempty"`
+		Assets map[string]map[string]LicenseLease `json:"assets" json:"assets"`
+		// ROBAcceptance is the most recent acceptance of the Rules of Behavior by the account
+		ROBAcceptance *ROBAcceptance `json:"rob_acceptance,omitempty"`
+		// Users is the roster of the account, mapping the username of each user to their role.  It is registered in the
+		// NGAC graph when the account is approved and when the admin processes the account's users.
+		Users map[string]string `json:"users"`
+		// EncryptionKey is the PEM encoded RSA public key license keys are sealed to when they are checked out
+		EncryptionKey string `json:"encryption_key,omitempty"`
+		// PointsOfContact are the people to contact about the account.  They are kept private to the account and the
+		// admin since they identify individuals.
+		PointsOfContact []PointOfContact `json:"points_of_contact,omitempty"`
 	}
 
 	AccountPublic struct {
 		Name   string `json:"name"`
 		MSPID  string `json:"mspid"`
 		Status Status `json:"status"`
+		// StatusUpdate is the most recent change to the account's status.  Every change is written to the public
+		// account record, so the full sequence of changes is available through the key history of the account.
+		StatusUpdate *Update `json:"status_update,omitempty"`
+		// Department is the executive department or independent agency the account belongs to
+		Department string `json:"department,omitempty"`
+		// SubAgency is the sub-agency or bureau within the department the account belongs to, if any
+		SubAgency string `json:"sub_agency,omitempty"`
 	}
 
 	Account struct {
-		Name   string                       `json:"name"`
-		MSPID  string                       `json:"mspid"`
-		Status Status                       `json:"status"`
-		ATO    string                       `json:"ato"`
-		Assets map[string]map[string]string `json:"assets" json:"assets"`
+		Name            string                             `json:"name"`
+		MSPID           string                             `json:"mspid"`
+		Status          Status                             `json:"status"`
+		StatusUpdate    *Update                            `json:"status_update,omitempty"`
+		Department      string                             `json:"department,omitempty"`
+		SubAgency       string                             `json:"sub_agency,omitempty"`
+		PointsOfContact []PointOfContact                   `json:"points_of_contact,omitempty"`
+		ATO             *ATO                               `json:"ato,omitempty"`
+		ROBAcceptance   *ROBAcceptance                     `json:"rob_acceptance,omitempty"`
+		Assets          map[string]map[string]LicenseLease `json:"assets" json:"assets"`
+		Users           map[string]string                  `json:"users"`
+	}
+
+	// AccountsPage is a page of public account info returned by a paginated query.
+	AccountsPage struct {
+		// Accounts are the accounts in the page
+		Accounts []*AccountPublic `json:"accounts"`
+		// Bookmark is passed to the next query to get the next page.  It is empty when there are no more pages.
+		Bookmark string `json:"bookmark"`
+		// Count is the total number of accounts that match the status filter across all pages.  It is only set on the
+		// first page, the page requested with an empty bookmark, and is omitted from later pages.
+		Count *int `json:"count,omitempty"`
 	}
 
 	// Status represents the status of an account within the blossom system
@@ -40,6 +74,20 @@ var (
 		"UNAUTHORIZED_ROB":           UnauthorizedROB,
 	}
 
+	// statusTransitions declares the statuses an account can move to from each status.  A transition that is not
+	// listed here is rejected by ValidateStatusTransition.
+	// Decommissioned is terminal.
+	statusTransitions = map[Status][]Status{
+		PendingApproval:          {PendingATO, UnauthorizedDenied, Decommissioned},
+		PendingATO:               {Authorized, UnauthorizedDenied, UnauthorizedOptOut},
+		Authorized:               {PendingATO, UnauthorizedATO, UnauthorizedOptOut, UnauthorizedSecurityRisk, UnauthorizedROB},
+		UnauthorizedDenied:       {PendingApproval, PendingATO, Decommissioned},
+		UnauthorizedATO:          {Authorized, PendingATO, UnauthorizedOptOut, Decommissioned},
+		UnauthorizedOptOut:       {PendingATO, Decommissioned},
+		UnauthorizedSecurityRisk: {PendingATO, UnauthorizedOptOut, Decommissioned},
+		UnauthorizedROB:          {Authorized, PendingATO, UnauthorizedOptOut, Decommissioned},
+	}
+
 	roles = map[string]bool{
 		SystemOwnerRole:           true,
 		SystemAdminRole:           true,
@@ -56,6 +104,7 @@ const (
 	UnauthorizedOptOut       Status = "Unauthorized: opted out"
 	UnauthorizedSecurityRisk Status = "Unauthorized: security risk"
 	UnauthorizedROB          Status = "Unauthorized: breach in rules of behavior"
+	Decommissioned           Status = "Decommissioned"
 
 	AccountPrefix = "account:"
 
@@ -79,6 +128,32 @@ func GetStatusUpdate(s string) (Status, error) {
 	return status, nil
 }
 
+// ParseStatus returns the Status for any of the strings accepted by GetStatusUpdate, as well as "DECOMMISSIONED".
+func ParseStatus(s string) (Status, error) {
+	if s == "DECOMMISSIONED" {
+		return Decommissioned, nil
+	}
+
+	return GetStatusUpdate(s)
+}
+
+// ValidateStatusTransition returns an error if an account cannot move from the status from to the status to.
+func ValidateStatusTransition(from, to Status) error {
+	for _, allowed := range statusTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("account status cannot be updated from %q to %q", from, to)
+}
+
+// AccountKeyRange returns the start (inclusive) and end (exclusive) keys of the range of all accounts in the world state.
+func AccountKeyRange() (string, string) {
+	// ';' is the character after ':' so the range covers every key with the account prefix
+	return AccountPrefix, "account;"
+}
+
 // AccountKey returns the key for an account on the ledger.  Accounts are stored with the format: "account:<account_name>".
 func AccountKey(name string) string {
 	return fmt.Sprintf("%s%s", AccountPrefix, name)
@@ -89,8 +164,8 @@ func NewAccount() *Account {
 		Name:   "",
 		MSPID:  "",
 		Status: "",
-		ATO:    "",
-		Assets: make(map[string]map[string]string),
+		Assets: make(map[string]map[string]LicenseLease),
+		Users:  make(map[string]string),
 	}
 }
 
@@ -104,7 +179,7 @@ func NewAccountPublic() *AccountPublic {
 
 func NewAccountPrivate() *AccountPrivate {
 	return &AccountPrivate{
-		ATO:    "",
-		Assets: make(map[string]map[string]string),
+		Assets: make(map[string]map[string]LicenseLease),
+		Users:  make(map[string]string),
 	}
 }
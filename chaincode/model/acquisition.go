@@ -0,0 +1,100 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+type (
+	// Acquisition is a request by an account's acquisition specialist to procure new software.  It moves from
+	// REQUESTED to APPROVED by the admin, to PURCHASED when the acquisition specialist records the purchase, and to
+	// ONBOARDED when the admin onboards the purchased licenses as an asset.  Acquisitions are stored in the private data
+	// collection of the requesting account.
+	Acquisition struct {
+		// ID is the ID of the transaction that requested the acquisition
+		ID string `json:"id"`
+		// Account is the account that requested the acquisition
+		Account string `json:"account"`
+		// Product is the name of the software product to acquire
+		Product string `json:"product"`
+		// Quantity is the number of licenses to acquire
+		Quantity int `json:"quantity"`
+		// Justification is the business justification for the acquisition
+		Justification string `json:"justification"`
+		// Status is the current status of the acquisition
+		Status AcquisitionStatus `json:"status"`
+		// Quotes are the vendor quotes attached to the acquisition, oldest first
+		Quotes []Quote `json:"quotes"`
+		// Purchase is the purchase made for the acquisition
+		Purchase *Purchase `json:"purchase,omitempty"`
+		// AssetID is the ID of the asset the purchased licenses were onboarded as
+		AssetID string `json:"asset_id,omitempty"`
+		// History records every change to the status of the acquisition, oldest first
+		History []Update `json:"history"`
+	}
+
+	// Quote is a vendor quote for an acquisition.  Only the digest of the quote document is stored.
+	Quote struct {
+		Vendor string `json:"vendor"`
+		// Digest is the hex encoded SHA-256 digest of the quote document
+		Digest string `json:"digest"`
+		// AmountCents is the total amount quoted, in cents
+		AmountCents int64 `json:"amount_cents"`
+		// URI is the location of the quote document
+		URI string `json:"uri,omitempty"`
+		// AttachedBy is the user that attached the quote
+		AttachedBy string `json:"attached_by"`
+		// AttachedAt is the timestamp of the transaction that attached the quote
+		AttachedAt time.Time `json:"attached_at"`
+	}
+
+	// Purchase is the purchase made for an acquisition and the license pool it provides.
+	Purchase struct {
+		// OrderNumber is the purchase order number
+		OrderNumber string `json:"order_number"`
+		// QuoteDigest is the digest of the quote the purchase was made from
+		QuoteDigest string `json:"quote_digest"`
+		// Licenses is the purchased license pool
+		Licenses []License `json:"licenses"`
+		// Cost is the optional price information the asset is onboarded with
+		Cost *AssetCost `json:"cost,omitempty"`
+	}
+
+	// AcquisitionStatus is the status of an acquisition
+	AcquisitionStatus string
+)
+
+const (
+	// AcquisitionRequested is the status of an acquisition waiting for quotes and the approval of the admin
+	AcquisitionRequested AcquisitionStatus = "REQUESTED"
+	// AcquisitionApproved is the status of an acquisition approved by the admin and waiting to be purchased
+	AcquisitionApproved AcquisitionStatus = "APPROVED"
+	// AcquisitionDenied is the status of an acquisition denied by the admin
+	AcquisitionDenied AcquisitionStatus = "DENIED"
+	// AcquisitionPurchased is the status of an acquisition that has been purchased and is waiting to be onboarded
+	AcquisitionPurchased AcquisitionStatus = "PURCHASED"
+	// AcquisitionOnboarded is the status of an acquisition whose licenses have been onboarded as an asset
+	AcquisitionOnboarded AcquisitionStatus = "ONBOARDED"
+)
+
+// AcquisitionKey returns the key for an acquisition in the private data collection of the account.  Acquisitions are
+// stored with the format: "acquisition=<account>:<acquisition_id>".
+func AcquisitionKey(account, id string) string {
+	return fmt.Sprintf("acquisition=%s:%s", account, id)
+}
+
+// IsOpen returns true if the acquisition has not been denied or onboarded.
+func (a *Acquisition) IsOpen() bool {
+	return a.Status == AcquisitionRequested || a.Status == AcquisitionApproved || a.Status == AcquisitionPurchased
+}
+
+// Quote returns the quote attached to the acquisition with the given digest.
+func (a *Acquisition) Quote(digest string) (Quote, bool) {
+	for _, quote := range a.Quotes {
+		if quote.Digest == digest {
+			return quote, true
+		}
+	}
+
+	return Quote{}, false
+}
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"fmt"
+)
+
+type (
+	// PointOfContact is a person to contact about an account.
+	PointOfContact struct {
+		Name  string `json:"name"`
+		Email string `json:"email"`
+		Phone string `json:"phone,omitempty"`
+		// Title is the position of the contact within the agency (i.e. CIO)
+		Title string `json:"title,omitempty"`
+	}
+
+	// LicenseRollup aggregates the licenses checked out by the accounts of a department or sub-agency.  The roll-up of a
+	// department has the roll-ups of its sub-agencies, and of the accounts that do not belong to a sub-agency, as
+	// children.  The roll-up of a sub-agency has the roll-ups of its accounts as children.
+	LicenseRollup struct {
+		// Name is the name of the department, sub-agency or account
+		Name string `json:"name"`
+		// Level is the level of the hierarchy the roll-up represents
+		Level RollupLevel `json:"level"`
+		// Assets maps the ID of each asset to the number of licenses checked out
+		Assets map[string]int `json:"assets"`
+		// Total is the total number of licenses checked out across all assets
+		Total int `json:"total"`
+		// Children are the roll-ups of the next level down the hierarchy
+		Children []*LicenseRollup `json:"children,omitempty"`
+	}
+
+	// RollupLevel is a level of the agency hierarchy
+	RollupLevel string
+)
+
+const (
+	DepartmentLevel RollupLevel = "department"
+	SubAgencyLevel  RollupLevel = "sub_agency"
+	AccountLevel    RollupLevel = "account"
+
+	// UnassignedDepartment is the department accounts are rolled up to if they have not recorded their department
+	UnassignedDepartment = "Unassigned"
+)
+
+// ValidatePointsOfContact returns an error if any point of contact is missing a name or email.
+func ValidatePointsOfContact(pocs []PointOfContact) error {
+	for i, poc := range pocs {
+		if poc.Name == "" {
+			return fmt.Errorf("point of contact %d is missing a name", i)
+		}
+		if poc.Email == "" {
+			return fmt.Errorf("point of contact %s is missing an email", poc.Name)
+		}
+	}
+
+	return nil
+}
+
+func NewLicenseRollup(name string, level RollupLevel) *LicenseRollup {
+	return &LicenseRollup{
+		Name:   name,
+		Level:  level,
+		Assets: make(map[string]int),
+	}
+}
+
+// Add adds the number of licenses checked out for an asset to the roll-up.
+func (r *LicenseRollup) Add(assetID string, amount int) {
+	r.Assets[assetID] += amount
+	r.Total += amount
+}
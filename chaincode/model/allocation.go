@@ -0,0 +1,154 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// AllocationStrategy is how the available licenses of an asset are selected when licenses are checked out.
+type AllocationStrategy string
+
+const (
+	// AllocationPoolOrder selects licenses in the order they are in the available pool.  This is the strategy of assets
+	// that do not set one.
+	AllocationPoolOrder AllocationStrategy = "POOL_ORDER"
+	// AllocationSoonestExpiring selects the licenses that expire soonest first
+	AllocationSoonestExpiring AllocationStrategy = "SOONEST_EXPIRING"
+	// AllocationLatestExpiring selects the licenses that expire latest first
+	AllocationLatestExpiring AllocationStrategy = "LATEST_EXPIRING"
+	// AllocationSameBatch selects licenses from as few contract batches as possible.  If a single batch has enough
+	// licenses the smallest such batch is used, otherwise the largest batches are used first.
+	AllocationSameBatch AllocationStrategy = "SAME_BATCH"
+	// AllocationExplicit requires the approver to choose the licenses.  Licenses checked out without being chosen, i.e.
+	// from the waitlist, are selected in pool order.
+	AllocationExplicit AllocationStrategy = "EXPLICIT"
+)
+
+var allocationStrategies = map[AllocationStrategy]bool{
+	AllocationPoolOrder:       true,
+	AllocationSoonestExpiring: true,
+	AllocationLatestExpiring:  true,
+	AllocationSameBatch:       true,
+	AllocationExplicit:        true,
+}
+
+// ValidateAllocationStrategy returns an error if the strategy is not a known allocation strategy.  An empty strategy is
+// valid and means pool order.
+func ValidateAllocationStrategy(strategy AllocationStrategy) error {
+	if strategy != "" && !allocationStrategies[strategy] {
+		return fmt.Errorf("unknown allocation strategy: %s", strategy)
+	}
+
+	return nil
+}
+
+// Allocation returns the allocation strategy of the asset, defaulting to pool order.
+func (a *AssetPublic) Allocation() AllocationStrategy {
+	if a.AllocationStrategy == "" {
+		return AllocationPoolOrder
+	}
+
+	return a.AllocationStrategy
+}
+
+// SetBatch records the contract batch of the license, if it has one.
+func (a *AssetPrivate) SetBatch(license License) {
+	if license.Batch == "" {
+		return
+	}
+
+	if a.Batches == nil {
+		a.Batches = make(map[string]string)
+	}
+
+	a.Batches[license.LicenseID] = license.Batch
+}
+
+// SetKey records the license key of the license, if it has one.
+func (a *AssetPrivate) SetKey(license License) {
+	if license.Key == "" {
+		return
+	}
+
+	if a.Keys == nil {
+		a.Keys = make(map[string]string)
+	}
+
+	a.Keys[license.LicenseID] = license.Key
+}
+
+// SelectLicenses returns up to the given amount of available licenses that have not expired as of the given time,
+// selected using the strategy.  The explicit strategy is treated as pool order.
+func (a *AssetPrivate) SelectLicenses(strategy AllocationStrategy, amount int, now time.Time) []string {
+	unexpired := make([]string, 0)
+	for _, license := range a.AvailableLicenses {
+		if !IsLicenseExpired(a.Licenses[license], now) {
+			unexpired = append(unexpired, license)
+		}
+	}
+
+	switch strategy {
+	case AllocationSoonestExpiring:
+		sort.SliceStable(unexpired, func(i, j int) bool {
+			return a.Licenses[unexpired[i]].Before(a.Licenses[unexpired[j]])
+		})
+	case AllocationLatestExpiring:
+		sort.SliceStable(unexpired, func(i, j int) bool {
+			return a.Licenses[unexpired[i]].After(a.Licenses[unexpired[j]])
+		})
+	case AllocationSameBatch:
+		unexpired = a.orderByBatch(unexpired, amount)
+	}
+
+	if len(unexpired) > amount {
+		unexpired = unexpired[:amount]
+	}
+
+	return unexpired
+}
+
+// orderByBatch orders the licenses by batch so that the first amount licenses come from as few batches as possible.
+// Licenses keep their pool order within a batch.  Licenses without a batch are treated as one batch.
+func (a *AssetPrivate) orderByBatch(licenses []string, amount int) []string {
+	batches := make(map[string][]string)
+	names := make([]string, 0)
+	for _, license := range licenses {
+		batch := a.Batches[license]
+		if _, ok := batches[batch]; !ok {
+			names = append(names, batch)
+		}
+
+		batches[batch] = append(batches[batch], license)
+	}
+
+	// the largest batches first so the fewest batches are split, ties broken by name
+	sort.Slice(names, func(i, j int) bool {
+		if len(batches[names[i]]) == len(batches[names[j]]) {
+			return names[i] < names[j]
+		}
+
+		return len(batches[names[i]]) > len(batches[names[j]])
+	})
+
+	// if a batch has enough licenses, use the smallest one so larger batches stay together for larger checkouts
+	best := -1
+	for i, name := range names {
+		if size := len(batches[name]); size >= amount && (best < 0 || size < len(batches[names[best]])) {
+			best = i
+		}
+	}
+
+	ordered := make([]string, 0, len(licenses))
+	if best >= 0 {
+		ordered = append(ordered, batches[names[best]]...)
+	}
+
+	for i, name := range names {
+		if i != best {
+			ordered = append(ordered, batches[name]...)
+		}
+	}
+
+	return ordered
+}
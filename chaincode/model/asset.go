@@ -2,6 +2,7 @@ package model
 
 import (
 	"fmt"
+	"time"
 )
 
 type (
@@ -9,12 +10,20 @@ type (
 		// TotalAmount is the total number of licenses available to Blossom
 		TotalAmount int `json:"total_amount"`
 		// Licenses is the complete set of licenses associated with this asset
-		Licenses map[string]string `json:"licenses"`
+		Licenses map[string]time.Time `json:"licenses"`
 		// AvailableLicenses is the set of licenses that are available to be checked out
 		AvailableLicenses []string `json:"available_licenses"`
 		// CheckedOut stores the accounts that have checked out this asset, which licenses they have leased and the
 		// expiration for each license
-		CheckedOut map[string]map[string]string `json:"checked_out"`
+		CheckedOut map[string]map[string]LicenseLease `json:"checked_out"`
+		// Charges are the charges to accounts for the leases of this asset that have ended and have not yet been
+		// written under their own keys.  Assets recorded before charges were stored apart may still carry them.
+		Charges []ChargebackEntry `json:"charges,omitempty"`
+		// Batches maps each license that was onboarded with a contract batch to the batch
+		Batches map[string]string `json:"batches,omitempty"`
+		// Keys maps each license that was onboarded with a license key to the key.  The keys are kept in plaintext,
+		// including those of checked out licenses, so they can be sealed for the next holder of the license.
+		Keys map[string]string `json:"keys,omitempty"`
 	}
 
 	// AssetPublic represents the public info for software asset on the ledger.
@@ -26,9 +35,16 @@ type (
 		// Available is the number of licenses that are currently available to be checked out
 		Available int `json:"available"`
 		// OnboardingDate is the date in which the asset was added to Blossom
-		OnboardingDate string `json:"onboarding_date"`
+		OnboardingDate time.Time `json:"onboarding_date"`
 		// Expiration is the date in which the asset will expire from Blossom
-		Expiration string `json:"expiration"`
+		Expiration time.Time `json:"expiration"`
+		// Cost is the price information of the asset, if any
+		Cost *AssetCost `json:"cost,omitempty"`
+		// AllocationStrategy is how licenses are selected when they are checked out.  If empty licenses are selected
+		// in pool order.
+		AllocationStrategy AllocationStrategy `json:"allocation_strategy,omitempty"`
+		// Status is computed from the dates of the asset when it is read and is not stored
+		Status AssetStatus `json:"status,omitempty"`
 	}
 
 	Asset struct {
@@ -39,28 +55,134 @@ type (
 		// Available is the number of licenses that are currently available to be checked out
 		Available int `json:"available"`
 		// OnboardingDate is the date in which the asset was added to Blossom
-		OnboardingDate string `json:"onboarding_date"`
+		OnboardingDate time.Time `json:"onboarding_date"`
 		// Expiration is the date in which the asset will expire from Blossom
-		Expiration string `json:"expiration"`
+		Expiration time.Time `json:"expiration"`
+		// Cost is the price information of the asset, if any
+		Cost *AssetCost `json:"cost,omitempty"`
+		// AllocationStrategy is how licenses are selected when they are checked out.  If empty licenses are selected
+		// in pool order.
+		AllocationStrategy AllocationStrategy `json:"allocation_strategy,omitempty"`
+		// Status is computed from the dates of the asset when it is read
+		Status AssetStatus `json:"status,omitempty"`
 		// TotalAmount is the total number of licenses available to Blossom
 		TotalAmount int `json:"total_amount"`
 		// Licenses is the complete set of licenses associated with this asset
-		Licenses map[string]string `json:"licenses"`
+		Licenses map[string]time.Time `json:"licenses"`
 		// AvailableLicenses is the set of licenses that are available to be checked out
 		AvailableLicenses []string `json:"available_licenses"`
 		// CheckedOut stores the accounts that have checked out this asset, which licenses they have leased and the
 		// expiration for each license
-		CheckedOut map[string]map[string]string `json:"checked_out"`
+		CheckedOut map[string]map[string]LicenseLease `json:"checked_out"`
 	}
 
 	License struct {
-		LicenseID  string `json:"license_id,omitempty"`
-		Expiration string `json:"expiration,omitempty"`
+		LicenseID string `json:"license_id,omitempty"`
+		// Expiration is the date the license expires.  If not set the license expires with the asset.
+		Expiration time.Time `json:"expiration"`
+		// Batch is the contract batch the license was purchased in, if any
+		Batch string `json:"batch,omitempty"`
+		// Key is the secret license key, if any.  It is sealed to the encryption key of the account the license is
+		// checked out to.
+		Key string `json:"key,omitempty"`
 	}
+
+	// AssetStatus is the status of an asset as of a point in time
+	AssetStatus string
+)
+
+const (
+	// AssetPending is the status of an asset before its onboarding date
+	AssetPending AssetStatus = "pending"
+	// AssetActive is the status of an asset between its onboarding date and its expiration
+	AssetActive AssetStatus = "active"
+	// AssetExpired is the status of an asset at or after its expiration
+	AssetExpired AssetStatus = "expired"
 )
 
 const AssetPrefix = "asset:"
 
+// LegacyDateLayouts are the layouts dates stored before dates were validated are parsed with, in order.
+var LegacyDateLayouts = []string{time.RFC3339, "2006-01-02", "01/02/2006"}
+
+// ParseDate parses an RFC 3339 date and returns it in UTC.
+func ParseDate(date string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, date)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not an RFC 3339 date", date)
+	}
+
+	return t.UTC(), nil
+}
+
+// ParseLegacyDate parses a date stored before dates were validated using the LegacyDateLayouts and returns it in UTC.
+func ParseLegacyDate(date string) (time.Time, error) {
+	for _, layout := range LegacyDateLayouts {
+		if t, err := time.Parse(layout, date); err == nil {
+			return t.UTC(), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("%q is not a recognized date", date)
+}
+
+// IsLicenseExpired returns true if the license expiration is at or before the given time.  A license without an
+// expiration never expires.
+func IsLicenseExpired(expiration time.Time, t time.Time) bool {
+	return !expiration.IsZero() && !expiration.After(t)
+}
+
+// ValidateDates checks that the asset has an onboarding date and an expiration that is after it.
+func (a *AssetPublic) ValidateDates() error {
+	if a.OnboardingDate.IsZero() {
+		return fmt.Errorf("asset %q does not have an onboarding date", a.ID)
+	} else if a.Expiration.IsZero() {
+		return fmt.Errorf("asset %q does not have an expiration", a.ID)
+	} else if !a.Expiration.After(a.OnboardingDate) {
+		return fmt.Errorf("expiration %s of asset %q must be after its onboarding date %s", a.Expiration.Format(time.RFC3339),
+			a.ID, a.OnboardingDate.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// StatusAt returns the status of the asset at the given time.
+func (a *AssetPublic) StatusAt(t time.Time) AssetStatus {
+	if t.Before(a.OnboardingDate) {
+		return AssetPending
+	} else if IsLicenseExpired(a.Expiration, t) {
+		return AssetExpired
+	}
+
+	return AssetActive
+}
+
+// CheckActive returns an error if the asset is not active at the given time.
+func (a *AssetPublic) CheckActive(t time.Time) error {
+	switch a.StatusAt(t) {
+	case AssetPending:
+		return fmt.Errorf("asset %s is not onboarded until %s", a.ID, a.OnboardingDate.Format(time.RFC3339))
+	case AssetExpired:
+		return fmt.Errorf("asset %s expired on %s", a.ID, a.Expiration.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// ValidateLicenseExpiration checks that a license of the asset expires after the asset is onboarded and does not
+// outlive the asset.
+func (a *AssetPublic) ValidateLicenseExpiration(licenseID string, expiration time.Time) error {
+	if !expiration.After(a.OnboardingDate) {
+		return fmt.Errorf("license %s expiration %s must be after the onboarding date %s of asset %q", licenseID,
+			expiration.Format(time.RFC3339), a.OnboardingDate.Format(time.RFC3339), a.ID)
+	} else if expiration.After(a.Expiration) {
+		return fmt.Errorf("license %s expiration %s cannot be after the expiration %s of asset %q", licenseID,
+			expiration.Format(time.RFC3339), a.Expiration.Format(time.RFC3339), a.ID)
+	}
+
+	return nil
+}
+
 // AssetKey returns the key for an asset on the ledger.  Assets are stored with the format: "asset:<asset_id>".
 func AssetKey(id string) string {
 	return fmt.Sprintf("%s%s", AssetPrefix, id)
@@ -71,17 +193,17 @@ func NewAssetPublic() *AssetPublic {
 		ID:             "",
 		Name:           "",
 		Available:      0,
-		OnboardingDate: "",
-		Expiration:     "",
+		OnboardingDate: time.Time{},
+		Expiration:     time.Time{},
 	}
 }
 
 func NewAssetPrivate() *AssetPrivate {
 	return &AssetPrivate{
 		TotalAmount:       0,
-		Licenses:          make(map[string]string),
+		Licenses:          make(map[string]time.Time),
 		AvailableLicenses: make([]string, 0),
-		CheckedOut:        make(map[string]map[string]string),
+		CheckedOut:        make(map[string]map[string]LicenseLease),
 	}
 }
 
@@ -90,11 +212,11 @@ func NewAsset() *Asset {
 		ID:                "",
 		Name:              "",
 		Available:         0,
-		OnboardingDate:    "",
-		Expiration:        "",
+		OnboardingDate:    time.Time{},
+		Expiration:        time.Time{},
 		TotalAmount:       0,
-		Licenses:          make(map[string]string),
+		Licenses:          make(map[string]time.Time),
 		AvailableLicenses: make([]string, 0),
-		CheckedOut:        make(map[string]map[string]string),
+		CheckedOut:        make(map[string]map[string]LicenseLease),
 	}
 }
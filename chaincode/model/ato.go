@@ -0,0 +1,78 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type (
+	// ATO is the Authority to Operate attestation of an account.
+	ATO struct {
+		// AuthorizingOfficial is the official that granted the ATO
+		AuthorizingOfficial string `json:"authorizing_official"`
+		// SystemName is the name of the system the ATO was granted for
+		SystemName string `json:"system_name"`
+		// IssueDate is the date the ATO was granted
+		IssueDate time.Time `json:"issue_date"`
+		// ExpirationDate is the date the ATO expires
+		ExpirationDate time.Time `json:"expiration_date"`
+		// ImpactLevel is the FIPS 199 impact level of the system
+		ImpactLevel ImpactLevel `json:"impact_level"`
+		// Digest is the hex encoded SHA-256 digest of the ATO document
+		Digest string `json:"digest"`
+		// Attestation is the free-form ATO of accounts that uploaded an ATO before ATOs were structured
+		Attestation string `json:"attestation,omitempty"`
+	}
+
+	// ImpactLevel is a FIPS 199 security categorization
+	ImpactLevel string
+)
+
+const (
+	LowImpact      ImpactLevel = "LOW"
+	ModerateImpact ImpactLevel = "MODERATE"
+	HighImpact     ImpactLevel = "HIGH"
+)
+
+// Validate returns an error if any field of the ATO is missing or invalid.
+func (a *ATO) Validate() error {
+	if a.AuthorizingOfficial == "" {
+		return fmt.Errorf("authorizing official cannot be empty")
+	}
+	if a.SystemName == "" {
+		return fmt.Errorf("system name cannot be empty")
+	}
+	if a.IssueDate.IsZero() {
+		return fmt.Errorf("issue date cannot be empty")
+	}
+	if !a.ExpirationDate.After(a.IssueDate) {
+		return fmt.Errorf("expiration date %s must be after the issue date %s", a.ExpirationDate, a.IssueDate)
+	}
+
+	switch a.ImpactLevel {
+	case LowImpact, ModerateImpact, HighImpact:
+	default:
+		return fmt.Errorf("unknown impact level: %s", a.ImpactLevel)
+	}
+
+	return ValidateDigest(a.Digest)
+}
+
+// IsExpired returns true if the ATO has an expiration date and it has passed at the given time. ATOs stored before they
+// were structured do not have an expiration date and never expire.
+func (a *ATO) IsExpired(t time.Time) bool {
+	return !a.ExpirationDate.IsZero() && !t.Before(a.ExpirationDate)
+}
+
+// UnmarshalJSON reads an ATO, accepting the free-form string ATOs that were stored before ATOs were structured.
+func (a *ATO) UnmarshalJSON(bytes []byte) error {
+	var attestation string
+	if err := json.Unmarshal(bytes, &attestation); err == nil {
+		*a = ATO{Attestation: attestation}
+		return nil
+	}
+
+	type ato ATO
+	return json.Unmarshal(bytes, (*ato)(a))
+}
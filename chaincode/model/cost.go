@@ -0,0 +1,153 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+type (
+	// AssetCost is the price information of an asset used to charge accounts for the licenses they lease.
+	AssetCost struct {
+		// UnitCostCents is the cost of one license for one year, in cents.  Leases are charged for the time they are
+		// held, prorated over a 365 day year.
+		UnitCostCents int64 `json:"unit_cost_cents"`
+		// FundingSource is the appropriation or fund the asset was purchased with
+		FundingSource string `json:"funding_source,omitempty"`
+		// ContractLineItem is the contract line item number (CLIN) the asset was purchased under
+		ContractLineItem string `json:"contract_line_item,omitempty"`
+	}
+
+	// ChargebackEntry is the charge to an account for holding a license during part of a fiscal period.
+	ChargebackEntry struct {
+		Account string `json:"account"`
+		Asset   string `json:"asset"`
+		License string `json:"license"`
+		// Period is the fiscal period the charge falls in
+		Period string `json:"period"`
+		// Start is the start of the time charged for
+		Start time.Time `json:"start"`
+		// End is the end of the time charged for
+		End time.Time `json:"end"`
+		// UnitCostCents is the yearly unit cost of the asset the charge was computed with
+		UnitCostCents    int64  `json:"unit_cost_cents"`
+		AmountCents      int64  `json:"amount_cents"`
+		FundingSource    string `json:"funding_source,omitempty"`
+		ContractLineItem string `json:"contract_line_item,omitempty"`
+		// Accrued is true if the lease is still held and the charge only covers the time up to the query
+		Accrued bool `json:"accrued,omitempty"`
+	}
+
+	// ChargebackLedger is the charges to an account in a fiscal period.
+	ChargebackLedger struct {
+		Account    string            `json:"account"`
+		Period     string            `json:"period"`
+		Entries    []ChargebackEntry `json:"entries"`
+		TotalCents int64             `json:"total_cents"`
+	}
+
+	// CostAllocation is the total charged to each account and for each asset in a fiscal period.
+	CostAllocation struct {
+		// Period is the fiscal period of the allocation.  If empty the allocation covers every period.
+		Period string `json:"period,omitempty"`
+		// AsOf is the time charges for leases that are still held are accrued up to
+		AsOf time.Time `json:"as_of"`
+		// Accounts maps each account to the amount charged to it, in cents
+		Accounts map[string]int64 `json:"accounts"`
+		// Assets maps each asset to the amount charged for its licenses, in cents
+		Assets     map[string]int64 `json:"assets"`
+		TotalCents int64            `json:"total_cents"`
+	}
+)
+
+// secondsPerYear is the length of the year leases are prorated over.
+const secondsPerYear = 365 * 24 * 60 * 60
+
+// Validate checks that the unit cost is not negative.
+func (c *AssetCost) Validate() error {
+	if c.UnitCostCents < 0 {
+		return fmt.Errorf("unit cost cannot be negative")
+	}
+
+	return nil
+}
+
+// FiscalYear returns the federal fiscal year the time falls in.  The fiscal year starts on October 1 of the previous
+// calendar year, e.g. fiscal year 2027 starts on October 1, 2026.
+func FiscalYear(t time.Time) int {
+	t = t.UTC()
+	if t.Month() >= time.October {
+		return t.Year() + 1
+	}
+
+	return t.Year()
+}
+
+// FiscalPeriod returns the federal fiscal quarter the time falls in, e.g. "FY2027Q1" for October through December
+// 2026.
+func FiscalPeriod(t time.Time) string {
+	quarter := (int(t.UTC().Month())+2)%12/3 + 1
+
+	return fmt.Sprintf("FY%dQ%d", FiscalYear(t), quarter)
+}
+
+// nextFiscalPeriod returns the start of the fiscal quarter after the one the time falls in.  Fiscal quarters start on
+// the same days as calendar quarters.
+func nextFiscalPeriod(t time.Time) time.Time {
+	t = t.UTC()
+	month := (t.Month()-1)/3*3 + 1
+	return time.Date(t.Year(), month, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 3, 0)
+}
+
+// Charges returns the charges to the account for holding the license of the asset under the lease until the given
+// time, one for each fiscal period.  Time after the lease end is not charged.  No charges are returned if the asset
+// has no cost or the lease was recorded before lease start dates were.
+func (a *AssetPublic) Charges(account, license string, lease LicenseLease, end time.Time) []ChargebackEntry {
+	charges := make([]ChargebackEntry, 0)
+	if a.Cost == nil || lease.Start.IsZero() {
+		return charges
+	}
+
+	if lease.LeaseEnd.Before(end) {
+		end = lease.LeaseEnd
+	}
+
+	for start := lease.Start; start.Before(end); {
+		next := nextFiscalPeriod(start)
+		if next.After(end) {
+			next = end
+		}
+
+		seconds := int64(next.Sub(start) / time.Second)
+		charges = append(charges, ChargebackEntry{
+			Account:          account,
+			Asset:            a.ID,
+			License:          license,
+			Period:           FiscalPeriod(start),
+			Start:            start,
+			End:              next,
+			UnitCostCents:    a.Cost.UnitCostCents,
+			AmountCents:      a.Cost.UnitCostCents * seconds / secondsPerYear,
+			FundingSource:    a.Cost.FundingSource,
+			ContractLineItem: a.Cost.ContractLineItem,
+		})
+
+		start = next
+	}
+
+	return charges
+}
+
+const ChargebackPrefix = "chargeback="
+
+// ChargebackKey returns the key of a charge in the licenses collection.  Charges are stored apart from the asset so
+// they outlive it, with the format: "chargeback=<account>:<period>:<asset>:<license>:<start>".
+func ChargebackKey(charge ChargebackEntry) string {
+	return fmt.Sprintf("%s%s:%s:%s:%s:%s", ChargebackPrefix, charge.Account, charge.Period, charge.Asset, charge.License,
+		charge.Start.UTC().Format(time.RFC3339Nano))
+}
+
+// Charge records the charges to the account for holding the license of the asset under the lease until the given time.
+// The charges are held in the asset private info until they are written under their own keys.
+func (a *AssetPrivate) Charge(assetPub *AssetPublic, account, license string, lease LicenseLease, end time.Time) {
+	a.Charges = append(a.Charges, assetPub.Charges(account, license, lease, end)...)
+}
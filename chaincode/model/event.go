@@ -0,0 +1,31 @@
+package model
+
+const (
+	// LicensesReclaimedEvent is the name of the event emitted when the licenses of an account that opted out are
+	// reclaimed
+	LicensesReclaimedEvent = "LicensesReclaimed"
+	// LicensesExpiredEvent is the name of the event emitted when expired licenses are removed from Blossom
+	LicensesExpiredEvent = "LicensesExpired"
+)
+
+type (
+	// LicensesReclaimed summarizes the licenses reclaimed from an account and the requests that were cancelled.
+	LicensesReclaimed struct {
+		// Account is the name of the account the licenses were reclaimed from
+		Account string `json:"account"`
+		// Reason is the reason the licenses were reclaimed
+		Reason string `json:"reason"`
+		// Licenses maps the ID of each asset to the licenses returned to the asset
+		Licenses map[string][]string `json:"licenses"`
+		// CancelledRequests are the keys of the pending checkout and checkin requests that were cancelled
+		CancelledRequests []string `json:"cancelled_requests"`
+	}
+
+	// LicensesExpired summarizes the expired licenses removed from Blossom by a sweep.
+	LicensesExpired struct {
+		// Removed maps the ID of each asset to the expired licenses removed from its available licenses
+		Removed map[string][]string `json:"removed"`
+		// Revoked has an entry for each account that had expired licenses revoked
+		Revoked []LicensesReclaimed `json:"revoked"`
+	}
+)
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type (
+	// DemandForecast is the number of licenses of a product an account expects to need in a fiscal year.  The product
+	// does not need to be in the catalog.  Forecasts are stored in the private data collection of the account.
+	DemandForecast struct {
+		// ID is the ID of the transaction that submitted the forecast
+		ID string `json:"id"`
+		// Product is the name of the software product
+		Product string `json:"product"`
+		// Quantity is the number of licenses needed
+		Quantity int `json:"quantity"`
+		// FiscalYear is the federal fiscal year the licenses are needed in
+		FiscalYear int `json:"fiscal_year"`
+		// Justification is the business justification for the licenses
+		Justification string `json:"justification"`
+		// SubmittedBy is the user that submitted the forecast
+		SubmittedBy string `json:"submitted_by"`
+		// SubmittedAt is the timestamp of the transaction that submitted the forecast
+		SubmittedAt time.Time `json:"submitted_at"`
+	}
+
+	// DemandAggregate is the total demand for a product in a fiscal year across all accounts.
+	DemandAggregate struct {
+		// Product is the name of the product.  Forecasts for the same product are aggregated regardless of case and
+		// surrounding whitespace, and the name used by the first forecast is reported.
+		Product    string `json:"product"`
+		FiscalYear int    `json:"fiscal_year"`
+		// Quantity is the total number of licenses forecast
+		Quantity int `json:"quantity"`
+		// Accounts maps each account that forecast demand for the product to the number of licenses it forecast
+		Accounts map[string]int `json:"accounts"`
+	}
+)
+
+// ForecastKey returns the key for a forecast in the private data collection of the account.  Forecasts are stored
+// with the format: "forecast=<account>:<forecast_id>".
+func ForecastKey(account, id string) string {
+	return fmt.Sprintf("forecast=%s:%s", account, id)
+}
+
+// ProductKey returns the name of the product used to aggregate forecasts.
+func ProductKey(product string) string {
+	return strings.ToLower(strings.TrimSpace(product))
+}
@@ -1,9 +1,64 @@
 package model
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
-type HistorySnapshot struct {
-	TxId      string    `json:"txid"`
-	Timestamp time.Time `json:"timestamp"`
-	Value     Account   `json:"account"`
+type (
+	HistorySnapshot struct {
+		TxId      string    `json:"txid"`
+		Timestamp time.Time `json:"timestamp"`
+		Value     Account   `json:"account"`
+	}
+
+	// Update records a change to the status of an account, checkout request, transfer, offer or acquisition.
+	Update struct {
+		// Previous is the status before the update.  It is only set for accounts, which keep their latest update.
+		Previous string `json:"previous,omitempty"`
+		// Status is the status after the update
+		Status string `json:"status"`
+		// Account is the account the update was made for, if it is not the account the record belongs to
+		Account string `json:"account,omitempty"`
+		// Approved is the number of licenses approved by the update of a checkout request
+		Approved int `json:"approved,omitempty"`
+		// Reason is the reason given for the update
+		Reason string `json:"reason,omitempty"`
+		// Actor is the user that made the update
+		Actor string `json:"actor"`
+		// TxID is the ID of the transaction that made the update
+		TxID string `json:"txid"`
+		// Timestamp is the timestamp of the transaction that made the update
+		Timestamp time.Time `json:"timestamp"`
+	}
+
+	// PrivateHistoryEntry records a single mutation of a private data key. Fabric does not keep the history of private
+	// data so an entry is written to the same collection as the key every time the key is written or deleted.
+	PrivateHistoryEntry struct {
+		// TxID is the ID of the transaction that mutated the key
+		TxID string `json:"txid"`
+		// Timestamp is the timestamp of the transaction that mutated the key
+		Timestamp time.Time `json:"timestamp"`
+		// Actor is the user that submitted the transaction
+		Actor string `json:"actor"`
+		// Operation is the name of the transaction that mutated the key
+		Operation string `json:"operation"`
+		// Hash is the hex encoded SHA-256 hash of the value written to the key. It is the same hash returned by
+		// GetPrivateDataHash so an entry can be checked against the current value. The hash is empty if the key was
+		// deleted.
+		Hash string `json:"hash"`
+	}
+)
+
+const PrivateHistoryPrefix = "history="
+
+// PrivateHistoryKey returns the key of the history entry of a private data key written in the given transaction. History
+// entries are stored with the format: "history=<key>:<txid>".
+func PrivateHistoryKey(key, txID string) string {
+	return fmt.Sprintf("%s%s", PrivateHistoryKeyPrefix(key), txID)
+}
+
+// PrivateHistoryKeyPrefix returns the prefix of all history entries of a private data key.
+func PrivateHistoryKeyPrefix(key string) string {
+	return fmt.Sprintf("%s%s:", PrivateHistoryPrefix, key)
 }
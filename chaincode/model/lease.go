@@ -0,0 +1,82 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type (
+	// LicenseLease is a license checked out by an account.
+	LicenseLease struct {
+		// Start is the date the account's lease of the license started.  It is not set for leases recorded before
+		// lease start dates were.
+		Start time.Time `json:"start,omitempty"`
+		// Expiration is the date the license expires
+		Expiration time.Time `json:"expiration"`
+		// LeaseEnd is the date the account's lease of the license ends.  It is never after the license expiration.
+		LeaseEnd time.Time `json:"lease_end"`
+		// SealedKey is the license key sealed to the encryption key of the account, if the license has a key and the
+		// account registered an encryption key when the license was checked out.  It is only stored in the account's
+		// private data collection.
+		SealedKey string `json:"sealed_key,omitempty"`
+	}
+)
+
+// MaxLeaseTermDays is the longest lease term, in days, that can be requested or added by a renewal.
+const MaxLeaseTermDays = 365
+
+// NewLicenseLease returns the lease of a license that starts at the given time and lasts the given number of days.
+// The lease ends when the license expires if that is sooner, or if the term is not set.
+func NewLicenseLease(expiration time.Time, start time.Time, termDays int) LicenseLease {
+	lease := LicenseLease{
+		Start:      start,
+		Expiration: expiration,
+		LeaseEnd:   expiration,
+	}
+
+	if termDays > 0 {
+		lease = lease.Extend(start, termDays)
+	}
+
+	return lease
+}
+
+// Extend returns the lease with the lease end set to the given number of days after the given time, but not after the
+// license expiration.
+func (l LicenseLease) Extend(from time.Time, termDays int) LicenseLease {
+	l.LeaseEnd = from.AddDate(0, 0, termDays)
+	if !l.Expiration.IsZero() && l.LeaseEnd.After(l.Expiration) {
+		l.LeaseEnd = l.Expiration
+	}
+
+	return l
+}
+
+// Unsealed returns the lease without the sealed license key, for the copy of the lease kept with the asset.
+func (l LicenseLease) Unsealed() LicenseLease {
+	l.SealedKey = ""
+	return l
+}
+
+// ValidateLeaseTerm checks that a lease term is between 1 and MaxLeaseTermDays days.
+func ValidateLeaseTerm(termDays int) error {
+	if termDays <= 0 || termDays > MaxLeaseTermDays {
+		return fmt.Errorf("lease term must be between 1 and %d days", MaxLeaseTermDays)
+	}
+
+	return nil
+}
+
+// UnmarshalJSON reads a license lease, accepting the license expirations that were stored before leases were recorded.
+// The lease of a legacy entry ends when the license expires.
+func (l *LicenseLease) UnmarshalJSON(bytes []byte) error {
+	var expiration time.Time
+	if err := json.Unmarshal(bytes, &expiration); err == nil {
+		*l = LicenseLease{Expiration: expiration, LeaseEnd: expiration}
+		return nil
+	}
+
+	type lease LicenseLease
+	return json.Unmarshal(bytes, (*lease)(l))
+}
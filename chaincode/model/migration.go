@@ -0,0 +1,13 @@
+package model
+
+type (
+	// DateMigration summarizes the records rewritten by a migration of legacy dates to RFC 3339 dates.
+	DateMigration struct {
+		// Assets are the IDs of the assets that were rewritten
+		Assets []string `json:"assets"`
+		// Accounts are the names of the accounts whose private info was rewritten
+		Accounts []string `json:"accounts"`
+		// Skipped maps the key of each record that could not be migrated to the reason it was skipped
+		Skipped map[string]string `json:"skipped"`
+	}
+)
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+type (
+	// Offer is a listing on the marketplace of licenses an account has checked out but does not need.  The offer is
+	// stored in the world state so every account can browse it.  The licenses offered are only stored in the private
+	// data collection of the offering account.
+	Offer struct {
+		// ID is the ID of the transaction that posted the offer
+		ID string `json:"id"`
+		// Asset is the asset the licenses belong to
+		Asset string `json:"asset"`
+		// Account is the account offering the licenses
+		Account string `json:"account"`
+		// Amount is the number of licenses offered
+		Amount int `json:"amount"`
+		// AvailableUntil is the date after which the offer can no longer be claimed
+		AvailableUntil time.Time `json:"available_until"`
+		// Status is the current status of the offer
+		Status OfferStatus `json:"status"`
+		// ClaimedBy is the account that claimed the offer
+		ClaimedBy string `json:"claimed_by,omitempty"`
+		// LeaseTermDays is the lease term, in days, requested by the account that claimed the offer
+		LeaseTermDays int `json:"lease_term_days,omitempty"`
+		// History records every change to the status of the offer, oldest first
+		History []Update `json:"history"`
+	}
+
+	// OfferLicenses are the licenses of an offer, stored in the private data collection of the offering account.
+	OfferLicenses struct {
+		Asset    string   `json:"asset"`
+		Licenses []string `json:"licenses"`
+	}
+
+	// OfferStatus is the status of an offer
+	OfferStatus string
+)
+
+const (
+	// OfferOpen is the status of an offer that can be claimed
+	OfferOpen OfferStatus = "OPEN"
+	// OfferClaimed is the status of an offer claimed by an account and waiting for the admin to move the licenses
+	OfferClaimed OfferStatus = "CLAIMED"
+	// OfferCompleted is the status of an offer whose licenses have been moved to the account that claimed it
+	OfferCompleted OfferStatus = "COMPLETED"
+	// OfferWithdrawn is the status of an offer withdrawn by the offering account or the admin
+	OfferWithdrawn OfferStatus = "WITHDRAWN"
+)
+
+const OfferPrefix = "offer:"
+
+// OfferKey returns the key for an offer on the ledger.  Offers are stored with the format: "offer:<offer_id>".
+func OfferKey(id string) string {
+	return fmt.Sprintf("%s%s", OfferPrefix, id)
+}
+
+// OfferKeyRange returns the start (inclusive) and end (exclusive) keys of the range of all offers in the world state.
+func OfferKeyRange() (string, string) {
+	// ';' is the character after ':' so the range covers every key with the offer prefix
+	return OfferPrefix, "offer;"
+}
+
+// IsOpen returns true if the offer has not been completed or withdrawn.
+func (o *Offer) IsOpen() bool {
+	return o.Status == OfferOpen || o.Status == OfferClaimed
+}
+
+// IsClaimable returns true if the offer is open, has not been claimed, and is still available at the given time.
+func (o *Offer) IsClaimable(now time.Time) bool {
+	return o.Status == OfferOpen && !now.After(o.AvailableUntil)
+}
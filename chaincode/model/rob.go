@@ -0,0 +1,60 @@
+package model
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+type (
+	// ROB is a version of the Rules of Behavior that each account must accept to use Blossom.
+	ROB struct {
+		// Version identifies the ROB document
+		Version string `json:"version"`
+		// Digest is the hex encoded SHA-256 digest of the ROB document
+		Digest string `json:"digest"`
+		// URI is the location of the ROB document
+		URI string `json:"uri,omitempty"`
+		// PublishedAt is the timestamp of the transaction that published the ROB
+		PublishedAt time.Time `json:"published_at"`
+		// AcceptBy is the end of the grace period for accounts to accept the ROB
+		AcceptBy time.Time `json:"accept_by"`
+		// Publisher is the user that published the ROB
+		Publisher string `json:"publisher"`
+	}
+
+	// ROBAcceptance records an account's acceptance of a version of the ROB. The acceptance is signed by the system
+	// owner as the submitter of the accepting transaction.
+	ROBAcceptance struct {
+		// Version is the version of the ROB that was accepted
+		Version string `json:"version"`
+		// Digest is the digest of the ROB document the system owner accepted
+		Digest string `json:"digest"`
+		// AcceptedBy is the system owner that accepted the ROB
+		AcceptedBy string `json:"accepted_by"`
+		// TxID is the ID of the transaction that accepted the ROB
+		TxID string `json:"txid"`
+		// Timestamp is the timestamp of the transaction that accepted the ROB
+		Timestamp time.Time `json:"timestamp"`
+	}
+)
+
+const (
+	ROBPrefix = "rob:"
+	// CurrentROBKey is the key of the version of the ROB that accounts must accept
+	CurrentROBKey = "rob_current"
+)
+
+// ROBKey returns the key for a version of the ROB on the ledger. ROBs are stored with the format: "rob:<version>".
+func ROBKey(version string) string {
+	return fmt.Sprintf("%s%s", ROBPrefix, version)
+}
+
+// ValidateDigest returns an error if the digest is not a hex encoded SHA-256 digest.
+func ValidateDigest(digest string) error {
+	if bytes, err := hex.DecodeString(digest); err != nil || len(bytes) != 32 {
+		return fmt.Errorf("digest must be a hex encoded SHA-256 digest")
+	}
+
+	return nil
+}
@@ -15,6 +15,10 @@ type (
 		Asset string `json:"asset"`
 		// License is the ID of the associated license
 		License string `json:"license"`
+		// Flagged is true if the associated asset was forcibly offboarded while the license was in use
+		Flagged bool `json:"flagged,omitempty"`
+		// FlagReason is the reason the asset was forcibly offboarded
+		FlagReason string `json:"flag_reason,omitempty"`
 	}
 )
 
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"fmt"
+)
+
+type (
+	// Transfer is a proposal to move checked out licenses of an asset directly from one account to another without
+	// returning them to the available pool.  The transfer is stored in the world state so both accounts can see it.  The
+	// licenses being transferred are only stored in the private data collection of the donating account.
+	Transfer struct {
+		// ID is the ID of the transaction that proposed the transfer
+		ID string `json:"id"`
+		// Asset is the asset the licenses belong to
+		Asset string `json:"asset"`
+		// From is the account donating the licenses
+		From string `json:"from"`
+		// To is the account receiving the licenses
+		To string `json:"to"`
+		// Amount is the number of licenses being transferred
+		Amount int `json:"amount"`
+		// Status is the current status of the transfer
+		Status TransferStatus `json:"status"`
+		// History records every change to the status of the transfer, oldest first
+		History []Update `json:"history"`
+	}
+
+	// TransferLicenses are the licenses of a transfer, stored in the private data collection of the donating account.
+	TransferLicenses struct {
+		Asset    string   `json:"asset"`
+		Licenses []string `json:"licenses"`
+	}
+
+	// TransferStatus is the status of a transfer
+	TransferStatus string
+)
+
+const (
+	// TransferProposed is the status of a transfer waiting for the recipient to accept it
+	TransferProposed TransferStatus = "PROPOSED"
+	// TransferAccepted is the status of a transfer accepted by the recipient and waiting for the admin to approve it
+	TransferAccepted TransferStatus = "ACCEPTED"
+	// TransferApproved is the status of a transfer whose licenses have been moved to the recipient
+	TransferApproved TransferStatus = "APPROVED"
+	// TransferCancelled is the status of a transfer cancelled by either account or the admin before it was approved
+	TransferCancelled TransferStatus = "CANCELLED"
+)
+
+const TransferPrefix = "transfer:"
+
+// TransferKey returns the key for a transfer on the ledger.  Transfers are stored with the format:
+// "transfer:<transfer_id>".
+func TransferKey(id string) string {
+	return fmt.Sprintf("%s%s", TransferPrefix, id)
+}
+
+// TransferKeyRange returns the start (inclusive) and end (exclusive) keys of the range of all transfers in the world
+// state.
+func TransferKeyRange() (string, string) {
+	// ';' is the character after ':' so the range covers every key with the transfer prefix
+	return TransferPrefix, "transfer;"
+}
+
+// IsOpen returns true if the transfer has not been approved or cancelled.
+func (t *Transfer) IsOpen() bool {
+	return t.Status == TransferProposed || t.Status == TransferAccepted
+}
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+type (
+	// Waitlist is the queue of approved checkout requests for an asset that are waiting for licenses to become
+	// available.  Entries are ordered by priority, highest first, and then by when they joined the waitlist.
+	Waitlist struct {
+		Asset   string          `json:"asset"`
+		Entries []WaitlistEntry `json:"entries"`
+	}
+
+	// WaitlistEntry is a checkout request waiting on the waitlist of an asset.
+	WaitlistEntry struct {
+		// Account is the account that made the request
+		Account string `json:"account"`
+		// RequestKey is the key of the request in the account's private data collection
+		RequestKey string `json:"request_key"`
+		// Amount is the number of licenses approved for the request
+		Amount int `json:"amount"`
+		// Priority is the priority given to the request when it was approved.  Higher priorities are offered licenses
+		// first.
+		Priority int `json:"priority"`
+		// AddedAt is the timestamp of the transaction that added the request to the waitlist
+		AddedAt time.Time `json:"added_at"`
+	}
+)
+
+const WaitlistPrefix = "waitlist:"
+
+// WaitlistKey returns the key for the waitlist of an asset in the catalog.  Waitlists are stored with the format:
+// "waitlist:<asset_id>".
+func WaitlistKey(assetID string) string {
+	return fmt.Sprintf("%s%s", WaitlistPrefix, assetID)
+}
+
+func NewWaitlist(assetID string) *Waitlist {
+	return &Waitlist{
+		Asset:   assetID,
+		Entries: make([]WaitlistEntry, 0),
+	}
+}
+
+// Add inserts the entry after every entry with the same or a higher priority.
+func (w *Waitlist) Add(entry WaitlistEntry) {
+	i := 0
+	for i < len(w.Entries) && w.Entries[i].Priority >= entry.Priority {
+		i++
+	}
+
+	w.Entries = append(w.Entries, WaitlistEntry{})
+	copy(w.Entries[i+1:], w.Entries[i:])
+	w.Entries[i] = entry
+}
+
+// Remove removes the entry for the request with the given key and returns true if it was on the waitlist.
+func (w *Waitlist) Remove(requestKey string) bool {
+	for i, entry := range w.Entries {
+		if entry.RequestKey == requestKey {
+			w.Entries = append(w.Entries[:i], w.Entries[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/usnistgov/blossom/chaincode/collections"
 	"github.com/usnistgov/blossom/chaincode/model"
 	"github.com/usnistgov/blossom/chaincode/ngac/common"
+	"github.com/usnistgov/blossom/chaincode/ngac/pap"
 )
 
 func process(ctx contractapi.TransactionContextInterface, evtCtx epp.EventContext, policyStore policy.Store) error {
@@ -20,7 +21,9 @@ func process(ctx contractapi.TransactionContextInterface, evtCtx epp.EventContex
 	return common.PutPvtCollPolicyStore(ctx, policyStore)
 }
 
-func ProcessApproveAccount(ctx contractapi.TransactionContextInterface, account string) error {
+// ProcessApproveAccount processes the approve_account event and registers the account's users in the graph. The users
+// map is the username of each user to their role.
+func ProcessApproveAccount(ctx contractapi.TransactionContextInterface, account string, users map[string]string) error {
 	user, err := common.GetUser(ctx)
 	if err != nil {
 		return err
@@ -39,38 +42,127 @@ func ProcessApproveAccount(ctx contractapi.TransactionContextInterface, account
 		},
 	}
 
-	return process(ctx, evtCtx, store)
+	if err = epp.NewEPP(store).ProcessEvent(evtCtx); err != nil {
+		return err
+	}
+
+	// the account UA is created by the event so users are added after processing it
+	if err = pap.SetAccountUsers(store, account, users); err != nil {
+		return err
+	}
+
+	return common.PutPvtCollPolicyStore(ctx, store)
 }
 
-func UpdateAccountStatusEvent(ctx contractapi.TransactionContextInterface, accountName, pvtColl string, status model.Status) error {
+// ProcessSetAccountUsers replaces the users registered with the account in the graph with the given users. The users
+// map is the username of each user to their role.
+func ProcessSetAccountUsers(ctx contractapi.TransactionContextInterface, account string, users map[string]string) error {
+	store, err := common.GetPvtCollPolicyStore(ctx, collections.Catalog())
+	if err != nil {
+		return fmt.Errorf("error getting ngac components: %w", err)
+	}
+
+	// the account's UA is created when it is approved
+	if ok, err := store.Graph().Exists(pap.AccountUA(account)); err != nil {
+		return fmt.Errorf("error checking if account %s is in the graph: %w", account, err)
+	} else if !ok {
+		return fmt.Errorf("account %s has not been approved", account)
+	}
+
+	if err = pap.SetAccountUsers(store, account, users); err != nil {
+		return err
+	}
+
+	return common.PutPvtCollPolicyStore(ctx, store)
+}
+
+// ProcessDecommissionAccount processes the decommission_account event, removing the account's nodes from the graph.
+func ProcessDecommissionAccount(ctx contractapi.TransactionContextInterface, account string) error {
+	user, err := common.GetUser(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting user from stub: %w", err)
+	}
+
 	store, err := common.GetPvtCollPolicyStore(ctx, collections.Catalog())
 	if err != nil {
+		return fmt.Errorf("error getting ngac components: %w", err)
+	}
+
+	// the account's nodes are created when it is approved, an account that was never approved has none to remove
+	if ok, err := store.Graph().Exists(pap.AccountObjectName(account)); err != nil {
+		return fmt.Errorf("error checking if account %s is in the graph: %w", account, err)
+	} else if !ok {
+		return nil
+	}
+
+	// the account UA cannot be removed while users are still assigned to it
+	if err = pap.SetAccountUsers(store, account, nil); err != nil {
 		return err
 	}
 
-	var f func(contractapi.TransactionContextInterface, string, string, policy.Store) error
+	evtCtx := epp.EventContext{
+		User:  user,
+		Event: "decommission_account",
+		Args: map[string]string{
+			"accountName": account,
+		},
+	}
+
+	return process(ctx, evtCtx, store)
+}
+
+func UpdateAccountStatusEvent(ctx contractapi.TransactionContextInterface, accountName, pvtColl string, status model.Status) error {
+	return UpdateAccountStatusEvents(ctx, pvtColl, map[string]model.Status{accountName: status})
+}
+
+// UpdateAccountStatusEvents processes the status event of each account in the statuses map. The policy store is read and
+// written once so several accounts can be updated in the same transaction.
+func UpdateAccountStatusEvents(ctx contractapi.TransactionContextInterface, pvtColl string, statuses map[string]model.Status) error {
+	user, err := common.GetUser(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting user from stub: %w", err)
+	}
+
+	store, err := common.GetPvtCollPolicyStore(ctx, pvtColl)
+	if err != nil {
+		return fmt.Errorf("error getting ngac components: %w", err)
+	}
+
+	eventProcessor := epp.NewEPP(store)
+	for accountName, status := range statuses {
+		event, err := accountStatusEvent(status)
+		if err != nil {
+			return err
+		}
+
+		evtCtx := epp.EventContext{
+			User:  user,
+			Event: event,
+			Args: map[string]string{
+				"accountName": accountName,
+			},
+		}
+
+		if err = eventProcessor.ProcessEvent(evtCtx); err != nil {
+			return fmt.Errorf("error processing %s event for account %s: %w", event, accountName, err)
+		}
+	}
+
+	return common.PutPvtCollPolicyStore(ctx, store)
+}
+
+func accountStatusEvent(status model.Status) (string, error) {
 	switch status {
-	case model.PendingApproval:
-		f = ProcessSetAccountPending
-	case model.PendingATO:
-		f = ProcessSetAccountPending
+	case model.PendingApproval, model.PendingATO:
+		return "set_account_pending", nil
 	case model.Authorized:
-		f = ProcessSetAccountActive
-	case model.UnauthorizedDenied:
-		f = ProcessSetAccountInactive
-	case model.UnauthorizedATO:
-		f = ProcessSetAccountInactive
-	case model.UnauthorizedOptOut:
-		f = ProcessSetAccountInactive
-	case model.UnauthorizedSecurityRisk:
-		f = ProcessSetAccountInactive
-	case model.UnauthorizedROB:
-		f = ProcessSetAccountInactive
+		return "set_account_active", nil
+	case model.UnauthorizedDenied, model.UnauthorizedATO, model.UnauthorizedOptOut, model.UnauthorizedSecurityRisk,
+		model.UnauthorizedROB:
+		return "set_account_inactive", nil
 	default:
-		return fmt.Errorf("unknown status: %s", status)
+		return "", fmt.Errorf("unknown status: %s", status)
 	}
-
-	return f(ctx, pvtColl, accountName, store)
 }
 
 func ProcessSetAccountActive(ctx contractapi.TransactionContextInterface, pvtCollName, account string, store policy.Store) error {
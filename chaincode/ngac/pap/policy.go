@@ -12,6 +12,7 @@ import (
 	"github.com/PM-Master/policy-machine-go/policy/author/remove"
 	"github.com/usnistgov/blossom/chaincode/adminmsp"
 	"github.com/usnistgov/blossom/chaincode/model"
+	"github.com/usnistgov/blossom/chaincode/ngac/common"
 )
 
 const (
@@ -29,6 +30,96 @@ func AccountUA(accountName string) string {
 	return fmt.Sprintf("%s_UA", accountName)
 }
 
+// AccountUserName returns the name of the user node representing a user registered with an account
+func AccountUserName(accountName, user string) string {
+	return common.FormatUsername(user, accountName)
+}
+
+// AddAccountUser creates a node for the user, assigned to the account's user attribute and the user attribute of the
+// user's role
+func AddAccountUser(policyStore policy.Store, accountName, user, role string) error {
+	if !model.IsValidRole(role) {
+		return fmt.Errorf("unrecognized role: %s", role)
+	}
+
+	return author.Author(policyStore,
+		create.User(AccountUserName(accountName, user)).In(AccountUA(accountName), role),
+	)
+}
+
+// HasAccountUsers reports whether any users are assigned to the account's user attribute
+func HasAccountUsers(policyStore policy.Store, accountName string) (bool, error) {
+	users, err := accountUsers(policyStore, accountName)
+	if err != nil {
+		return false, err
+	}
+
+	return len(users) > 0, nil
+}
+
+// SetAccountUsers assigns the users to the account's user attribute and the user attribute of their role, and removes
+// any other users assigned to the account's user attribute.  The users map is the username of each user to their role.
+// Users that are already assigned with the same role are left in place.
+func SetAccountUsers(policyStore policy.Store, accountName string, users map[string]string) error {
+	registered, err := accountUsers(policyStore, accountName)
+	if err != nil {
+		return err
+	}
+
+	graph := policyStore.Graph()
+	for username, role := range users {
+		name := AccountUserName(accountName, username)
+		if _, ok := registered[name]; ok {
+			parents, err := graph.GetParents(name)
+			if err != nil {
+				return fmt.Errorf("error getting user attributes of %s: %w", name, err)
+			}
+
+			if _, ok = parents[role]; ok {
+				delete(registered, name)
+				continue
+			}
+
+			// the user's role changed so the node is created again with the new role
+			if err = graph.DeleteNode(name); err != nil {
+				return fmt.Errorf("error removing user %s: %w", name, err)
+			}
+
+			delete(registered, name)
+		}
+
+		if err = AddAccountUser(policyStore, accountName, username, role); err != nil {
+			return fmt.Errorf("error adding user %s to account %s: %w", username, accountName, err)
+		}
+	}
+
+	// the users left are no longer registered with the account
+	for name := range registered {
+		if err = graph.DeleteNode(name); err != nil {
+			return fmt.Errorf("error removing user %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// accountUsers returns the user nodes assigned to the account's user attribute
+func accountUsers(policyStore policy.Store, accountName string) (map[string]policy.Node, error) {
+	children, err := policyStore.Graph().GetChildren(AccountUA(accountName))
+	if err != nil {
+		return nil, fmt.Errorf("error getting users of account %s: %w", accountName, err)
+	}
+
+	users := make(map[string]policy.Node)
+	for name, node := range children {
+		if node.Kind == policy.User {
+			users[name] = node
+		}
+	}
+
+	return users, nil
+}
+
 // AdminUA returns the name of the user attribute representing the admin member
 func AdminUA() string {
 	return fmt.Sprintf("%s_UA", adminmsp.AdminMSP)
@@ -88,11 +179,14 @@ func LoadCatalogPolicy() (policy.Store, error) {
 		create.UserAttribute(AccountsUserAttrInRBAC).In(RbacUserAttr),
 
 		grant.UserAttribute(model.SystemOwnerRole).
-			Permissions("upload_ato").
+			Permissions("upload_ato", "update_account_agency", "accept_rob", "register_encryption_key", "manage_account_users").
 			On(AccountsObjectAttrInRBAC),
 		grant.UserAttribute(model.SystemAdminRole).
 			Permissions("check_out", "initiate_check_in", "report_swid", "delete_swid").
 			On(AccountsObjectAttrInRBAC),
+		grant.UserAttribute(model.AcquisitionSpecialistRole).
+			Permissions("request_acquisition", "attach_quote", "record_purchase").
+			On(AccountsObjectAttrInRBAC),
 
 		// assets policy
 		create.PolicyClass(AssetsPolicyClass),
@@ -115,7 +209,7 @@ func LoadCatalogPolicy() (policy.Store, error) {
 		create.Object(AllAssetsObj).In(AssetsObjectAttr),
 
 		grant.UserAttribute(adminUA).Permissions(policy.AllOps).On(AssetsObjectAttr),
-		grant.UserAttribute(AssetManagersAttr).Permissions("onboard_asset", "offboard_asset", "view_assets", "view_asset_private", "view_asset_public").On(AssetsObjectAttr),
+		grant.UserAttribute(AssetManagersAttr).Permissions("onboard_asset", "offboard_asset", "update_asset", "view_assets", "view_asset_private", "view_asset_public").On(AssetsObjectAttr),
 
 		create.Obligation("onboard_asset").
 			When(policy.AnyUserSubject).
@@ -157,7 +251,7 @@ func LoadCatalogPolicy() (policy.Store, error) {
 
 		// grants
 		grant.UserAttribute(ActiveAttr).Permissions(policy.AllOps).On(AccountsObjectAttrInStatusPC),
-		grant.UserAttribute(PendingAttr).Permissions("upload_ato").On(AccountsObjectAttrInStatusPC),
+		grant.UserAttribute(PendingAttr).Permissions("upload_ato", "update_account_agency", "accept_rob", "register_encryption_key", "manage_account_users").On(AccountsObjectAttrInStatusPC),
 		grant.UserAttribute(ActiveAttr).Permissions("view_assets", "view_asset_public").On(CatalogObjectAttrInStatusPC),
 
 		create.Obligation("set_account_active").
@@ -198,6 +292,13 @@ func LoadCatalogPolicy() (policy.Store, error) {
 				// create a UA for the account
 				create.UserAttribute(AccountUA("<accountName>")).In(AccountsUserAttrInAssetsPC, PendingAttr),
 			),
+		create.Obligation("decommission_account").
+			When(policy.AnyUserSubject).
+			Performs("decommission_account", "accountName").
+			Do(
+				remove.Object(AccountObjectName("<accountName>")),
+				remove.UserAttribute(AccountUA("<accountName>")),
+			),
 	)
 
 	if err != nil {
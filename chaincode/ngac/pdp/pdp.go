@@ -58,6 +58,38 @@ func CanUpdateAccountStatus(ctx contractapi.TransactionContextInterface, account
 	return check(ctx, pap.AccountObjectName(account), "update_account_status")
 }
 
+func CanUpdateAccountAgency(ctx contractapi.TransactionContextInterface, account string) error {
+	return check(ctx, pap.AccountObjectName(account), "update_account_agency")
+}
+
+func CanDecommissionAccount(ctx contractapi.TransactionContextInterface, account string) error {
+	return check(ctx, pap.BlossomObject, "decommission_account")
+}
+
+func CanSweepExpiredATOs(ctx contractapi.TransactionContextInterface) error {
+	return check(ctx, pap.BlossomObject, "sweep_expired_atos")
+}
+
+func CanPublishROB(ctx contractapi.TransactionContextInterface) error {
+	return check(ctx, pap.BlossomObject, "publish_rob")
+}
+
+func CanRegisterEncryptionKey(ctx contractapi.TransactionContextInterface, account string) error {
+	return check(ctx, pap.AccountObjectName(account), "register_encryption_key")
+}
+
+func CanSealLicenseKeys(ctx contractapi.TransactionContextInterface, account string) error {
+	return check(ctx, pap.AccountObjectName(account), "seal_license_keys")
+}
+
+func CanAcceptROB(ctx contractapi.TransactionContextInterface, account string) error {
+	return check(ctx, pap.AccountObjectName(account), "accept_rob")
+}
+
+func CanEnforceROB(ctx contractapi.TransactionContextInterface) error {
+	return check(ctx, pap.BlossomObject, "enforce_rob")
+}
+
 func CanRequestCheckout(ctx contractapi.TransactionContextInterface, account string) error {
 	return check(ctx, pap.AccountObjectName(account), "check_out")
 }
@@ -66,6 +98,64 @@ func CanApproveCheckout(ctx contractapi.TransactionContextInterface, account str
 	return check(ctx, pap.BlossomObject, "approve_checkout")
 }
 
+// CanRequestLeaseRenewal checks that the user can request the renewal of the leases of the account's licenses. Any
+// user that can request a checkout for the account can request a renewal.
+func CanRequestLeaseRenewal(ctx contractapi.TransactionContextInterface, account string) error {
+	return check(ctx, pap.AccountObjectName(account), "check_out")
+}
+
+func CanProcessLeaseRenewal(ctx contractapi.TransactionContextInterface, account string) error {
+	return check(ctx, pap.BlossomObject, "process_lease_renewal")
+}
+
+func CanDenyCheckout(ctx contractapi.TransactionContextInterface, account string) error {
+	return check(ctx, pap.BlossomObject, "deny_checkout")
+}
+
+// CanCancelCheckout checks that the user can cancel a checkout request of the account. Any user that can request a
+// checkout for the account can cancel it.
+func CanCancelCheckout(ctx contractapi.TransactionContextInterface, account string) error {
+	return check(ctx, pap.AccountObjectName(account), "check_out")
+}
+
+// CanProposeTransfer checks that the user can propose a transfer of the account's licenses to another account. Any
+// user that can initiate a checkin for the account can propose a transfer.
+func CanProposeTransfer(ctx contractapi.TransactionContextInterface, account string) error {
+	return check(ctx, pap.AccountObjectName(account), "initiate_check_in")
+}
+
+// CanAcceptTransfer checks that the user can accept a transfer of licenses to the account. Any user that can request a
+// checkout for the account can accept a transfer.
+func CanAcceptTransfer(ctx contractapi.TransactionContextInterface, account string) error {
+	return check(ctx, pap.AccountObjectName(account), "check_out")
+}
+
+func CanApproveTransfer(ctx contractapi.TransactionContextInterface) error {
+	return check(ctx, pap.BlossomObject, "approve_transfer")
+}
+
+// CanPostOffer checks that the user can offer licenses checked out by the account on the marketplace. Any user that can
+// initiate a checkin for the account can post an offer.
+func CanPostOffer(ctx contractapi.TransactionContextInterface, account string) error {
+	return check(ctx, pap.AccountObjectName(account), "initiate_check_in")
+}
+
+// CanViewOffers checks that the user can browse the offers on the marketplace. Any user that can view the public info of
+// the assets can browse the offers.
+func CanViewOffers(ctx contractapi.TransactionContextInterface) error {
+	return check(ctx, "all_assets", "view_asset_public")
+}
+
+// CanClaimOffer checks that the user can claim an offer on the marketplace for the account. Any user that can request
+// a checkout for the account can claim an offer.
+func CanClaimOffer(ctx contractapi.TransactionContextInterface, account string) error {
+	return check(ctx, pap.AccountObjectName(account), "check_out")
+}
+
+func CanProcessOfferClaim(ctx contractapi.TransactionContextInterface) error {
+	return check(ctx, pap.BlossomObject, "process_offer_claim")
+}
+
 func CanInitiateCheckIn(ctx contractapi.TransactionContextInterface, account string) error {
 	return check(ctx, pap.AccountObjectName(account), "initiate_check_in")
 }
@@ -90,6 +180,56 @@ func CanOffboardAsset(ctx contractapi.TransactionContextInterface) error {
 	return check(ctx, "assets", "offboard_asset")
 }
 
+func CanUpdateAsset(ctx contractapi.TransactionContextInterface) error {
+	return check(ctx, "assets", "update_asset")
+}
+
+func CanSweepExpiredLicenses(ctx contractapi.TransactionContextInterface) error {
+	return check(ctx, pap.BlossomObject, "sweep_expired_licenses")
+}
+
+func CanViewExpiringAssets(ctx contractapi.TransactionContextInterface) error {
+	return check(ctx, pap.BlossomObject, "view_expiring_assets")
+}
+
+func CanMigrateDates(ctx contractapi.TransactionContextInterface) error {
+	return check(ctx, pap.BlossomObject, "migrate_dates")
+}
+
+func CanRequestAcquisition(ctx contractapi.TransactionContextInterface, account string) error {
+	return check(ctx, pap.AccountObjectName(account), "request_acquisition")
+}
+
+func CanAttachQuote(ctx contractapi.TransactionContextInterface, account string) error {
+	return check(ctx, pap.AccountObjectName(account), "attach_quote")
+}
+
+func CanApproveAcquisition(ctx contractapi.TransactionContextInterface) error {
+	return check(ctx, pap.BlossomObject, "approve_acquisition")
+}
+
+func CanRecordPurchase(ctx contractapi.TransactionContextInterface, account string) error {
+	return check(ctx, pap.AccountObjectName(account), "record_purchase")
+}
+
+func CanOnboardAcquisition(ctx contractapi.TransactionContextInterface) error {
+	return check(ctx, pap.BlossomObject, "onboard_acquisition")
+}
+
+// CanSubmitForecast checks that the user can submit a demand forecast for the account. Any user that can request a
+// checkout for the account can submit a forecast.
+func CanSubmitForecast(ctx contractapi.TransactionContextInterface, account string) error {
+	return check(ctx, pap.AccountObjectName(account), "check_out")
+}
+
+func CanViewDemand(ctx contractapi.TransactionContextInterface) error {
+	return check(ctx, pap.BlossomObject, "view_demand")
+}
+
+func CanViewCostAllocation(ctx contractapi.TransactionContextInterface) error {
+	return check(ctx, pap.BlossomObject, "view_cost_allocation")
+}
+
 func CanViewAssetPrivate(ctx contractapi.TransactionContextInterface) error {
 	return check(ctx, "all_assets", "view_asset_private")
 }
@@ -102,12 +242,15 @@ func CanViewAssets(ctx contractapi.TransactionContextInterface) error {
 	return check(ctx, "all_assets", "view_assets")
 }
 
-func check(ctx contractapi.TransactionContextInterface, target, permission string) error {
-	user, err := common.GetUsername(ctx)
-	if err != nil {
-		return fmt.Errorf("error getting user: %v", err)
-	}
+func CanManageAccountUsers(ctx contractapi.TransactionContextInterface, account string) error {
+	return check(ctx, pap.AccountObjectName(account), "manage_account_users")
+}
+
+func CanProcessAccountUsers(ctx contractapi.TransactionContextInterface) error {
+	return check(ctx, pap.BlossomObject, "process_account_users")
+}
 
+func check(ctx contractapi.TransactionContextInterface, target, permission string) error {
 	policyStore, err := common.GetPvtCollPolicyStore(ctx, collections.Catalog())
 	if err != nil {
 		return err
@@ -118,27 +261,36 @@ func check(ctx contractapi.TransactionContextInterface, target, permission strin
 		return err
 	}
 
-	userAttributes := make([]string, 0)
+	var user string
 	if account == adminmsp.AdminMSP {
 		// adminmsp users need to have the admin role
 		if err = ctx.GetClientIdentity().AssertAttributeValue(model.AdminAttribute, "true"); err != nil {
 			return fmt.Errorf("adminmsp users need to have the attribute blossom.admin=true")
 		}
 
-		userAttributes = append(userAttributes, pap.AdminUA())
+		if user, err = common.GetUsername(ctx); err != nil {
+			return fmt.Errorf("error getting user: %v", err)
+		}
+
+		// assign the user to the admin user attribute
+		if _, err = policyStore.Graph().CreateNode(user, policy.User, nil, pap.AdminUA()); err != nil {
+			return fmt.Errorf("error assigning user %s to user attribute %s: %v", user, pap.AdminUA(), err)
+		}
 	} else {
-		// if user is not in the adminmsp, get the role they have in their account
-		role, err := getRole(ctx)
+		// if user is not in the adminmsp, they must be registered with their account in the graph
+		username, err := common.GetUsername(ctx)
 		if err != nil {
-			return err
+			return fmt.Errorf("error getting user: %v", err)
 		}
 
-		userAttributes = append(userAttributes, role)
-	}
-
-	// assign the user to the account and role
-	if _, err = policyStore.Graph().CreateNode(user, policy.User, nil, pap.AccountUA(account), userAttributes...); err != nil {
-		return fmt.Errorf("error assigning user %s to user attributes %s: %v", user, userAttributes, err)
+		user = pap.AccountUserName(account, username)
+		if ok, err := policyStore.Graph().Exists(user); err != nil {
+			return fmt.Errorf("error checking if user %s exists: %w", user, err)
+		} else if !ok {
+			if err = assignUnregisteredUser(ctx, policyStore, account, username); err != nil {
+				return err
+			}
+		}
 	}
 
 	decider := pdp.NewDecider(policyStore.Graph(), policyStore.Prohibitions())
@@ -151,6 +303,29 @@ func check(ctx contractapi.TransactionContextInterface, target, permission strin
 	return nil
 }
 
+// assignUnregisteredUser assigns a user that is not registered in the graph to the account and the role in the
+// blossom.role attribute of their certificate.  This is only done for accounts that have no users registered in the
+// graph, i.e. accounts approved before users were registered, so their users keep access until the admin registers the
+// account's users with ProcessAccountUsers.  The assignment is not persisted.
+func assignUnregisteredUser(ctx contractapi.TransactionContextInterface, policyStore policy.Store, account, username string) error {
+	if ok, err := pap.HasAccountUsers(policyStore, account); err != nil {
+		return err
+	} else if ok {
+		return fmt.Errorf("user %s is not registered with account %s", username, account)
+	}
+
+	role, err := getRole(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = pap.AddAccountUser(policyStore, account, username, role); err != nil {
+		return fmt.Errorf("error assigning user %s to account %s: %v", username, account, err)
+	}
+
+	return nil
+}
+
 func getRole(ctx contractapi.TransactionContextInterface) (role string, err error) {
 	var ok bool
 	if role, ok, err = ctx.GetClientIdentity().GetAttributeValue(model.RoleAttribute); err != nil {
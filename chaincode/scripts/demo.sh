@@ -102,11 +102,31 @@ InitNGAC() {
 OnboardAsset() {
   setUser $1
   asset=$2
-  export LICENSES=$(echo -n "{\"licenses\":[{\"license_id\": \"asset$asset-license-1\", \"expiration\": \"exp\"}, {\"license_id\": \"asset$asset-license-2\", \"expiration\": \"exp\"}, {\"license_id\": \"asset$asset-license-3\", \"expiration\": \"exp\"}, {\"license_id\": \"asset$asset-license-4\", \"expiration\": \"exp\"}]}" | base64 | tr -d \\n)
+  export LICENSES=$(echo -n "{\"licenses\":[{\"license_id\": \"asset$asset-license-1\", \"expiration\": \"2025-01-01T00:00:00Z\"}, {\"license_id\": \"asset$asset-license-2\", \"expiration\": \"2025-01-01T00:00:00Z\"}, {\"license_id\": \"asset$asset-license-3\", \"expiration\": \"2025-01-01T00:00:00Z\"}, {\"license_id\": \"asset$asset-license-4\", \"expiration\": \"2025-01-01T00:00:00Z\"}]}" | base64 | tr -d \\n)
   peer chaincode invoke -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
     --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
     -C mychannel -n blossomcc \
-    -c  '{"Args":["OnboardAsset", "10'"$asset"'", "asset'"$asset"'", "01/01/2022", "01/01/2025"]}' --transient "{\"asset\":\"$LICENSES\"}"
+    -c  '{"Args":["OnboardAsset", "10'"$asset"'", "asset'"$asset"'", "2022-01-01T00:00:00Z", "2026-01-01T00:00:00Z"]}' --transient "{\"asset\":\"$LICENSES\"}"
+}
+
+AddLicenses() {
+  setUser $1
+  asset=$2
+  export LICENSES=$(echo -n "{\"licenses\":[{\"license_id\": \"asset$asset-license-5\", \"expiration\": \"2025-01-01T00:00:00Z\"}]}" | base64 | tr -d \\n)
+  peer chaincode invoke -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc \
+    -c  '{"Args":["AddLicenses", "10'"$asset"'"]}' --transient "{\"licenses\":\"$LICENSES\"}"
+}
+
+RetireLicenses() {
+  setUser $1
+  asset=$2
+  export LICENSES=$(echo -n "{\"licenses\":[\"asset$asset-license-5\"]}" | base64 | tr -d \\n)
+  peer chaincode invoke -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc \
+    -c  '{"Args":["RetireLicenses", "10'"$asset"'"]}' --transient "{\"licenses\":\"$LICENSES\"}"
 }
 
 Assets() {
@@ -127,7 +147,9 @@ RequestAccount() {
   sysOwner=$2
   sysAdmin=$3
   acqSpec=$4
-  export ACCOUNT=$(echo -n "{\"system_owner\":\"$sysOwner\",\"system_admin\":\"$sysAdmin\",\"acquisition_specialist\": \"$acqSpec\"}" | base64 | tr -d \\n)
+  department=$5
+  subAgency=$6
+  export ACCOUNT=$(echo -n "{\"system_owner\":\"$sysOwner\",\"system_admin\":\"$sysAdmin\",\"acquisition_specialist\": \"$acqSpec\",\"department\":\"$department\",\"sub_agency\":\"$subAgency\"}" | base64 | tr -d \\n)
   peer chaincode invoke -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
     --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
     -C mychannel -n blossomcc -c  \
@@ -153,7 +175,7 @@ ApproveOrg3Account() {
 
 UploadATOOrg2() {
   setUser $1
-  export ATO=$(echo -n "{\"ato\":\"org2 test ato\"}" | base64 | tr -d \\n)
+  export ATO=$(echo -n "{\"authorizing_official\":\"org2 official\",\"system_name\":\"org2 system\",\"issue_date\":\"2024-01-01T00:00:00Z\",\"expiration_date\":\"2027-01-01T00:00:00Z\",\"impact_level\":\"MODERATE\",\"digest\":\"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08\"}" | base64 | tr -d \\n)
   peer chaincode invoke -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
     --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
     -C mychannel -n blossomcc \
@@ -162,13 +184,247 @@ UploadATOOrg2() {
 
 UploadATOOrg3() {
   setUser $1
-  export ATO=$(echo -n "{\"ato\":\"org3 test ato\"}" | base64 | tr -d \\n)
+  export ATO=$(echo -n "{\"authorizing_official\":\"org3 official\",\"system_name\":\"org3 system\",\"issue_date\":\"2024-01-01T00:00:00Z\",\"expiration_date\":\"2027-01-01T00:00:00Z\",\"impact_level\":\"MODERATE\",\"digest\":\"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08\"}" | base64 | tr -d \\n)
   peer chaincode invoke -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
     --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
     -C mychannel -n blossomcc \
     -c  '{"Args":["UploadATO"]}' --transient "{\"ato\":\"$ATO\"}"
 }
 
+RegisterEncryptionKey() {
+  setUser $1
+  # the public key PEM with its newlines escaped for JSON
+  key=$(awk '{printf "%s\\n", $0}' "$2")
+  export ENCRYPTION_KEY=$(echo -n "{\"public_key\":\"$key\"}" | base64 | tr -d \\n)
+  peer chaincode invoke -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc \
+    -c  '{"Args":["RegisterEncryptionKey"]}' --transient "{\"encryption_key\":\"$ENCRYPTION_KEY\"}"
+}
+
+SealLicenseKeys() {
+  setUser $1
+  account=$2
+  peer chaincode invoke -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc \
+    -c  '{"Args":["SealLicenseKeys", "'"$account"'"]}'
+}
+
+PublishROB() {
+  setUser $1
+  version=$2
+  digest=$3
+  uri=$4
+  grace=$5
+  peer chaincode invoke -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc \
+    -c  '{"Args":["PublishROB", "'"$version"'", "'"$digest"'", "'"$uri"'", "'"$grace"'"]}'
+}
+
+AcceptROB() {
+  setUser $1
+  version=$2
+  digest=$3
+  peer chaincode invoke -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc \
+    -c  '{"Args":["AcceptROB", "'"$version"'", "'"$digest"'"]}'
+}
+
+EnforceROB() {
+  setUser $1
+  peer chaincode invoke -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc \
+    -c  '{"Args":["EnforceROB"]}'
+}
+
+SweepExpiredATOs() {
+  setUser $1
+  peer chaincode invoke -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc \
+    -c  '{"Args":["SweepExpiredATOs"]}'
+}
+
+MigrateDates() {
+  setUser $1
+  peer chaincode invoke -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc \
+    -c  '{"Args":["MigrateDates"]}'
+}
+
+SweepExpiredLicenses() {
+  setUser $1
+  peer chaincode invoke -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc \
+    -c  '{"Args":["SweepExpiredLicenses"]}'
+}
+
+LicenseRollup() {
+  setUser $1
+  peer chaincode query -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc -c  '{"Args":["GetLicenseRollup"]}'
+}
+
+ExpiringAssets() {
+  setUser $1
+  days=$2
+  peer chaincode query -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc -c  '{"Args":["GetExpiringAssets", "'"$days"'"]}'
+}
+
+UpdateAssetCost() {
+  setUser $1
+  asset=$2
+  cents=$3
+  export COST=$(echo -n "{\"unit_cost_cents\":$cents,\"funding_source\":\"O&M\",\"contract_line_item\":\"0001\"}" | base64 | tr -d \\n)
+  peer chaincode invoke -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc \
+    -c  '{"Args":["UpdateAssetCost", "10'"$asset"'"]}' --transient "{\"cost\":\"$COST\"}"
+}
+
+UpdateAllocationStrategy() {
+  setUser $1
+  asset=$2
+  strategy=$3
+  peer chaincode invoke -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc \
+    -c  '{"Args":["UpdateAllocationStrategy", "10'"$asset"'", "'"$strategy"'"]}'
+}
+
+ForceOffboardAsset() {
+  setUser $1
+  asset=$2
+  reason=$3
+  peer chaincode invoke -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc \
+    -c  '{"Args":["ForceOffboardAsset", "10'"$asset"'", "'"$reason"'"]}'
+}
+
+Chargebacks() {
+  setUser $1
+  account=$2
+  peer chaincode query -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc -c '{"Args":["GetChargebacks", "'"$account"'"]}'
+}
+
+CostAllocation() {
+  setUser $1
+  period=$2
+  peer chaincode query -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc -c '{"Args":["GetCostAllocation", "'"$period"'"]}'
+}
+
+RequestAcquisition() {
+  setUser $1
+  product=$2
+  quantity=$3
+  justification=$4
+  export ACQUISITION=$(echo -n "{\"product\":\"$product\",\"quantity\":$quantity,\"justification\":\"$justification\"}" | base64 | tr -d \\n)
+  peer chaincode invoke -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc \
+    -c  '{"Args":["RequestAcquisition"]}' --transient "{\"acquisition\":\"$ACQUISITION\"}"
+}
+
+AttachQuote() {
+  setUser $1
+  id=$2
+  vendor=$3
+  digest=$4
+  cents=$5
+  export QUOTE=$(echo -n "{\"vendor\":\"$vendor\",\"digest\":\"$digest\",\"amount_cents\":$cents}" | base64 | tr -d \\n)
+  peer chaincode invoke -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc \
+    -c  '{"Args":["AttachQuote", "'"$id"'"]}' --transient "{\"quote\":\"$QUOTE\"}"
+}
+
+ApproveAcquisition() {
+  setUser $1
+  account=$2
+  id=$3
+  peer chaincode invoke -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc \
+    -c  '{"Args":["ApproveAcquisition", "'"$account"'", "'"$id"'"]}'
+}
+
+RecordPurchase() {
+  setUser $1
+  id=$2
+  order=$3
+  digest=$4
+  export PURCHASE=$(echo -n "{\"order_number\":\"$order\",\"quote_digest\":\"$digest\",\"licenses\":[{\"license_id\":\"$id-license-1\"},{\"license_id\":\"$id-license-2\"}]}" | base64 | tr -d \\n)
+  peer chaincode invoke -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc \
+    -c  '{"Args":["RecordPurchase", "'"$id"'"]}' --transient "{\"purchase\":\"$PURCHASE\"}"
+}
+
+OnboardAcquisition() {
+  setUser $1
+  account=$2
+  id=$3
+  asset=$4
+  name=$5
+  onboard=$(date -u +%Y-%m-%dT%H:%M:%SZ)
+  expiration=$(date -u -d "+1 year" +%Y-%m-%dT%H:%M:%SZ)
+  peer chaincode invoke -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc \
+    -c  '{"Args":["OnboardAcquisition", "'"$account"'", "'"$id"'", "'"$asset"'", "'"$name"'", "'"$onboard"'", "'"$expiration"'"]}'
+}
+
+Acquisitions() {
+  setUser $1
+  account=$2
+  peer chaincode query -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc -c '{"Args":["GetAcquisitions", "'"$account"'"]}'
+}
+
+SubmitForecast() {
+  setUser $1
+  product=$2
+  quantity=$3
+  fiscal_year=$4
+  justification=$5
+  export FORECAST=$(echo -n "{\"product\":\"$product\",\"quantity\":$quantity,\"fiscal_year\":$fiscal_year,\"justification\":\"$justification\"}" | base64 | tr -d \\n)
+  peer chaincode invoke -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc \
+    -c  '{"Args":["SubmitForecast"]}' --transient "{\"forecast\":\"$FORECAST\"}"
+}
+
+Forecasts() {
+  setUser $1
+  account=$2
+  peer chaincode query -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc -c '{"Args":["GetForecasts", "'"$account"'"]}'
+}
+
+Demand() {
+  setUser $1
+  fiscal_year=$2
+  peer chaincode query -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc -c '{"Args":["GetDemand", "'"$fiscal_year"'"]}'
+}
+
 Accounts() {
   setUser $1
   peer chaincode query -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
@@ -185,26 +441,28 @@ Account() {
 UpdateOrg2Status() {
   setUser $1
   status=$2
+  reason=$3
   peer chaincode invoke -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
     --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
     -C mychannel -n blossomcc \
-    -c  '{"Args":["UpdateAccountStatus", "Org2MSP", "'"$status"'"]}'
+    -c  '{"Args":["UpdateAccountStatus", "Org2MSP", "'"$status"'", "'"$reason"'"]}'
 }
 
 UpdateOrg3Status() {
   setUser $1
   status=$2
+  reason=$3
   peer chaincode invoke -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
     --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
     -C mychannel -n blossomcc \
-    -c  '{"Args":["UpdateAccountStatus", "Org3MSP", "'"$status"'"]}'
+    -c  '{"Args":["UpdateAccountStatus", "Org3MSP", "'"$status"'", "'"$reason"'"]}'
 }
 
 Org2RequestCheckout() {
   setUser $1
   asset=$2
   amount=$3
-  export CHECKOUT=$(echo -n "{\"asset_id\":\"10$asset\",\"amount\":$amount}" | base64 | tr -d \\n)
+  export CHECKOUT=$(echo -n "{\"asset_id\":\"10$asset\",\"amount\":$amount,\"justification\":\"demo\",\"lease_term_days\":90,\"fisma_system\":\"demo system\",\"needed_by\":\"2030-01-01T00:00:00Z\"}" | base64 | tr -d \\n)
   peer chaincode invoke -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
     --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
     -C mychannel -n blossomcc \
@@ -215,7 +473,7 @@ Org3RequestCheckout() {
   setUser $1
   asset=$2
   amount=$3
-  export CHECKOUT=$(echo -n "{\"asset_id\":\"10$asset\",\"amount\":$amount}" | base64 | tr -d \\n)
+  export CHECKOUT=$(echo -n "{\"asset_id\":\"10$asset\",\"amount\":$amount,\"justification\":\"demo\",\"lease_term_days\":90,\"fisma_system\":\"demo system\",\"needed_by\":\"2030-01-01T00:00:00Z\"}" | base64 | tr -d \\n)
   peer chaincode invoke -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
     --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
     -C mychannel -n blossomcc \
@@ -235,13 +493,47 @@ ApproveCheckout() {
   setUser $1
   account=$2
   asset=$3
-  export CHECKOUT=$(echo -n "{\"account\":\"$account\",\"asset_id\":\"10$asset\"}" | base64 | tr -d \\n)
+  amount=${4:-0}
+  priority=${5:-0}
+  export CHECKOUT=$(echo -n "{\"account\":\"$account\",\"asset_id\":\"10$asset\",\"amount\":$amount,\"priority\":$priority}" | base64 | tr -d \\n)
   peer chaincode invoke -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
     --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
     -C mychannel -n blossomcc \
     -c  '{"Args":["ApproveCheckout"]}' --transient "{\"checkout\":\"$CHECKOUT\"}"
 }
 
+Waitlist() {
+  setUser $1
+  asset=$2
+  peer chaincode query -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc \
+    -c '{"Args":["GetWaitlist", "10'"$asset"'"]}'
+}
+
+DenyCheckout() {
+  setUser $1
+  account=$2
+  asset=$3
+  reason=$4
+  export CHECKOUT=$(echo -n "{\"account\":\"$account\",\"asset_id\":\"10$asset\",\"reason\":\"$reason\"}" | base64 | tr -d \\n)
+  peer chaincode invoke -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc \
+    -c  '{"Args":["DenyCheckout"]}' --transient "{\"checkout\":\"$CHECKOUT\"}"
+}
+
+CancelCheckout() {
+  setUser $1
+  asset=$2
+  reason=$3
+  export CHECKOUT=$(echo -n "{\"asset_id\":\"10$asset\",\"reason\":\"$reason\"}" | base64 | tr -d \\n)
+  peer chaincode invoke -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc \
+    -c  '{"Args":["CancelCheckout"]}' --transient "{\"checkout\":\"$CHECKOUT\"}"
+}
+
 InitiateCheckin() {
   setUser $1
   asset=$2
@@ -272,6 +564,124 @@ ProcessCheckin() {
     -c  '{"Args":["ProcessCheckin"]}' --transient "{\"checkin\":\"$CHECKIN\"}"
 }
 
+RequestLeaseRenewal() {
+  setUser $1
+  asset=$2
+  term_days=$3
+  export RENEWAL=$(echo -n "{\"asset_id\":\"10$asset\",\"term_days\":$term_days}" | base64 | tr -d \\n)
+  peer chaincode invoke -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc \
+    -c  '{"Args":["RequestLeaseRenewal"]}' --transient "{\"renewal\":\"$RENEWAL\"}"
+}
+
+ProcessLeaseRenewal() {
+  setUser $1
+  asset_id=$2
+  account=$3
+  export RENEWAL=$(echo -n "{\"asset_id\":\"10$asset_id\",\"account\":\"$account\"}" | base64 | tr -d \\n)
+  peer chaincode invoke -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc \
+    -c  '{"Args":["ProcessLeaseRenewal"]}' --transient "{\"renewal\":\"$RENEWAL\"}"
+}
+
+ProposeTransfer() {
+  setUser $1
+  asset=$2
+  to=$3
+  export TRANSFER=$(echo -n "{\"asset_id\":\"10$asset\",\"licenses\":[\"asset1-license-1\"],\"to\":\"$to\"}" | base64 | tr -d \\n)
+  peer chaincode invoke -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc \
+    -c  '{"Args":["ProposeTransfer"]}' --transient "{\"transfer\":\"$TRANSFER\"}"
+}
+
+AcceptTransfer() {
+  setUser $1
+  id=$2
+  peer chaincode invoke -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc \
+    -c '{"Args":["AcceptTransfer", "'"$id"'"]}'
+}
+
+ApproveTransfer() {
+  setUser $1
+  id=$2
+  peer chaincode invoke -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc \
+    -c '{"Args":["ApproveTransfer", "'"$id"'"]}'
+}
+
+CancelTransfer() {
+  setUser $1
+  id=$2
+  reason=$3
+  peer chaincode invoke -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc \
+    -c '{"Args":["CancelTransfer", "'"$id"'", "'"$reason"'"]}'
+}
+
+Transfers() {
+  setUser $1
+  account=$2
+  peer chaincode query -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc \
+    -c '{"Args":["GetTransfers", "'"$account"'"]}'
+}
+
+PostOffer() {
+  setUser $1
+  asset=$2
+  until=$3
+  export OFFER=$(echo -n "{\"asset_id\":\"10$asset\",\"licenses\":[\"asset1-license-2\"],\"available_until\":\"$until\"}" | base64 | tr -d \\n)
+  peer chaincode invoke -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc \
+    -c  '{"Args":["PostOffer"]}' --transient "{\"offer\":\"$OFFER\"}"
+}
+
+Offers() {
+  setUser $1
+  peer chaincode query -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc \
+    -c '{"Args":["GetOffers"]}'
+}
+
+ClaimOffer() {
+  setUser $1
+  id=$2
+  term_days=$3
+  peer chaincode invoke -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc \
+    -c '{"Args":["ClaimOffer", "'"$id"'", "'"$term_days"'"]}'
+}
+
+ProcessOfferClaim() {
+  setUser $1
+  id=$2
+  peer chaincode invoke -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc \
+    -c '{"Args":["ProcessOfferClaim", "'"$id"'"]}'
+}
+
+WithdrawOffer() {
+  setUser $1
+  id=$2
+  reason=$3
+  peer chaincode invoke -o localhost:7050 --ordererTLSHostnameOverride orderer.example.com \
+    --tls --cafile ${PWD}/organizations/ordererOrganizations/example.com/orderers/orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem \
+    -C mychannel -n blossomcc \
+    -c '{"Args":["WithdrawOffer", "'"$id"'", "'"$reason"'"]}'
+}
+
 Licenses() {
   setUser $1
   account=$2
@@ -324,13 +734,17 @@ if [ "$func" == "InitNGAC" ]; then
   InitNGAC $2
 elif [ "$func" == "OnboardAsset" ]; then
   OnboardAsset $2 $3
+elif [ "$func" == "AddLicenses" ]; then
+  AddLicenses $2 $3
+elif [ "$func" == "RetireLicenses" ]; then
+  RetireLicenses $2 $3
 elif [ "$func" == "Assets" ]; then
   Assets $2 | python -m json.tool
 elif [ "$func" == "AssetInfo" ]; then
   AssetInfo $2 $3 | python -m json.tool
 elif [ "$func" == "RequestAccount" ]; then
-  # user, system owner, system admin, acq spec
-  RequestAccount $2 $3 $4 $5
+  # user, system owner, system admin, acq spec, department, sub-agency
+  RequestAccount $2 $3 $4 $5 "$6" "$7"
 elif [ "$func" == "ApproveOrg2Account" ]; then
   ApproveOrg2Account $2
 elif [ "$func" == "ApproveOrg3Account" ]; then
@@ -339,14 +753,83 @@ elif [ "$func" == "UploadATOOrg2" ]; then
   UploadATOOrg2 $2
 elif [ "$func" == "UploadATOOrg3" ]; then
   UploadATOOrg3 $2
+elif [ "$func" == "RegisterEncryptionKey" ]; then
+  # user, public key PEM file
+  RegisterEncryptionKey $2 $3
+elif [ "$func" == "SealLicenseKeys" ]; then
+  # user, account
+  SealLicenseKeys $2 $3
+elif [ "$func" == "PublishROB" ]; then
+  # user, version, digest, uri, grace period days
+  PublishROB $2 $3 $4 "$5" $6
+elif [ "$func" == "AcceptROB" ]; then
+  # user, version, digest
+  AcceptROB $2 $3 $4
+elif [ "$func" == "EnforceROB" ]; then
+  EnforceROB $2
+elif [ "$func" == "SweepExpiredATOs" ]; then
+  SweepExpiredATOs $2
+elif [ "$func" == "MigrateDates" ]; then
+  MigrateDates $2
+elif [ "$func" == "SweepExpiredLicenses" ]; then
+  SweepExpiredLicenses $2
+elif [ "$func" == "LicenseRollup" ]; then
+  LicenseRollup $2 | python -m json.tool
+elif [ "$func" == "ExpiringAssets" ]; then
+  # user, days
+  ExpiringAssets $2 $3 | python -m json.tool
+elif [ "$func" == "UpdateAssetCost" ]; then
+  # user, asset, yearly unit cost in cents
+  UpdateAssetCost $2 $3 $4
+elif [ "$func" == "UpdateAllocationStrategy" ]; then
+  # user, asset, strategy (POOL_ORDER, SOONEST_EXPIRING, LATEST_EXPIRING, SAME_BATCH or EXPLICIT)
+  UpdateAllocationStrategy $2 $3 $4
+elif [ "$func" == "ForceOffboardAsset" ]; then
+  # user, asset, reason
+  ForceOffboardAsset $2 $3 "$4"
+elif [ "$func" == "Chargebacks" ]; then
+  # user, account
+  Chargebacks $2 $3 | python -m json.tool
+elif [ "$func" == "CostAllocation" ]; then
+  # user, fiscal period (e.g. FY2027Q1, empty for all)
+  CostAllocation $2 "$3" | python -m json.tool
+elif [ "$func" == "RequestAcquisition" ]; then
+  # user, product, quantity, justification
+  RequestAcquisition $2 "$3" $4 "$5"
+elif [ "$func" == "AttachQuote" ]; then
+  # user, acquisition id, vendor, quote sha256 digest, amount in cents
+  AttachQuote $2 $3 "$4" $5 $6
+elif [ "$func" == "ApproveAcquisition" ]; then
+  # user, account, acquisition id
+  ApproveAcquisition $2 $3 $4
+elif [ "$func" == "RecordPurchase" ]; then
+  # user, acquisition id, order number, quote sha256 digest
+  RecordPurchase $2 $3 $4 $5
+elif [ "$func" == "OnboardAcquisition" ]; then
+  # user, account, acquisition id, asset id, asset name
+  OnboardAcquisition $2 $3 $4 $5 "$6"
+elif [ "$func" == "Acquisitions" ]; then
+  # user, account
+  Acquisitions $2 $3 | python -m json.tool
+elif [ "$func" == "SubmitForecast" ]; then
+  # user, product, quantity, fiscal year, justification
+  SubmitForecast $2 "$3" $4 $5 "$6"
+elif [ "$func" == "Forecasts" ]; then
+  # user, account
+  Forecasts $2 $3 | python -m json.tool
+elif [ "$func" == "Demand" ]; then
+  # user, fiscal year (0 for all)
+  Demand $2 $3 | python -m json.tool
 elif [ "$func" == "Accounts" ]; then
   Accounts $2 | python -m json.tool
 elif [ "$func" == "Account" ]; then
   Account $2 $3 | python -m json.tool
 elif [ "$func" == "UpdateOrg2Status" ]; then
-  UpdateOrg2Status $2 $3
+  # user, status, reason
+  UpdateOrg2Status $2 $3 "$4"
 elif  [ "$func" == "UpdateOrg3Status" ]; then
-  UpdateOrg3Status $2 $3
+  # user, status, reason
+  UpdateOrg3Status $2 $3 "$4"
 elif [ "$func" == "Org2RequestCheckout" ]; then
   Org2RequestCheckout $2 $3 $4
 elif [ "$func" == "Org3RequestCheckout" ]; then
@@ -354,13 +837,56 @@ elif [ "$func" == "Org3RequestCheckout" ]; then
 elif [ "$func" == "CheckoutRequests" ]; then
   CheckoutRequests $2 $3
 elif [ "$func" == "ApproveCheckout" ]; then
-  ApproveCheckout $2 $3 $4
+  # user, account, asset, approved amount (optional), waitlist priority (optional)
+  ApproveCheckout $2 $3 $4 $5 $6
+elif [ "$func" == "Waitlist" ]; then
+  # user, asset
+  Waitlist $2 $3 | python -m json.tool
+elif [ "$func" == "DenyCheckout" ]; then
+  # user, account, asset, reason
+  DenyCheckout $2 $3 $4 "$5"
+elif [ "$func" == "CancelCheckout" ]; then
+  # user, asset, reason
+  CancelCheckout $2 $3 "$4"
 elif [ "$func" == "InitiateCheckin" ]; then
   InitiateCheckin $2 $3 | python -m json.tool
 elif [ "$func" == "InitiatedCheckins" ]; then
   InitiatedCheckins $2 $3 $4
 elif [ "$func" == "ProcessCheckin" ]; then
   ProcessCheckin $2 $3 $4
+elif [ "$func" == "RequestLeaseRenewal" ]; then
+  RequestLeaseRenewal $2 $3 $4
+elif [ "$func" == "ProcessLeaseRenewal" ]; then
+  ProcessLeaseRenewal $2 $3 $4
+elif [ "$func" == "ProposeTransfer" ]; then
+  # user, asset, to account
+  ProposeTransfer $2 $3 $4
+elif [ "$func" == "AcceptTransfer" ]; then
+  # user, transfer id
+  AcceptTransfer $2 $3
+elif [ "$func" == "ApproveTransfer" ]; then
+  # user, transfer id
+  ApproveTransfer $2 $3
+elif [ "$func" == "CancelTransfer" ]; then
+  # user, transfer id, reason
+  CancelTransfer $2 $3 "$4"
+elif [ "$func" == "Transfers" ]; then
+  # user, account
+  Transfers $2 $3 | python -m json.tool
+elif [ "$func" == "PostOffer" ]; then
+  # user, asset, available until
+  PostOffer $2 $3 $4
+elif [ "$func" == "Offers" ]; then
+  Offers $2 | python -m json.tool
+elif [ "$func" == "ClaimOffer" ]; then
+  # user, offer id, lease term days
+  ClaimOffer $2 $3 $4
+elif [ "$func" == "ProcessOfferClaim" ]; then
+  # user, offer id
+  ProcessOfferClaim $2 $3
+elif [ "$func" == "WithdrawOffer" ]; then
+  # user, offer id, reason
+  WithdrawOffer $2 $3 "$4"
 elif [ "$func" == "Licenses" ]; then
   Licenses $2 $3 $4 | python -m json.tool
 elif [ "$func" == "ReportSwID" ]; then
@@ -0,0 +1,209 @@
+// Package seal encrypts license keys to an account's RSA public key so that only the account can read them.  A sealed
+// value is an AES-256-GCM ciphertext whose key is wrapped with RSA-OAEP (SHA-256).  The label, i.e. the license ID, is
+// bound to both so a sealed key cannot be moved to another license.
+//
+// Every endorsing peer must produce the same write set, so the chaincode seals with a deterministic random reader
+// derived from secret and per-transaction inputs instead of crypto/rand.
+package seal
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"io"
+)
+
+// MinKeyBits is the smallest RSA modulus accepted for an encryption key.
+const MinKeyBits = 2048
+
+const keySize = 32
+
+// ParsePublicKey parses a PEM encoded PKIX or PKCS #1 RSA public key of at least MinKeyBits bits.
+func ParsePublicKey(pemKey string) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(pemKey))
+	if block == nil {
+		return nil, fmt.Errorf("encryption key is not PEM encoded")
+	}
+
+	var (
+		pub *rsa.PublicKey
+		ok  bool
+	)
+
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing encryption key: %w", err)
+		}
+
+		if pub, ok = key.(*rsa.PublicKey); !ok {
+			return nil, fmt.Errorf("encryption key is not an RSA key")
+		}
+	case "RSA PUBLIC KEY":
+		key, err := x509.ParsePKCS1PublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing encryption key: %w", err)
+		}
+
+		pub = key
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+
+	if pub.N.BitLen() < MinKeyBits {
+		return nil, fmt.Errorf("encryption key must be at least %d bits", MinKeyBits)
+	}
+
+	return pub, nil
+}
+
+// ParsePrivateKey parses a PEM encoded PKCS #8 or PKCS #1 RSA private key.
+func ParsePrivateKey(pemKey []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(pemKey)
+	if block == nil {
+		return nil, fmt.Errorf("private key is not PEM encoded")
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing private key: %w", err)
+		}
+
+		priv, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("private key is not an RSA key")
+		}
+
+		return priv, nil
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+}
+
+// Seal encrypts the plaintext to the public key and returns the base64 encoded wrapped key, nonce and ciphertext.
+func Seal(pub *rsa.PublicKey, plaintext, label []byte, random io.Reader) (string, error) {
+	key := make([]byte, keySize)
+	if _, err := io.ReadFull(random, key); err != nil {
+		return "", fmt.Errorf("error generating key: %w", err)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(random, nonce); err != nil {
+		return "", fmt.Errorf("error generating nonce: %w", err)
+	}
+
+	wrapped, err := rsa.EncryptOAEP(sha256.New(), random, pub, key, label)
+	if err != nil {
+		return "", fmt.Errorf("error wrapping key: %w", err)
+	}
+
+	sealed := append(wrapped, nonce...)
+	sealed = gcm.Seal(sealed, nonce, plaintext, label)
+
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Open decrypts a value sealed to the public key of the private key with the same label.
+func Open(priv *rsa.PrivateKey, sealed string, label []byte) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding sealed value: %w", err)
+	}
+
+	size := priv.PublicKey.Size()
+	if len(data) < size {
+		return nil, fmt.Errorf("sealed value is too short")
+	}
+
+	key, err := rsa.DecryptOAEP(sha256.New(), nil, priv, data[:size], label)
+	if err != nil {
+		return nil, fmt.Errorf("error unwrapping key: %w", err)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	data = data[size:]
+	if len(data) < gcm.NonceSize() {
+		return nil, fmt.Errorf("sealed value is too short")
+	}
+
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], label)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting sealed value: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("error creating cipher: %w", err)
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// deterministicReader is an unbounded stream of SHA-256(seed || counter) blocks.
+type deterministicReader struct {
+	seed    [sha256.Size]byte
+	counter uint64
+	buf     []byte
+}
+
+// NewDeterministicReader returns a random reader that always yields the same bytes for the same seed parts.  The
+// parts are length prefixed before they are hashed so they cannot be shifted into each other.  At least one part must
+// be secret for the output to be unpredictable.
+func NewDeterministicReader(parts ...[]byte) io.Reader {
+	h := sha256.New()
+	for _, part := range parts {
+		var length [8]byte
+		binary.BigEndian.PutUint64(length[:], uint64(len(part)))
+		h.Write(length[:])
+		h.Write(part)
+	}
+
+	r := &deterministicReader{}
+	copy(r.seed[:], h.Sum(nil))
+	return r
+}
+
+func (r *deterministicReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(r.buf) == 0 {
+			var block [sha256.Size + 8]byte
+			copy(block[:], r.seed[:])
+			binary.BigEndian.PutUint64(block[sha256.Size:], r.counter)
+			r.counter++
+
+			sum := sha256.Sum256(block[:])
+			r.buf = sum[:]
+		}
+
+		copied := copy(p[n:], r.buf)
+		r.buf = r.buf[copied:]
+		n += copied
+	}
+
+	return n, nil
+}
@@ -0,0 +1,68 @@
+package seal
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeal(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, MinKeyBits)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	require.NoError(t, err)
+	pub, err := ParsePublicKey(string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})))
+	require.NoError(t, err)
+
+	t.Run("test seal and open", func(t *testing.T) {
+		sealed, err := Seal(pub, []byte("XXXX-YYYY-ZZZZ"), []byte("l1"), NewDeterministicReader([]byte("tx1"), []byte("secret")))
+		require.NoError(t, err)
+
+		plaintext, err := Open(priv, sealed, []byte("l1"))
+		require.NoError(t, err)
+		require.Equal(t, "XXXX-YYYY-ZZZZ", string(plaintext))
+
+		// the label is bound to the sealed value
+		_, err = Open(priv, sealed, []byte("l2"))
+		require.Error(t, err)
+	})
+
+	t.Run("test deterministic", func(t *testing.T) {
+		a, err := Seal(pub, []byte("key"), []byte("l1"), NewDeterministicReader([]byte("tx1"), []byte("secret")))
+		require.NoError(t, err)
+		b, err := Seal(pub, []byte("key"), []byte("l1"), NewDeterministicReader([]byte("tx1"), []byte("secret")))
+		require.NoError(t, err)
+		require.Equal(t, a, b)
+
+		c, err := Seal(pub, []byte("key"), []byte("l1"), NewDeterministicReader([]byte("tx2"), []byte("secret")))
+		require.NoError(t, err)
+		require.NotEqual(t, a, c)
+
+		// parts are length prefixed
+		d, err := Seal(pub, []byte("key"), []byte("l1"), NewDeterministicReader([]byte("tx1s"), []byte("ecret")))
+		require.NoError(t, err)
+		require.NotEqual(t, a, d)
+	})
+
+	t.Run("test parse keys", func(t *testing.T) {
+		_, err := ParsePublicKey("not a key")
+		require.Error(t, err)
+
+		small, err := rsa.GenerateKey(rand.Reader, 1024)
+		require.NoError(t, err)
+		der := x509.MarshalPKCS1PublicKey(&small.PublicKey)
+		_, err = ParsePublicKey(string(pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: der})))
+		require.Error(t, err)
+
+		der, err = x509.MarshalPKCS8PrivateKey(priv)
+		require.NoError(t, err)
+		parsed, err := ParsePrivateKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+		require.NoError(t, err)
+		require.True(t, parsed.Equal(priv))
+	})
+}